@@ -17,8 +17,24 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/chaos-mesh/chaosd/pkg/core"
+	"github.com/chaos-mesh/chaosd/pkg/server/chaosd"
+	"github.com/chaos-mesh/chaosd/pkg/utils"
 )
 
+var (
+	// dryRun, labels, annotations and probes are shared by all the attack commands
+	dryRun      bool
+	labels      map[string]string
+	annotations map[string]string
+	probes      []string
+)
+
+// commonConfig is implemented by the configs which embed core.CommonAttackConfig
+type commonConfig interface {
+	SetLabels(labels, annotations map[string]string)
+	AddProbes(probes []core.Probe)
+}
+
 func NewAttackCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "attack <subcommand>",
@@ -27,27 +43,59 @@ func NewAttackCommand() *cobra.Command {
 
 	var uid string
 	cmd.PersistentFlags().StringVarP(&uid, "uid", "", "", "the experiment ID")
+	cmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "only print what the attack will do, without executing it")
+	cmd.PersistentFlags().StringToStringVar(&labels, "label", nil,
+		"labels of the experiment, such as --label team=payments --label gameday=2023-q3, they can be used to search and recover experiments")
+	cmd.PersistentFlags().StringToStringVar(&annotations, "annotation", nil, "annotations of the experiment, such as --annotation owner=alice")
+	cmd.PersistentFlags().StringArrayVar(&probes, "probe", nil,
+		`steady-state probe of the experiment, the experiment is aborted if the probe fails while the attack is running. `+
+			`It can be a JSON object or key=value pairs, such as --probe type=http,url=http://127.0.0.1:8080/health,max_latency=200ms,tolerance=2 `+
+			`or --probe type=tcp,address=127.0.0.1:3306 or --probe type=command,interval=5s,command="pgrep mysqld"`)
 
-	cmd.AddCommand(
-		NewProcessAttackCommand(&uid),
-		NewNetworkAttackCommand(&uid),
-		NewStressAttackCommand(&uid),
-		NewDiskAttackCommand(&uid),
-		NewHostAttackCommand(&uid),
-		NewJVMAttackCommand(&uid),
-		NewClockAttackCommand(&uid),
-		NewKafkaAttackCommand(&uid),
-		NewRedisAttackCommand(&uid),
-		NewFileAttackCommand(&uid),
-		NewHTTPAttackCommand(&uid),
-		NewVMAttackCommand(&uid),
-		NewUserDefinedCommand(&uid),
-	)
+	for _, kind := range chaosd.GetAttackKinds() {
+		if kind.Command != nil {
+			cmd.AddCommand(kind.Command(&uid))
+		}
+	}
+	cmd.AddCommand(NewUpdateCommand())
 
 	return cmd
 }
 
 func SetScheduleFlags(cmd *cobra.Command, conf *core.SchedulerConfig) {
 	cmd.Flags().StringVar(&conf.Duration, "duration", "",
-		`Work duration of attacks.A duration string is a possibly signed sequence of decimal numbers, each with optional fraction and a unit suffix, such as "300ms", "1.5h" or "2h45m".Valid time units are "ns", "us" (or "µs"), "ms", "s", "m", "h".`)
+		`Work duration of attacks, the attack will be recovered automatically after the duration.A duration string is a possibly signed sequence of decimal numbers, each with optional fraction and a unit suffix, such as "300ms", "1.5h" or "2h45m".Valid time units are "ns", "us" (or "µs"), "ms", "s", "m", "h".`)
+}
+
+// executeAttack executes the attack in command mode, or prints the plan of the attack and exits if --dry-run is set.
+func executeAttack(chaos *chaosd.Server, attackType chaosd.AttackType, options core.AttackConfig) (string, error) {
+	parsedProbes := make([]core.Probe, 0, len(probes))
+	for _, p := range probes {
+		probe, err := core.ParseProbe(p)
+		if err != nil {
+			utils.ExitWithError(utils.ExitBadArgs, err)
+		}
+		if err := probe.Validate(); err != nil {
+			utils.ExitWithError(utils.ExitBadArgs, err)
+		}
+		parsedProbes = append(parsedProbes, probe)
+	}
+	if config, ok := options.(commonConfig); ok {
+		config.SetLabels(labels, annotations)
+		config.AddProbes(parsedProbes)
+	}
+	if err := core.ValidateLabels(options.GetLabels()); err != nil {
+		utils.ExitWithError(utils.ExitBadArgs, err)
+	}
+
+	if !dryRun && !options.IsDryRun() {
+		return chaos.ExecuteAttack(attackType, options, core.CommandMode)
+	}
+
+	plan, err := chaos.PlanAttack(attackType, options)
+	if err != nil {
+		utils.ExitWithError(utils.ExitError, err)
+	}
+	utils.NormalExit(plan.String())
+	return "", nil
 }
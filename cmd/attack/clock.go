@@ -25,6 +25,10 @@ import (
 	"github.com/chaos-mesh/chaosd/pkg/utils"
 )
 
+func init() {
+	chaosd.AttachAttackCommand(core.ClockAttack, NewClockAttackCommand)
+}
+
 func NewClockAttackCommand(uid *string) *cobra.Command {
 	options := core.NewClockOption()
 	dep := fx.Options(
@@ -50,6 +54,7 @@ func NewClockAttackCommand(uid *string) *cobra.Command {
 		"The identifier of the particular clock on which to act."+
 			"More clock description in linux kernel can be found in man page of clock_getres, clock_gettime, clock_settime."+
 			"Muti clock ids should be split with \",\"")
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 	return cmd
 }
 
@@ -59,7 +64,7 @@ func processClockAttack(options *core.ClockOption, chaos *chaosd.Server) {
 		utils.ExitWithError(utils.ExitBadArgs, err)
 	}
 
-	uid, err := chaos.ExecuteAttack(chaosd.ClockAttack, options, core.CommandMode)
+	uid, err := executeAttack(chaos, chaosd.ClockAttack, options)
 	if err != nil {
 		utils.ExitWithError(utils.ExitError, err)
 	}
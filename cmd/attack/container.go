@@ -0,0 +1,90 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attack
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+
+	"github.com/chaos-mesh/chaosd/cmd/server"
+	"github.com/chaos-mesh/chaosd/pkg/core"
+	"github.com/chaos-mesh/chaosd/pkg/server/chaosd"
+	"github.com/chaos-mesh/chaosd/pkg/utils"
+)
+
+func init() {
+	chaosd.AttachAttackCommand(core.ContainerAttack, NewContainerAttackCommand)
+}
+
+func NewContainerAttackCommand(uid *string) *cobra.Command {
+	options := core.NewContainerCommand()
+	dep := fx.Options(
+		server.Module,
+		fx.Provide(func() *core.ContainerCommand {
+			options.UID = *uid
+			return options
+		}),
+	)
+
+	cmd := &cobra.Command{
+		Use:   "container <subcommand>",
+		Short: "Container attack related commands",
+	}
+
+	cmd.AddCommand(
+		NewContainerActionCommand(dep, options, core.ContainerKillAction, "kill the containers with SIGKILL"),
+		NewContainerActionCommand(dep, options, core.ContainerStopAction, "stop the containers"),
+		NewContainerActionCommand(dep, options, core.ContainerRestartAction, "restart the containers"),
+		NewContainerActionCommand(dep, options, core.ContainerPauseAction, "pause the containers, they are unpaused when recovering"),
+		NewContainerActionCommand(dep, options, core.ContainerUnpauseAction, "unpause the paused containers, they are paused again when recovering"),
+	)
+
+	return cmd
+}
+
+func NewContainerActionCommand(dep fx.Option, options *core.ContainerCommand, action, short string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   action,
+		Short: short,
+		Run: func(*cobra.Command, []string) {
+			options.Action = action
+			utils.FxNewAppWithoutLog(dep, fx.Invoke(containerAttackF)).Run()
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&options.ContainerIDs, "container-id", nil, "The IDs of the containers with the runtime prefix, "+
+		"such as docker://<id> or containerd://<id>")
+	cmd.Flags().StringVar(&options.ContainerName, "container-name", "", "Select the running containers by the name")
+	cmd.Flags().StringToStringVar(&options.ContainerLabels, "container-label", nil, "Select the running containers by the labels, "+
+		"such as --container-label app=nginx")
+	server.AddRuntimeFlag(cmd)
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
+
+	return cmd
+}
+
+func containerAttackF(options *core.ContainerCommand, chaos *chaosd.Server) {
+	if err := options.Validate(); err != nil {
+		utils.ExitWithError(utils.ExitBadArgs, err)
+	}
+
+	uid, err := executeAttack(chaos, chaosd.ContainerAttack, options)
+	if err != nil {
+		utils.ExitWithError(utils.ExitError, err)
+	}
+
+	utils.NormalExit(fmt.Sprintf("Attack container %s successfully, uid: %s", options.Selector(), uid))
+}
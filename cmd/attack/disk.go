@@ -25,6 +25,10 @@ import (
 	"github.com/chaos-mesh/chaosd/pkg/utils"
 )
 
+func init() {
+	chaosd.AttachAttackCommand(core.DiskAttack, NewDiskAttackCommand)
+}
+
 func NewDiskAttackCommand(uid *string) *cobra.Command {
 	options := core.NewDiskOption()
 	dep := fx.Options(
@@ -129,6 +133,7 @@ func NewDiskFillCommand(dep fx.Option, options *core.DiskOption) *cobra.Command
 	cmd.Flags().StringVarP(&options.Percent, "percent", "c", "",
 		"'percent' how many percent data of disk will fill in the file path")
 	cmd.Flags().BoolVarP(&options.FillByFallocate, "fallocate", "f", true, "fill disk by fallocate instead of dd")
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 	return cmd
 }
 
@@ -138,7 +143,7 @@ func processDiskAttack(options *core.DiskOption, chaos *chaosd.Server) {
 		utils.ExitWithError(utils.ExitBadArgs, err)
 	}
 
-	uid, err := chaos.ExecuteAttack(chaosd.DiskAttack, attackConfig, core.CommandMode)
+	uid, err := executeAttack(chaos, chaosd.DiskAttack, attackConfig)
 	if err != nil {
 		utils.ExitWithError(utils.ExitError, err)
 	}
@@ -25,6 +25,10 @@ import (
 	"github.com/chaos-mesh/chaosd/pkg/utils"
 )
 
+func init() {
+	chaosd.AttachAttackCommand(core.FileAttack, NewFileAttackCommand)
+}
+
 func NewFileAttackCommand(uid *string) *cobra.Command {
 	options := core.NewFileCommand()
 	dep := fx.Options(
@@ -66,6 +70,7 @@ func NewFileCreateCommand(dep fx.Option, options *core.FileCommand) *cobra.Comma
 
 	cmd.Flags().StringVarP(&options.FileName, "file-name", "f", "", "the name of file to be created")
 	cmd.Flags().StringVarP(&options.DirName, "dir-name", "d", "", "the name of directory to be created")
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 
 	return cmd
 }
@@ -82,6 +87,7 @@ func NewFileModifyPrivilegeCommand(dep fx.Option, options *core.FileCommand) *co
 
 	cmd.Flags().StringVarP(&options.FileName, "file-name", "f", "", "file to be change privilege")
 	cmd.Flags().Uint32VarP(&options.Privilege, "privilege", "p", 0, "privilege to be update")
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 
 	return cmd
 }
@@ -100,6 +106,7 @@ func NewFileDeleteCommand(dep fx.Option, options *core.FileCommand) *cobra.Comma
 
 	cmd.Flags().StringVarP(&options.FileName, "file-name", "f", "", "the file to be deleted")
 	cmd.Flags().StringVarP(&options.DirName, "dir-name", "d", "", "the directory to be deleted")
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 
 	return cmd
 }
@@ -117,6 +124,7 @@ func NewFileRenameCommand(dep fx.Option, options *core.FileCommand) *cobra.Comma
 
 	cmd.Flags().StringVarP(&options.SourceFile, "source-file", "s", "", "the source file/dir of rename")
 	cmd.Flags().StringVarP(&options.DestFile, "dest-file", "d", "", "the destination file/dir of rename")
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 
 	return cmd
 }
@@ -135,6 +143,7 @@ func NewFileAppendCommand(dep fx.Option, options *core.FileCommand) *cobra.Comma
 	cmd.Flags().StringVarP(&options.FileName, "file-name", "f", "", "append data to the file")
 	cmd.Flags().StringVarP(&options.Data, "data", "d", "", "append data")
 	cmd.Flags().IntVarP(&options.Count, "count", "c", 1, "append count with default value is 1")
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 
 	return cmd
 }
@@ -154,6 +163,7 @@ func NewFileReplaceCommand(dep fx.Option, options *core.FileCommand) *cobra.Comm
 	cmd.Flags().StringVarP(&options.OriginStr, "origin-string", "o", "", "the origin string to be replaced")
 	cmd.Flags().StringVarP(&options.DestStr, "dest-string", "d", "", "the destination string to replace the origin string")
 	cmd.Flags().IntVarP(&options.Line, "line", "l", 0, "the line number to replace, default is 0, means replace all lines")
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 
 	return cmd
 }
@@ -163,7 +173,7 @@ func commonFileAttackFunc(options *core.FileCommand, chaos *chaosd.Server) {
 		utils.ExitWithError(utils.ExitBadArgs, err)
 	}
 
-	uid, err := chaos.ExecuteAttack(chaosd.FileAttack, options, core.CommandMode)
+	uid, err := executeAttack(chaos, chaosd.FileAttack, options)
 	if err != nil {
 		utils.ExitWithError(utils.ExitError, err)
 	}
@@ -25,6 +25,10 @@ import (
 	"github.com/chaos-mesh/chaosd/pkg/utils"
 )
 
+func init() {
+	chaosd.AttachAttackCommand(core.HostAttack, NewHostAttackCommand)
+}
+
 func NewHostAttackCommand(uid *string) *cobra.Command {
 	options := core.NewHostCommand()
 	dep := fx.Options(
@@ -79,7 +83,7 @@ func hostAttackF(chaos *chaosd.Server, options *core.HostCommand) {
 		utils.ExitWithError(utils.ExitBadArgs, err)
 	}
 
-	uid, err := chaos.ExecuteAttack(chaosd.HostAttack, options, core.CommandMode)
+	uid, err := executeAttack(chaos, chaosd.HostAttack, options)
 	if err != nil {
 		utils.ExitWithError(utils.ExitError, err)
 	}
@@ -25,6 +25,10 @@ import (
 	"github.com/chaos-mesh/chaosd/pkg/utils"
 )
 
+func init() {
+	chaosd.AttachAttackCommand(core.HTTPAttack, NewHTTPAttackCommand)
+}
+
 func NewHTTPAttackCommand(uid *string) *cobra.Command {
 	option := core.NewHTTPAttackOption()
 	dep := fx.Options(
@@ -63,6 +67,7 @@ func NewHTTPAbortCommand(dep fx.Option, o *core.HTTPAttackOption) *cobra.Command
 
 	setTarget(cmd, o)
 	setSelector(cmd, o)
+	SetScheduleFlags(cmd, &o.SchedulerConfig)
 	return cmd
 }
 
@@ -80,6 +85,7 @@ func NewHTTPDelayCommand(dep fx.Option, o *core.HTTPAttackOption) *cobra.Command
 	setSelector(cmd, o)
 
 	cmd.Flags().StringVarP(&o.Delay, "delay time", "d", "", "Delay represents the delay of the target request/response.")
+	SetScheduleFlags(cmd, &o.SchedulerConfig)
 	return cmd
 }
 
@@ -109,6 +115,7 @@ func NewHTTPConfigCommand(dep fx.Option, o *core.HTTPAttackOption) *cobra.Comman
 		},
 	}
 	cmd.Flags().StringVarP(&o.FilePath, "file path", "p", "", "Config file path.")
+	SetScheduleFlags(cmd, &o.SchedulerConfig)
 	return cmd
 }
 
@@ -125,6 +132,7 @@ func NewHTTPRequestCommand(dep fx.Option, o *core.HTTPAttackOption) *cobra.Comma
 	cmd.Flags().StringVarP(&o.HTTPRequestConfig.URL, "url", "", "", "Request to send")
 	cmd.Flags().IntVarP(&o.HTTPRequestConfig.Count, "count", "c", 1, "Number of requests to send")
 	cmd.Flags().BoolVarP(&o.HTTPRequestConfig.EnableConnPool, "enable-conn-pool", "p", false, "Enable connection pool")
+	SetScheduleFlags(cmd, &o.SchedulerConfig)
 	return cmd
 }
 
@@ -134,7 +142,7 @@ func processHTTPAttack(o *core.HTTPAttackOption, chaos *chaosd.Server) {
 		utils.ExitWithError(utils.ExitBadArgs, err)
 	}
 
-	uid, err := chaos.ExecuteAttack(chaosd.HTTPAttack, attackConfig, core.CommandMode)
+	uid, err := executeAttack(chaos, chaosd.HTTPAttack, attackConfig)
 	if err != nil {
 		utils.ExitWithError(utils.ExitError, err)
 	}
@@ -25,6 +25,10 @@ import (
 	"github.com/chaos-mesh/chaosd/pkg/utils"
 )
 
+func init() {
+	chaosd.AttachAttackCommand(core.JVMAttack, NewJVMAttackCommand)
+}
+
 func NewJVMAttackCommand(uid *string) *cobra.Command {
 	options := core.NewJVMCommand()
 	dep := fx.Options(
@@ -69,6 +73,7 @@ func NewJVMLatencyCommand(dep fx.Option, options *core.JVMCommand) *cobra.Comman
 	cmd.Flags().StringVarP(&options.Class, "class", "c", "", "Java class name")
 	cmd.Flags().StringVarP(&options.Method, "method", "m", "", "the method name in Java class")
 	cmd.Flags().IntVarP(&options.LatencyDuration, "latency", "", 0, "the latency duration, unit ms")
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 
 	return cmd
 }
@@ -86,6 +91,7 @@ func NewJVMReturnCommand(dep fx.Option, options *core.JVMCommand) *cobra.Command
 	cmd.Flags().StringVarP(&options.Class, "class", "c", "", "Java class name")
 	cmd.Flags().StringVarP(&options.Method, "method", "m", "", "the method name in Java class")
 	cmd.Flags().StringVarP(&options.ReturnValue, "value", "", "", "the return value for action 'return'. Only supports number and string types.")
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 
 	return cmd
 }
@@ -103,6 +109,7 @@ func NewJVMExceptionCommand(dep fx.Option, options *core.JVMCommand) *cobra.Comm
 	cmd.Flags().StringVarP(&options.Class, "class", "c", "", "Java class name")
 	cmd.Flags().StringVarP(&options.Method, "method", "m", "", "the method name in Java class")
 	cmd.Flags().StringVarP(&options.ThrowException, "exception", "", "", "the exception which needs to throw for action 'exception'")
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 
 	return cmd
 }
@@ -119,6 +126,7 @@ func NewJVMStressCommand(dep fx.Option, options *core.JVMCommand) *cobra.Command
 
 	cmd.Flags().IntVarP(&options.CPUCount, "cpu-count", "", 0, "the CPU core number")
 	cmd.Flags().StringVarP(&options.MemoryType, "mem-type", "", "", "the memory type to be allocated. The value can be 'stack' or 'heap'")
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 
 	return cmd
 }
@@ -132,6 +140,7 @@ func NewJVMGCCommand(dep fx.Option, options *core.JVMCommand) *cobra.Command {
 			utils.FxNewAppWithoutLog(dep, fx.Invoke(jvmCommandFunc)).Run()
 		},
 	}
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 
 	return cmd
 }
@@ -147,6 +156,7 @@ func NewJVMRuleFileCommand(dep fx.Option, options *core.JVMCommand) *cobra.Comma
 	}
 
 	cmd.Flags().StringVarP(&options.RuleFile, "path", "p", "", "the path of configured byteman rule file")
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 
 	return cmd
 }
@@ -167,6 +177,7 @@ func NewJVMMySQLCommand(dep fx.Option, options *core.JVMCommand) *cobra.Command
 	cmd.Flags().StringVarP(&options.MySQLConnectorVersion, "mysql-connector-version", "v", "8", "the version of mysql-connector-java, only support 5.X.X(set to 5) and 8.X.X(set to 8)")
 	cmd.Flags().StringVarP(&options.ThrowException, "exception", "", "", "the exception message needs to throw")
 	cmd.Flags().IntVarP(&options.LatencyDuration, "latency", "", 0, "the latency duration, unit ms")
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 
 	return cmd
 }
@@ -178,7 +189,7 @@ func jvmCommandFunc(options *core.JVMCommand, chaos *chaosd.Server) {
 		utils.ExitWithError(utils.ExitBadArgs, err)
 	}
 
-	uid, err := chaos.ExecuteAttack(chaosd.JVMAttack, options, core.CommandMode)
+	uid, err := executeAttack(chaos, chaosd.JVMAttack, options)
 	if err != nil {
 		utils.ExitWithError(utils.ExitError, err)
 	}
@@ -25,6 +25,10 @@ import (
 	"github.com/chaos-mesh/chaosd/pkg/utils"
 )
 
+func init() {
+	chaosd.AttachAttackCommand(core.NetworkAttack, NewNetworkAttackCommand)
+}
+
 func NewNetworkAttackCommand(uid *string) *cobra.Command {
 	options := core.NewNetworkCommand()
 	dep := fx.Options(
@@ -85,6 +89,8 @@ func NewNetworkDelayCommand(dep fx.Option, options *core.NetworkCommand) *cobra.
 	cmd.Flags().StringVarP(&options.IPProtocol, "protocol", "p", "",
 		"only impact traffic using this IP protocol, supported: tcp, udp, icmp, all")
 	cmd.Flags().StringVarP(&options.AcceptTCPFlags, "accept-tcp-flags", "", "", "only the packet which match the tcp flag can be accepted, others will be dropped. only set when the protocol is tcp.")
+	setNetworkNamespaceFlags(cmd, options)
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 
 	return cmd
 }
@@ -114,6 +120,8 @@ func NewNetworkLossCommand(dep fx.Option, options *core.NetworkCommand) *cobra.C
 	cmd.Flags().StringVarP(&options.Hostname, "hostname", "H", "", "only impact traffic to these hostnames")
 	cmd.Flags().StringVarP(&options.IPProtocol, "protocol", "p", "",
 		"only impact traffic using this IP protocol, supported: tcp, udp, icmp, all")
+	setNetworkNamespaceFlags(cmd, options)
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 
 	return cmd
 }
@@ -143,6 +151,8 @@ func NewNetworkCorruptCommand(dep fx.Option, options *core.NetworkCommand) *cobr
 	cmd.Flags().StringVarP(&options.Hostname, "hostname", "H", "", "only impact traffic to these hostnames")
 	cmd.Flags().StringVarP(&options.IPProtocol, "protocol", "p", "",
 		"only impact traffic using this IP protocol, supported: tcp, udp, icmp, all")
+	setNetworkNamespaceFlags(cmd, options)
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 
 	return cmd
 }
@@ -172,6 +182,8 @@ func NetworkDuplicateCommand(dep fx.Option, options *core.NetworkCommand) *cobra
 	cmd.Flags().StringVarP(&options.Hostname, "hostname", "H", "", "only impact traffic to these hostnames")
 	cmd.Flags().StringVarP(&options.IPProtocol, "protocol", "p", "",
 		"only impact traffic using this IP protocol, supported: tcp, udp, icmp, all")
+	setNetworkNamespaceFlags(cmd, options)
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 
 	return cmd
 }
@@ -195,6 +207,8 @@ func NetworkPartitionCommand(dep fx.Option, options *core.NetworkCommand) *cobra
 	cmd.Flags().StringVarP(&options.IPProtocol, "protocol", "p", "",
 		"only impact traffic using this IP protocol, supported: tcp, udp, icmp, all")
 	cmd.Flags().StringVarP(&options.AcceptTCPFlags, "accept-tcp-flags", "", "", "only the packet which match the tcp flag can be accepted, others will be dropped. only set when the protocol is tcp.")
+	setNetworkNamespaceFlags(cmd, options)
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 
 	return cmd
 }
@@ -215,6 +229,7 @@ func NetworkDNSCommand(dep fx.Option, options *core.NetworkCommand) *cobra.Comma
 		"update the DNS server in /etc/resolv.conf with this value")
 	cmd.Flags().StringVarP(&options.DNSDomainName, "dns-domain-name", "d", "", "map this host to specified IP")
 	cmd.Flags().StringVarP(&options.DNSIp, "dns-ip", "i", "", "map specified host to this IP address")
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 
 	return cmd
 }
@@ -239,16 +254,26 @@ func NewNetworkBandwidthCommand(dep fx.Option, options *core.NetworkCommand) *co
 	cmd.Flags().StringVarP(&options.Device, "device", "d", "", "the network interface to impact")
 	cmd.Flags().StringVarP(&options.IPAddress, "ip", "i", "", "only impact egress traffic to these IP addresses")
 	cmd.Flags().StringVarP(&options.Hostname, "hostname", "H", "", "only impact traffic to these hostnames")
+	setNetworkNamespaceFlags(cmd, options)
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 
 	return cmd
 }
 
+// setNetworkNamespaceFlags sets the flags selecting the network namespace of a container or a process,
+// the rules are injected in the network namespace of the host if neither is provided.
+func setNetworkNamespaceFlags(cmd *cobra.Command, options *core.NetworkCommand) {
+	cmd.Flags().StringVar(&options.ContainerID, "container-id", "", "only impact the network namespace of the container, "+
+		"the id should have the protocol prefix of the runtime, such as docker://<id> or containerd://<id>")
+	cmd.Flags().IntVar(&options.PID, "pid", 0, "only impact the network namespace of the process")
+}
+
 func commonNetworkAttackFunc(options *core.NetworkCommand, chaos *chaosd.Server) {
 	if err := options.Validate(); err != nil {
 		utils.ExitWithError(utils.ExitBadArgs, err)
 	}
 
-	uid, err := chaos.ExecuteAttack(chaosd.NetworkAttack, options, core.CommandMode)
+	uid, err := executeAttack(chaos, chaosd.NetworkAttack, options)
 	if err != nil {
 		utils.ExitWithError(utils.ExitError, err)
 	}
@@ -269,6 +294,7 @@ func NewNetworkPortOccupiedCommand(dep fx.Option, options *core.NetworkCommand)
 	}
 
 	cmd.Flags().StringVarP(&options.Port, "port", "p", "", "this specified port is to occupied")
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 	return cmd
 }
 
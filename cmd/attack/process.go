@@ -27,6 +27,10 @@ import (
 	"github.com/chaos-mesh/chaosd/pkg/utils"
 )
 
+func init() {
+	chaosd.AttachAttackCommand(core.ProcessAttack, NewProcessAttackCommand)
+}
+
 func NewProcessAttackCommand(uid *string) *cobra.Command {
 	options := core.NewProcessCommand()
 	dep := fx.Options(
@@ -45,6 +49,7 @@ func NewProcessAttackCommand(uid *string) *cobra.Command {
 	cmd.AddCommand(
 		NewProcessKillCommand(dep, options),
 		NewProcessStopCommand(dep, options),
+		NewProcessCrashLoopCommand(dep, options),
 	)
 
 	return cmd
@@ -60,9 +65,12 @@ func NewProcessKillCommand(dep fx.Option, options *core.ProcessCommand) *cobra.C
 		},
 	}
 
-	cmd.Flags().StringVarP(&options.Process, "process", "p", "", "The process name or the process ID")
+	setProcessSelectorFlags(cmd, options)
 	cmd.Flags().IntVarP(&options.Signal, "signal", "s", 9, "The signal number to send")
-	cmd.Flags().StringVarP(&options.RecoverCmd, "recover-cmd", "r", "", "The command to be run when recovering experiment")
+	cmd.Flags().StringVarP(&options.RecoverCmd, "recover-cmd", "r", "", "The command to be run when recovering experiment, "+
+		"the killed processes are restarted with their command lines and environments if it is not provided, "+
+		"the environments are saved in files only root can read")
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 
 	return cmd
 }
@@ -78,20 +86,83 @@ func NewProcessStopCommand(dep fx.Option, options *core.ProcessCommand) *cobra.C
 		},
 	}
 
-	cmd.Flags().StringVarP(&options.Process, "process", "p", "", "The process name or the process ID")
+	setProcessSelectorFlags(cmd, options)
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
+
+	return cmd
+}
+
+func NewProcessCrashLoopCommand(dep fx.Option, options *core.ProcessCommand) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "crash-loop",
+		Short: "kill process every interval until the attack is recovered, default signal 9",
+		Long: "Kill process every interval until the attack is recovered, default signal 9. " +
+			"The command keeps running while the processes are killed, until the duration is reached " +
+			"or the attack is recovered by chaosd recover",
+		Run: func(*cobra.Command, []string) {
+			options.Action = core.ProcessCrashLoopAction
+			utils.FxNewAppWithoutLog(dep, fx.Invoke(processCrashLoopF)).Run()
+		},
+	}
+
+	setProcessSelectorFlags(cmd, options)
+	cmd.Flags().IntVarP(&options.Signal, "signal", "s", 9, "The signal number to send")
+	cmd.Flags().StringVar(&options.Interval, "interval", "", "How often the processes are killed, such as 30s")
+	cmd.Flags().BoolVar(&options.WaitRestart, "wait-restart", false, "Kill the processes only when they have come back up, "+
+		"instead of every process matched at each interval")
+	cmd.Flags().StringVarP(&options.RecoverCmd, "recover-cmd", "r", "", "The command to be run when recovering experiment, "+
+		"the killed processes are restarted with their command lines and environments if it is not provided, "+
+		"the environments are saved in files only root can read")
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 
 	return cmd
 }
 
+// setProcessSelectorFlags sets the flags selecting the processes, the processes matching all of them are selected.
+func setProcessSelectorFlags(cmd *cobra.Command, options *core.ProcessCommand) {
+	cmd.Flags().StringVarP(&options.Process, "process", "p", "", "The process name or the process ID")
+	cmd.Flags().StringVar(&options.Cmdline, "cmdline", "", "The regular expression matched against the full command line, "+
+		"such as 'java -jar .*order-service.jar'")
+	cmd.Flags().BoolVar(&options.Exact, "exact", false, "Match the whole command line with --cmdline")
+	cmd.Flags().IntVar(&options.ParentPID, "ppid", 0, "Select the children of the parent process ID")
+	cmd.Flags().StringVarP(&options.User, "user", "u", "", "Select the processes of the user name or the user ID")
+	cmd.Flags().BoolVar(&options.Newest, "newest", false, "Select only the newest one of the matched processes")
+	cmd.Flags().BoolVar(&options.Oldest, "oldest", false, "Select only the oldest one of the matched processes")
+	cmd.Flags().BoolVar(&options.KillChildren, "kill-children", false, "Signal the whole process tree of the selected processes")
+	cmd.Flags().StringVar(&options.Mode, "mode", "", "Choose the processes from the matched ones randomly, "+
+		"one of all, one, fixed, fixed-percent and random-max-percent, the default value is all")
+	cmd.Flags().StringVar(&options.Value, "value", "", "The number of the processes for the mode fixed, "+
+		"or the percent of the processes for the modes fixed-percent and random-max-percent")
+}
+
 func processAttackF(options *core.ProcessCommand, chaos *chaosd.Server) {
 	if err := options.Validate(); err != nil {
 		utils.ExitWithError(utils.ExitBadArgs, err)
 	}
 
-	uid, err := chaos.ExecuteAttack(chaosd.ProcessAttack, options, core.CommandMode)
+	uid, err := executeAttack(chaos, chaosd.ProcessAttack, options)
+	if err != nil {
+		utils.ExitWithError(utils.ExitError, err)
+	}
+
+	if len(options.PIDs) > 0 {
+		utils.NormalExit(fmt.Sprintf("Attack process %s successfully, pids: %v, uid: %s", options.Selector(), options.PIDs, uid))
+	}
+	utils.NormalExit(fmt.Sprintf("Attack process %s successfully, uid: %s", options.Selector(), uid))
+}
+
+// processCrashLoopF executes the crash-loop attack and waits for its loop, which is stopped when the process exits.
+func processCrashLoopF(options *core.ProcessCommand, chaos *chaosd.Server) {
+	if err := options.Validate(); err != nil {
+		utils.ExitWithError(utils.ExitBadArgs, err)
+	}
+
+	uid, err := executeAttack(chaos, chaosd.ProcessAttack, options)
 	if err != nil {
 		utils.ExitWithError(utils.ExitError, err)
 	}
 
-	utils.NormalExit(fmt.Sprintf("Attack process %s successfully, uid: %s", options.Process, uid))
+	fmt.Printf("Attack process %s successfully, uid: %s, killing the processes every %s\n", options.Selector(), uid, options.Interval)
+	chaos.WaitCrashLoop(uid)
+	utils.NormalExit(fmt.Sprintf("Crash-loop of process %s is over, uid: %s", options.Selector(), uid))
 }
@@ -25,6 +25,10 @@ import (
 	"github.com/chaos-mesh/chaosd/pkg/utils"
 )
 
+func init() {
+	chaosd.AttachAttackCommand(core.RedisAttack, NewRedisAttackCommand)
+}
+
 func NewRedisAttackCommand(uid *string) *cobra.Command {
 	options := core.NewRedisCommand()
 	dep := fx.Options(
@@ -66,6 +70,7 @@ func NewRedisSentinelRestartCommand(dep fx.Option, options *core.RedisCommand) *
 	cmd.Flags().StringVarP(&options.Conf, "conf", "c", "", "The config of Redis server")
 	cmd.Flags().BoolVarP(&options.FlushConfig, "flush-config", "", true, " Force Sentinel to rewrite its configuration on disk")
 	cmd.Flags().StringVarP(&options.RedisPath, "redis-path", "", "", "The path of the redis-server command")
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 
 	return cmd
 }
@@ -86,6 +91,7 @@ func NewRedisSentinelStopCommand(dep fx.Option, options *core.RedisCommand) *cob
 	cmd.Flags().StringVarP(&options.Conf, "conf", "c", "", "The config path of Redis server")
 	cmd.Flags().BoolVarP(&options.FlushConfig, "flush-config", "", true, "Force Sentinel to rewrite its configuration on disk")
 	cmd.Flags().StringVarP(&options.RedisPath, "redis-path", "", "", "The path of the redis-server command")
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 
 	return cmd
 }
@@ -103,6 +109,7 @@ func NewRedisCachePenetrationCommand(dep fx.Option, options *core.RedisCommand)
 	cmd.Flags().StringVarP(&options.Addr, "addr", "a", "", "The address of redis server")
 	cmd.Flags().StringVarP(&options.Password, "password", "p", "", "The password of server")
 	cmd.Flags().IntVarP(&options.RequestNum, "request-num", "", 0, "The number of requests")
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 
 	return cmd
 }
@@ -121,6 +128,7 @@ func NewRedisCacheLimitCommand(dep fx.Option, options *core.RedisCommand) *cobra
 	cmd.Flags().StringVarP(&options.Password, "password", "p", "", "The password of server")
 	cmd.Flags().StringVarP(&options.CacheSize, "size", "s", "0", "The size of cache")
 	cmd.Flags().StringVarP(&options.Percent, "percent", "", "", "The percentage of maxmemory")
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 
 	return cmd
 }
@@ -140,6 +148,7 @@ func NewRedisCacheExpirationCommand(dep fx.Option, options *core.RedisCommand) *
 	cmd.Flags().StringVarP(&options.Key, "key", "k", "", "The key to be set a expiration, default expire all keys")
 	cmd.Flags().StringVarP(&options.Expiration, "expiration", "", "0", `The expiration of the key. A expiration string should be able to be converted to a time duration, such as "5s" or "30m"`)
 	cmd.Flags().StringVarP(&options.Option, "option", "", "", "The additional options of expiration, only NX, XX, GT, LT supported")
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 
 	return cmd
 }
@@ -148,7 +157,7 @@ func redisAttackF(chaos *chaosd.Server, options *core.RedisCommand) {
 	if err := options.Validate(); err != nil {
 		utils.ExitWithError(utils.ExitBadArgs, err)
 	}
-	uid, err := chaos.ExecuteAttack(chaosd.RedisAttack, options, core.CommandMode)
+	uid, err := executeAttack(chaos, chaosd.RedisAttack, options)
 	if err != nil {
 		utils.ExitWithError(utils.ExitError, err)
 	}
@@ -25,6 +25,10 @@ import (
 	"github.com/chaos-mesh/chaosd/pkg/utils"
 )
 
+func init() {
+	chaosd.AttachAttackCommand(core.StressAttack, NewStressAttackCommand)
+}
+
 func NewStressAttackCommand(uid *string) *cobra.Command {
 	options := core.NewStressCommand()
 	dep := fx.Options(
@@ -62,6 +66,7 @@ func NewStressCPUCommand(dep fx.Option, options *core.StressCommand) *cobra.Comm
 	cmd.Flags().IntVarP(&options.Load, "load", "l", 10, "Load specifies P percent loading per CPU worker. 0 is effectively a sleep (no load) and 100 is full loading.")
 	cmd.Flags().IntVarP(&options.Workers, "workers", "w", 1, "Workers specifies N workers to apply the stressor.")
 	cmd.Flags().StringSliceVarP(&options.Options, "options", "o", []string{}, "extend stress-ng options.")
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 
 	return cmd
 }
@@ -79,6 +84,7 @@ func NewStressMemCommand(dep fx.Option, options *core.StressCommand) *cobra.Comm
 
 	cmd.Flags().StringVarP(&options.Size, "size", "s", "", "Size specifies N bytes consumed per vm worker, default is the total available memory. One can specify the size as % of total available memory or in units of B, KB/KiB, MB/MiB, GB/GiB, TB/TiB..")
 	cmd.Flags().StringSliceVarP(&options.Options, "options", "o", []string{}, "extend stress-ng options.")
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 
 	return cmd
 }
@@ -88,7 +94,7 @@ func stressAttackF(chaos *chaosd.Server, options *core.StressCommand) {
 		utils.ExitWithError(utils.ExitBadArgs, err)
 	}
 
-	uid, err := chaos.ExecuteAttack(chaosd.StressAttack, options, core.CommandMode)
+	uid, err := executeAttack(chaos, chaosd.StressAttack, options)
 	if err != nil {
 		utils.ExitWithError(utils.ExitError, err)
 	}
@@ -0,0 +1,100 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attack
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+
+	"github.com/chaos-mesh/chaosd/cmd/server"
+	"github.com/chaos-mesh/chaosd/pkg/core"
+	"github.com/chaos-mesh/chaosd/pkg/server/chaosd"
+	"github.com/chaos-mesh/chaosd/pkg/utils"
+)
+
+func init() {
+	chaosd.AttachAttackCommand(core.SystemdAttack, NewSystemdAttackCommand)
+}
+
+func NewSystemdAttackCommand(uid *string) *cobra.Command {
+	options := core.NewSystemdCommand()
+	dep := fx.Options(
+		server.Module,
+		fx.Provide(func() *core.SystemdCommand {
+			options.UID = *uid
+			return options
+		}),
+	)
+
+	cmd := &cobra.Command{
+		Use:   "systemd <subcommand>",
+		Short: "Systemd unit attack related commands",
+	}
+
+	cmd.AddCommand(
+		NewSystemdActionCommand(dep, options, core.SystemdStopAction, "stop the unit"),
+		NewSystemdActionCommand(dep, options, core.SystemdRestartAction, "restart the unit"),
+		NewSystemdKillCommand(dep, options),
+		NewSystemdActionCommand(dep, options, core.SystemdFreezeAction, "freeze the processes of the unit with the cgroup freezer"),
+		NewSystemdMaskCommand(dep, options),
+	)
+
+	return cmd
+}
+
+func NewSystemdActionCommand(dep fx.Option, options *core.SystemdCommand, action, short string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   action,
+		Short: short,
+		Run: func(*cobra.Command, []string) {
+			options.Action = action
+			utils.FxNewAppWithoutLog(dep, fx.Invoke(systemdAttackF)).Run()
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.Unit, "unit", "u", "", "The name of the systemd unit, such as nginx.service")
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
+
+	return cmd
+}
+
+func NewSystemdKillCommand(dep fx.Option, options *core.SystemdCommand) *cobra.Command {
+	cmd := NewSystemdActionCommand(dep, options, core.SystemdKillAction, "send signal to all the processes of the unit, default signal 9")
+	cmd.Flags().IntVarP(&options.Signal, "signal", "s", 9, "The signal number to send")
+
+	return cmd
+}
+
+func NewSystemdMaskCommand(dep fx.Option, options *core.SystemdCommand) *cobra.Command {
+	cmd := NewSystemdActionCommand(dep, options, core.SystemdMaskAction, "mask and stop the unit, so that it can not be started")
+	cmd.Flags().BoolVar(&options.Runtime, "runtime", false, "Mask the unit until the next reboot only")
+
+	return cmd
+}
+
+func systemdAttackF(options *core.SystemdCommand, chaos *chaosd.Server) {
+	options.CompleteDefaults()
+	if err := options.Validate(); err != nil {
+		utils.ExitWithError(utils.ExitBadArgs, err)
+	}
+
+	uid, err := executeAttack(chaos, chaosd.SystemdAttack, options)
+	if err != nil {
+		utils.ExitWithError(utils.ExitError, err)
+	}
+
+	utils.NormalExit(fmt.Sprintf("Attack systemd unit %s successfully, uid: %s", options.Unit, uid))
+}
@@ -0,0 +1,88 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attack
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+
+	"github.com/chaos-mesh/chaosd/cmd/server"
+	"github.com/chaos-mesh/chaosd/pkg/client"
+	"github.com/chaos-mesh/chaosd/pkg/server/chaosd"
+	"github.com/chaos-mesh/chaosd/pkg/utils"
+)
+
+type updateCommand struct {
+	uid    string
+	config string
+	addr   string
+}
+
+func NewUpdateCommand() *cobra.Command {
+	options := &updateCommand{}
+	dep := fx.Options(
+		server.Module,
+		fx.Provide(func() *updateCommand {
+			return options
+		}),
+	)
+
+	cmd := &cobra.Command{
+		Use:   "update UID",
+		Short: "Update the parameters of a live chaos experiment",
+		Long: `Update the parameters of a live chaos experiment, the config is merged into the config of the experiment.
+For example, change the latency of a network delay attack:
+  chaosd attack update <uid> --config '{"latency": "100ms"}'
+
+The experiment is updated by chaosd server, which keeps the cron jobs and the deadlines of the experiments.
+If chaosd server is not running, the experiment is updated by this command directly.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			options.uid = args[0]
+			if len(options.config) == 0 {
+				utils.ExitWithError(utils.ExitBadArgs, errors.New("config is required"))
+			}
+			if !json.Valid([]byte(options.config)) {
+				utils.ExitWithError(utils.ExitBadArgs, errors.New("config is not valid JSON"))
+			}
+
+			exp, apiErr, err := client.NewClient(client.Config{Addr: options.addr}).UpdateAttack(options.uid, []byte(options.config))
+			if err != nil && client.IsUnreachable(err) {
+				fmt.Printf("chaosd server is not running at %s, updating %s directly\n", options.addr, options.uid)
+				utils.FxNewAppWithoutLog(dep, fx.Invoke(updateCommandF)).Run()
+				return
+			}
+			utils.ExitOnAPIError(apiErr, err)
+			utils.NormalExit(fmt.Sprintf("Update %s successfully, config: %s", exp.Uid, exp.RecoverCommand))
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.config, "config", "c", "", "the changed fields of the attack config in JSON, such as '{\"latency\": \"100ms\"}'")
+	cmd.Flags().StringVar(&options.addr, "addr", client.DefaultAddr, "the address of chaosd server")
+
+	return cmd
+}
+
+func updateCommandF(chaos *chaosd.Server, options *updateCommand) {
+	exp, err := chaos.UpdateAttack(options.uid, []byte(options.config))
+	if err != nil {
+		utils.ExitWithError(utils.ExitError, err)
+	}
+
+	utils.NormalExit(fmt.Sprintf("Update %s successfully, config: %s", exp.Uid, exp.RecoverCommand))
+}
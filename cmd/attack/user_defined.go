@@ -25,6 +25,10 @@ import (
 	"github.com/chaos-mesh/chaosd/pkg/utils"
 )
 
+func init() {
+	chaosd.AttachAttackCommand(core.UserDefinedAttack, NewUserDefinedCommand)
+}
+
 func NewUserDefinedCommand(uid *string) *cobra.Command {
 	options := core.NewUserDefinedOption()
 	dep := fx.Options(
@@ -46,6 +50,7 @@ func NewUserDefinedCommand(uid *string) *cobra.Command {
 
 	cmd.Flags().StringVarP(&options.AttackCmd, "attack-cmd", "a", "", "the command to be executed when attack")
 	cmd.Flags().StringVarP(&options.RecoverCmd, "recover-cmd", "r", "", "the command to be executed when recover")
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 
 	return cmd
 }
@@ -55,7 +60,7 @@ func userDefinedAttackFunc(options *core.UserDefinedOption, chaos *chaosd.Server
 		utils.ExitWithError(utils.ExitBadArgs, err)
 	}
 
-	uid, err := chaos.ExecuteAttack(chaosd.UserDefinedAttack, options, core.CommandMode)
+	uid, err := executeAttack(chaos, chaosd.UserDefinedAttack, options)
 	if err != nil {
 		utils.ExitWithError(utils.ExitError, err)
 	}
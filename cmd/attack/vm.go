@@ -25,6 +25,10 @@ import (
 	"github.com/chaos-mesh/chaosd/pkg/utils"
 )
 
+func init() {
+	chaosd.AttachAttackCommand(core.VMAttack, NewVMAttackCommand)
+}
+
 func NewVMAttackCommand(uid *string) *cobra.Command {
 	options := core.NewVMOption()
 	dep := fx.Options(
@@ -45,11 +49,12 @@ func NewVMAttackCommand(uid *string) *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&options.VMName, "vm-name", "v", "", "The name of the vm to be destoryed")
+	SetScheduleFlags(cmd, &options.SchedulerConfig)
 	return cmd
 }
 
 func vmAttack(options *core.VMOption, chaos *chaosd.Server) {
-	uid, err := chaos.ExecuteAttack(chaosd.VMAttack, options, core.CommandMode)
+	uid, err := executeAttack(chaos, chaosd.VMAttack, options)
 	if err != nil {
 		utils.ExitWithError(utils.ExitError, err)
 	}
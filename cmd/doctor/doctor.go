@@ -0,0 +1,82 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doctor
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+
+	"github.com/chaos-mesh/chaosd/cmd/server"
+	"github.com/chaos-mesh/chaosd/pkg/server/chaosd"
+	"github.com/chaos-mesh/chaosd/pkg/utils"
+)
+
+type doctorCommand struct {
+	fix bool
+}
+
+func NewDoctorCommand() *cobra.Command {
+	options := &doctorCommand{}
+	dep := fx.Options(
+		server.Module,
+		fx.Provide(func() *doctorCommand {
+			return options
+		}),
+	)
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the network rules left on the host by chaos attacks",
+		Run: func(cmd *cobra.Command, args []string) {
+			utils.FxNewAppWithoutLog(dep, fx.Invoke(doctorCommandFunc)).Run()
+		},
+	}
+
+	cmd.Flags().BoolVar(&options.fix, "fix", false, "remove the rules of inactive attacks and apply the rules of active attacks again")
+
+	return cmd
+}
+
+func doctorCommandFunc(chaos *chaosd.Server, options *doctorCommand) {
+	issues, err := chaos.DiagnoseNetworkRules(options.fix)
+	if err != nil {
+		utils.ExitWithError(utils.ExitError, err)
+	}
+
+	if len(issues) == 0 {
+		utils.NormalExit("No problem found")
+	}
+
+	tw := tablewriter.NewWriter(os.Stdout)
+	tw.SetHeader([]string{"Type", "Name", "Experiment", "Status", "Problem", "Fixed"})
+	tw.SetBorders(tablewriter.Border{Left: false, Top: false, Right: false, Bottom: false})
+	tw.SetAlignment(3)
+	tw.SetRowSeparator("-")
+	tw.SetCenterSeparator(" ")
+	tw.SetColumnSeparator(" ")
+
+	for _, issue := range issues {
+		tw.Append([]string{
+			issue.Type, issue.Name, issue.Experiment, issue.Status, issue.Problem, strconv.FormatBool(issue.Fixed),
+		})
+	}
+
+	tw.Render()
+
+	utils.NormalExit("")
+}
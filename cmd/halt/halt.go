@@ -0,0 +1,84 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package halt
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+
+	recovercmd "github.com/chaos-mesh/chaosd/cmd/recover"
+	"github.com/chaos-mesh/chaosd/cmd/server"
+	"github.com/chaos-mesh/chaosd/pkg/client"
+	"github.com/chaos-mesh/chaosd/pkg/server/chaosd"
+	"github.com/chaos-mesh/chaosd/pkg/utils"
+)
+
+type haltCommand struct {
+	reason string
+	addr   string
+}
+
+func NewHaltCommand() *cobra.Command {
+	options := &haltCommand{}
+	dep := fx.Options(
+		server.Module,
+		fx.Provide(func() *haltCommand {
+			return options
+		}),
+	)
+
+	cmd := &cobra.Command{
+		Use:   "halt",
+		Short: "Recover all the experiments and reject new attacks until chaosd is unhalted",
+		Long: `Recover all the experiments and reject new attacks until chaosd is unhalted.
+
+The experiments are halted by chaosd server, so that its scheduled experiments are removed too.
+If chaosd server is not running, chaosd is halted by this command directly.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			result, apiErr, err := client.NewClient(client.Config{Addr: options.addr}).Halt(options.reason)
+			if err != nil && client.IsUnreachable(err) {
+				fmt.Printf("chaosd server is not running at %s, halting chaosd directly\n", options.addr)
+				utils.FxNewAppWithoutLog(dep, fx.Invoke(haltCommandF)).Run()
+				return
+			}
+			utils.ExitOnAPIError(apiErr, err)
+			printHaltResults(result.Recovered)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.reason, "reason", "r", "", "the reason why chaosd is halted")
+	cmd.Flags().StringVar(&options.addr, "addr", client.DefaultAddr, "the address of chaosd server")
+	return cmd
+}
+
+func haltCommandF(chaos *chaosd.Server, options *haltCommand) {
+	results, err := chaos.Halt(options.reason)
+	if err != nil {
+		utils.ExitWithError(utils.ExitError, err)
+	}
+	printHaltResults(results)
+}
+
+func printHaltResults(results []chaosd.RecoverResult) {
+	if len(results) > 0 {
+		if failed := recovercmd.PrintRecoverResults(results); failed > 0 {
+			utils.ExitWithMsg(utils.ExitError,
+				fmt.Sprintf("chaosd is halted, but %d of %d experiments failed to recover", failed, len(results)))
+		}
+	}
+	utils.NormalExit(fmt.Sprintf("chaosd is halted, %d experiments are recovered", len(results)))
+}
@@ -19,6 +19,7 @@ import (
 	_ "github.com/alecthomas/template"
 	"github.com/pingcap/log"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	_ "github.com/swaggo/swag"
 	"go.uber.org/zap"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -26,9 +27,15 @@ import (
 
 	"github.com/chaos-mesh/chaosd/cmd/attack"
 	"github.com/chaos-mesh/chaosd/cmd/completion"
+	"github.com/chaos-mesh/chaosd/cmd/doctor"
+	"github.com/chaos-mesh/chaosd/cmd/halt"
+	"github.com/chaos-mesh/chaosd/cmd/pause"
 	"github.com/chaos-mesh/chaosd/cmd/recover"
+	"github.com/chaos-mesh/chaosd/cmd/resume"
+	"github.com/chaos-mesh/chaosd/cmd/schedule"
 	"github.com/chaos-mesh/chaosd/cmd/search"
 	"github.com/chaos-mesh/chaosd/cmd/server"
+	"github.com/chaos-mesh/chaosd/cmd/unhalt"
 	"github.com/chaos-mesh/chaosd/cmd/version"
 	"github.com/chaos-mesh/chaosd/pkg/utils"
 )
@@ -42,14 +49,20 @@ var rootCmd = &cobra.Command{
 }
 
 func init() {
-	cobra.OnInitialize(setLog)
+	cobra.OnInitialize(setLog, setGlobalFlags)
 	rootCmd.PersistentFlags().StringVarP(&logLevel, "log-level", "", "", "the log level of chaosd. The value can be 'debug', 'info', 'warn' and 'error'")
 
 	rootCmd.AddCommand(
 		server.NewServerCommand(),
 		attack.NewAttackCommand(),
 		recover.NewRecoverCommand(),
+		pause.NewPauseCommand(),
+		resume.NewResumeCommand(),
 		search.NewSearchCommand(),
+		schedule.NewScheduleCommand(),
+		halt.NewHaltCommand(),
+		unhalt.NewUnhaltCommand(),
+		doctor.NewDoctorCommand(),
 		version.NewVersionCommand(),
 		completion.NewCompletionCommand(),
 	)
@@ -57,6 +70,16 @@ func init() {
 	_ = utils.SetRuntimeEnv()
 }
 
+// setGlobalFlags records the global flags set by the user for the helper processes.
+func setGlobalFlags() {
+	utils.GlobalFlags = nil
+	rootCmd.PersistentFlags().VisitAll(func(flag *pflag.Flag) {
+		if flag.Changed {
+			utils.GlobalFlags = append(utils.GlobalFlags, "--"+flag.Name+"="+flag.Value.String())
+		}
+	})
+}
+
 func setLog() {
 	conf := &log.Config{Level: logLevel}
 	lg, r, err := log.InitLogger(conf)
@@ -0,0 +1,73 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pause
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+
+	"github.com/chaos-mesh/chaosd/cmd/server"
+	"github.com/chaos-mesh/chaosd/pkg/client"
+	"github.com/chaos-mesh/chaosd/pkg/server/chaosd"
+	"github.com/chaos-mesh/chaosd/pkg/utils"
+)
+
+type pauseCommand struct {
+	uid  string
+	addr string
+}
+
+func NewPauseCommand() *cobra.Command {
+	options := &pauseCommand{}
+	dep := fx.Options(
+		server.Module,
+		fx.Provide(func() *pauseCommand {
+			return options
+		}),
+	)
+
+	cmd := &cobra.Command{
+		Use:   "pause UID",
+		Short: "Pause a chaos experiment, the fault is lifted until it is resumed",
+		Long: `Pause a chaos experiment, the fault is lifted until it is resumed.
+
+The experiment is paused by chaosd server, which keeps the cron jobs and the deadlines of the experiments.
+If chaosd server is not running, the experiment is paused by this command directly.`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			options.uid = args[0]
+			_, apiErr, err := client.NewClient(client.Config{Addr: options.addr}).PauseAttack(options.uid)
+			if err != nil && client.IsUnreachable(err) {
+				fmt.Printf("chaosd server is not running at %s, pausing %s directly\n", options.addr, options.uid)
+				utils.FxNewAppWithoutLog(dep, fx.Invoke(pauseCommandF)).Run()
+				return
+			}
+			utils.ExitOnAPIError(apiErr, err)
+			utils.NormalExit(fmt.Sprintf("Pause %s successfully", options.uid))
+		},
+	}
+
+	cmd.Flags().StringVar(&options.addr, "addr", client.DefaultAddr, "the address of chaosd server")
+	return cmd
+}
+
+func pauseCommandF(chaos *chaosd.Server, options *pauseCommand) {
+	if err := chaos.PauseAttack(options.uid); err != nil {
+		utils.ExitWithError(utils.ExitError, err)
+	}
+
+	utils.NormalExit(fmt.Sprintf("Pause %s successfully", options.uid))
+}
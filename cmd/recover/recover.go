@@ -16,7 +16,9 @@ package recover
 import (
 	"context"
 	"fmt"
+	"os"
 
+	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 	"go.uber.org/fx"
 
@@ -24,12 +26,38 @@ import (
 	"github.com/pingcap/log"
 
 	"github.com/chaos-mesh/chaosd/cmd/server"
+	"github.com/chaos-mesh/chaosd/pkg/core"
 	"github.com/chaos-mesh/chaosd/pkg/server/chaosd"
 	"github.com/chaos-mesh/chaosd/pkg/utils"
 )
 
 type recoverCommand struct {
-	uid string
+	uid  string
+	wait bool
+
+	all     bool
+	workers int
+	filter  chaosd.RecoverFilter
+}
+
+// isBulk returns true if the experiments to recover are selected by the filter instead of the uid
+func (r *recoverCommand) isBulk() bool {
+	return r.all || len(r.filter.Kind) > 0 || len(r.filter.LaunchMode) > 0 || len(r.filter.Selector) > 0
+}
+
+func (r *recoverCommand) validate() error {
+	if len(r.filter.Kind) > 0 && core.GetAttackByKind(r.filter.Kind) == nil {
+		return errors.Errorf("kind %s not supported", r.filter.Kind)
+	}
+	if len(r.filter.LaunchMode) > 0 && r.filter.LaunchMode != core.ServerMode && r.filter.LaunchMode != core.CommandMode {
+		return errors.Errorf("launch mode %s not supported", r.filter.LaunchMode)
+	}
+	if len(r.filter.Selector) > 0 {
+		if _, err := core.ParseSelector(r.filter.Selector); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func NewRecoverCommand() *cobra.Command {
@@ -43,10 +71,19 @@ func NewRecoverCommand() *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:               "recover UID",
-		Short:             "Recover a chaos experiment",
-		Args:              cobra.MinimumNArgs(1),
+		Short:             "Recover a chaos experiment, or all the active experiments selected by the options",
 		ValidArgsFunction: completeUid,
 		Run: func(cmd *cobra.Command, args []string) {
+			if options.isBulk() {
+				if len(args) > 0 {
+					utils.ExitWithMsg(utils.ExitBadArgs, "UID can not be used together with --all, --kind, --launch-mode or --selector")
+				}
+				if err := options.validate(); err != nil {
+					utils.ExitWithError(utils.ExitBadArgs, err)
+				}
+				utils.FxNewAppWithoutLog(dep, fx.Invoke(bulkRecoverCommandF)).Run()
+				return
+			}
 			if len(args) == 0 {
 				utils.ExitWithMsg(utils.ExitBadArgs, "UID is required")
 			}
@@ -54,11 +91,28 @@ func NewRecoverCommand() *cobra.Command {
 			utils.FxNewAppWithoutLog(dep, fx.Invoke(recoverCommandF)).Run()
 		},
 	}
+
+	cmd.Flags().BoolVarP(&options.all, "all", "A", false, "recover all the active experiments")
+	cmd.Flags().StringVarP(&options.filter.Kind, "kind", "k", "", "recover all the active experiments of the kind, such as network")
+	cmd.Flags().StringVar(&options.filter.LaunchMode, "launch-mode", "",
+		"recover all the active experiments launched in the mode, supported value: svr, cmd")
+	cmd.Flags().StringVarP(&options.filter.Selector, "selector", "l", "",
+		"recover all the active experiments matching the label selector, such as team=payments")
+	cmd.Flags().IntVar(&options.workers, "workers", chaosd.DefaultRecoverWorkers, "the number of experiments recovered at the same time")
+
+	// wait is used by the helper process which recovers an attack in command mode on its deadline
+	cmd.Flags().BoolVar(&options.wait, "wait", false, "wait until the deadline of the experiment before recovering it")
+	_ = cmd.Flags().MarkHidden("wait")
 	return cmd
 }
 
 func recoverCommandF(chaos *chaosd.Server, options *recoverCommand) {
-	err := chaos.RecoverAttack(options.uid)
+	var err error
+	if options.wait {
+		err = chaos.WaitForDeadline(options.uid)
+	} else {
+		err = chaos.RecoverAttack(options.uid)
+	}
 	if err != nil {
 		utils.ExitWithError(utils.ExitError, err)
 	}
@@ -66,6 +120,45 @@ func recoverCommandF(chaos *chaosd.Server, options *recoverCommand) {
 	utils.NormalExit(fmt.Sprintf("Recover %s successfully", options.uid))
 }
 
+func bulkRecoverCommandF(chaos *chaosd.Server, options *recoverCommand) {
+	results, err := chaos.RecoverExperiments(options.filter, options.workers)
+	if err != nil {
+		utils.ExitWithError(utils.ExitError, err)
+	}
+	if len(results) == 0 {
+		utils.NormalExit("No active experiment to recover")
+	}
+
+	failed := PrintRecoverResults(results)
+	if failed > 0 {
+		utils.ExitWithMsg(utils.ExitError, fmt.Sprintf("%d of %d experiments failed to recover", failed, len(results)))
+	}
+	utils.NormalExit(fmt.Sprintf("Recover %d experiments successfully", len(results)))
+}
+
+// PrintRecoverResults prints the results of recovering experiments in a table and returns the number of failures.
+func PrintRecoverResults(results []chaosd.RecoverResult) int {
+	tw := tablewriter.NewWriter(os.Stdout)
+	tw.SetHeader([]string{"UID", "Kind", "Result", "Error"})
+	tw.SetBorders(tablewriter.Border{Left: false, Top: false, Right: false, Bottom: false})
+	tw.SetAlignment(3)
+	tw.SetRowSeparator("-")
+	tw.SetCenterSeparator(" ")
+	tw.SetColumnSeparator(" ")
+
+	failed := 0
+	for _, result := range results {
+		if len(result.Error) > 0 {
+			failed++
+			tw.Append([]string{result.UID, result.Kind, "failed", result.Error})
+			continue
+		}
+		tw.Append([]string{result.UID, result.Kind, "recovered", ""})
+	}
+	tw.Render()
+	return failed
+}
+
 func completeUid(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	completionCtx := newCompletionCtx()
 	completionDep := fx.Options(
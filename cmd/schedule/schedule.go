@@ -0,0 +1,145 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/chaos-mesh/chaosd/pkg/client"
+	"github.com/chaos-mesh/chaosd/pkg/server/chaosd"
+	cmdutils "github.com/chaos-mesh/chaosd/pkg/utils"
+)
+
+// The scheduled experiments are run by chaosd server, so the commands manage them through its API.
+type scheduleCommand struct {
+	addr string
+}
+
+func (s *scheduleCommand) client() *client.Client {
+	return client.NewClient(client.Config{Addr: s.addr})
+}
+
+func NewScheduleCommand() *cobra.Command {
+	options := &scheduleCommand{}
+
+	cmd := &cobra.Command{
+		Use:   "schedule <subcommand>",
+		Short: "Manage the scheduled experiments of chaosd server",
+	}
+	cmd.PersistentFlags().StringVar(&options.addr, "addr", client.DefaultAddr, "the address of chaosd server")
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "list",
+			Short: "List the scheduled experiments with their next and previous fire times",
+			Args:  cobra.NoArgs,
+			Run: func(*cobra.Command, []string) {
+				schedules, apiErr, err := options.client().ListSchedules()
+				cmdutils.ExitOnAPIError(apiErr, err)
+				printSchedules(schedules)
+				cmdutils.NormalExit("")
+			},
+		},
+		&cobra.Command{
+			Use:   "describe UID",
+			Short: "Describe the schedule of an experiment",
+			Args:  cobra.ExactArgs(1),
+			Run: func(_ *cobra.Command, args []string) {
+				schedule, apiErr, err := options.client().DescribeSchedule(args[0])
+				cmdutils.ExitOnAPIError(apiErr, err)
+				printJSON(schedule)
+			},
+		},
+		newSuspendCommand(options, true),
+		newSuspendCommand(options, false),
+		&cobra.Command{
+			Use:   "trigger UID",
+			Short: "Start a run of a scheduled experiment at once, regardless of its windows, jitter and suspension",
+			Args:  cobra.ExactArgs(1),
+			Run: func(_ *cobra.Command, args []string) {
+				run, apiErr, err := options.client().TriggerSchedule(args[0])
+				cmdutils.ExitOnAPIError(apiErr, err)
+				printJSON(run)
+			},
+		},
+	)
+	return cmd
+}
+
+func newSuspendCommand(options *scheduleCommand, suspend bool) *cobra.Command {
+	use, short, done := "suspend UID", "Suspend the schedule of an experiment, no new run starts until it is unsuspended", "Suspend"
+	if !suspend {
+		use, short, done = "unsuspend UID", "Unsuspend the schedule of an experiment", "Unsuspend"
+	}
+
+	return &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			_, apiErr, err := options.client().SuspendSchedule(args[0], suspend)
+			cmdutils.ExitOnAPIError(apiErr, err)
+			cmdutils.NormalExit(fmt.Sprintf("%s schedule of %s successfully", done, args[0]))
+		},
+	}
+}
+
+func printJSON(obj interface{}) {
+	data, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		cmdutils.ExitWithError(cmdutils.ExitError, err)
+	}
+	cmdutils.NormalExit(string(data))
+}
+
+func printSchedules(schedules []*chaosd.ScheduleInfo) {
+	tw := tablewriter.NewWriter(os.Stdout)
+	tw.SetHeader([]string{"UID", "Kind", "Status", "Schedule", "Suspended", "Next Run", "Prev Run", "Last Run", "Pending Recovery"})
+	tw.SetBorders(tablewriter.Border{Left: false, Top: false, Right: false, Bottom: false})
+	tw.SetAlignment(3)
+	tw.SetRowSeparator("-")
+	tw.SetCenterSeparator(" ")
+	tw.SetColumnSeparator(" ")
+
+	for _, s := range schedules {
+		schedule := s.Schedule
+		if len(s.TimeZone) > 0 {
+			schedule += " (" + s.TimeZone + ")"
+		}
+		lastRun := ""
+		if s.LastRun != nil {
+			lastRun = s.LastRun.Status
+		}
+		tw.Append([]string{
+			s.UID, s.Kind, s.Status, schedule, strconv.FormatBool(s.Suspended),
+			formatTime(s.NextRun), formatTime(s.PrevRun), lastRun, strconv.FormatBool(s.PendingRecovery),
+		})
+	}
+
+	tw.Render()
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -15,6 +15,8 @@ package search
 
 import (
 	"os"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/olekukonko/tablewriter"
@@ -49,9 +51,10 @@ func NewSearchCommand() *cobra.Command {
 
 	cmd.Flags().BoolVarP(&options.All, "all", "A", false, "list all chaos attacks")
 	cmd.Flags().StringVarP(&options.Status, "status", "s", "", "attack status, "+
-		"supported value: created, success, error, destroyed, revoked")
+		"supported value: created, success, error, scheduled, paused, destroyed, revoked, aborted")
 	cmd.Flags().StringVarP(&options.Kind, "kind", "k", "", "attack kind, "+
 		"supported value: network, process, stress, disk, host, jvm")
+	cmd.Flags().StringVar(&options.Selector, "selector", "", "label selector of attacks, such as team=payments,env!=prod")
 	cmd.Flags().Uint32VarP(&options.Offset, "offset", "o", 0, "starting to search attacks from offset")
 	cmd.Flags().Uint32VarP(&options.Limit, "limit", "l", 0, "limit the count of attacks")
 	cmd.Flags().BoolVar(&options.Asc, "asc", false, "order by CreateTime, "+
@@ -71,7 +74,7 @@ func searchCommandFunc(chaos *chaosd.Server, options *core.SearchCommand) {
 	}
 
 	tw := tablewriter.NewWriter(os.Stdout)
-	tw.SetHeader([]string{"UID", "Kind", "Action", "Status", "Create Time", "Configuration"})
+	tw.SetHeader([]string{"UID", "Kind", "Action", "Status", "Create Time", "Labels", "Configuration"})
 	tw.SetBorders(tablewriter.Border{Left: false, Top: false, Right: false, Bottom: false})
 	tw.SetAlignment(3)
 	tw.SetRowSeparator("-")
@@ -80,7 +83,7 @@ func searchCommandFunc(chaos *chaosd.Server, options *core.SearchCommand) {
 
 	for _, exp := range exps {
 		tw.Append([]string{
-			exp.Uid, exp.Kind, exp.Action, exp.Status, exp.CreatedAt.Format(time.RFC3339), exp.RecoverCommand,
+			exp.Uid, exp.Kind, exp.Action, exp.Status, exp.CreatedAt.Format(time.RFC3339), formatLabels(exp.Labels), exp.RecoverCommand,
 		})
 	}
 
@@ -88,3 +91,12 @@ func searchCommandFunc(chaos *chaosd.Server, options *core.SearchCommand) {
 
 	utils.NormalExit("")
 }
+
+func formatLabels(labels core.Labels) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
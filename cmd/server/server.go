@@ -14,10 +14,13 @@
 package server
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 	"go.uber.org/fx"
 
 	"github.com/chaos-mesh/chaosd/pkg/config"
+	"github.com/chaos-mesh/chaosd/pkg/core"
 	"github.com/chaos-mesh/chaosd/pkg/server/httpserver"
 	"github.com/chaos-mesh/chaosd/pkg/utils"
 	"github.com/chaos-mesh/chaosd/pkg/version"
@@ -37,14 +40,27 @@ func NewServerCommand() *cobra.Command {
 	cmd.Flags().StringVar(&conf.SSLKeyFile, "key", "", "path to a PEM encoded private key file")
 	cmd.Flags().StringVar(&conf.SSLClientCAFile, "CA", "", "path to a PEM encoded CA's certificate file")
 	cmd.Flags().StringVar(&conf.ServerName, "server-name", "chaosd.chaos-mesh.org", "server name is used to verify the hostname on the returned certificates")
-	cmd.Flags().StringVarP(&conf.Runtime, "runtime", "r", "docker", "current container runtime")
+	AddRuntimeFlag(cmd)
 	cmd.Flags().BoolVar(&conf.EnablePprof, "enable-pprof", true, "enable pprof")
 	cmd.Flags().IntVar(&conf.PprofPort, "pprof-port", 31766, "listen port of the pprof server")
 	cmd.Flags().StringVarP(&conf.Platform, "platform", "f", "local", "platform to deploy, default: local, supported platform: local, kubernetes")
+	cmd.Flags().StringVar(&conf.PolicyFile, "policy", "", "path to a YAML or JSON policy file which limits the blast radius of the attacks, "+core.DefaultPolicyFile+" is loaded if it is not set")
+	cmd.Flags().BoolVar(&conf.FixNetworkRules, "fix-network-rules", true, "fix the leaked or missing network rules when the server starts, they are only reported if it is false")
+	cmd.Flags().DurationVar(&conf.Guardrails.Interval, "guardrail-interval", 5*time.Second, "interval of sampling the host metrics for the guardrails")
+	cmd.Flags().Float64Var(&conf.Guardrails.MaxCPUPercent, "guardrail-max-cpu-percent", 0, "recover the CPU stress experiments if the CPU usage of the host exceeds the percent, such as 90")
+	cmd.Flags().StringVar(&conf.Guardrails.MinFreeMemory, "guardrail-min-free-memory", "", "recover the memory stress experiments if the available memory of the host is less than the size, such as 512MB")
+	cmd.Flags().StringToStringVar(&conf.Guardrails.MinFreeDisk, "guardrail-min-free-disk", nil, "recover the disk experiments if the free space of the mount point is less than the size, such as --guardrail-min-free-disk /=1GB")
+	cmd.Flags().Float64Var(&conf.Guardrails.MaxLoadAverage, "guardrail-max-load", 0, "recover the CPU stress experiments if the load average of the last minute exceeds the value")
 
 	return cmd
 }
 
+// AddRuntimeFlag adds the flag of the container runtime to the command, it is used by the commands
+// attacking containers without chaosd server.
+func AddRuntimeFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&conf.Runtime, "runtime", "r", "docker", "current container runtime")
+}
+
 var conf = config.Config{
 	Platform: config.LocalPlatform,
 	Runtime:  "docker",
@@ -0,0 +1,56 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unhalt
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+
+	"github.com/chaos-mesh/chaosd/cmd/server"
+	"github.com/chaos-mesh/chaosd/pkg/client"
+	"github.com/chaos-mesh/chaosd/pkg/server/chaosd"
+	"github.com/chaos-mesh/chaosd/pkg/utils"
+)
+
+func NewUnhaltCommand() *cobra.Command {
+	var addr string
+	cmd := &cobra.Command{
+		Use:   "unhalt",
+		Short: "Allow new attacks to be executed again after chaosd is halted",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			_, apiErr, err := client.NewClient(client.Config{Addr: addr}).Unhalt()
+			if err != nil && client.IsUnreachable(err) {
+				fmt.Printf("chaosd server is not running at %s, unhalting chaosd directly\n", addr)
+				utils.FxNewAppWithoutLog(server.Module, fx.Invoke(unhaltCommandF)).Run()
+				return
+			}
+			utils.ExitOnAPIError(apiErr, err)
+			utils.NormalExit("chaosd is unhalted")
+		},
+	}
+
+	cmd.Flags().StringVar(&addr, "addr", client.DefaultAddr, "the address of chaosd server")
+	return cmd
+}
+
+func unhaltCommandF(chaos *chaosd.Server) {
+	if err := chaos.Unhalt(); err != nil {
+		utils.ExitWithError(utils.ExitError, err)
+	}
+
+	utils.NormalExit("chaosd is unhalted")
+}
@@ -25,7 +25,9 @@ import (
 )
 
 const (
+	attack        = "api/attack"
 	processAttack = "api/attack/process"
+	experiments   = "api/experiments"
 )
 
 func (c *Client) CreateProcessAttack(attack *core.ProcessCommand) (*utils.Response, *utils.APIError, error) {
@@ -55,3 +57,24 @@ func (c *Client) CreateProcessAttack(attack *core.ProcessCommand) (*utils.Respon
 
 	return resp, nil, nil
 }
+
+// PauseAttack lifts the fault of the experiment until it is resumed.
+func (c *Client) PauseAttack(uid string) (*utils.Response, *utils.APIError, error) {
+	resp := &utils.Response{}
+	apiErr, err := c.call(http.MethodPost, fmt.Sprintf("%s/%s/pause", attack, uid), resp)
+	return resp, apiErr, err
+}
+
+// ResumeAttack injects the fault of the paused experiment again.
+func (c *Client) ResumeAttack(uid string) (*utils.Response, *utils.APIError, error) {
+	resp := &utils.Response{}
+	apiErr, err := c.call(http.MethodPost, fmt.Sprintf("%s/%s/resume", attack, uid), resp)
+	return resp, apiErr, err
+}
+
+// UpdateAttack merges the patch, which is a JSON object of the changed fields, into the config of the experiment.
+func (c *Client) UpdateAttack(uid string, patch []byte) (*core.Experiment, *utils.APIError, error) {
+	resp := &core.Experiment{}
+	apiErr, err := c.callWithBody(http.MethodPatch, fmt.Sprintf("%s/%s", experiments, uid), json.RawMessage(patch), resp)
+	return resp, apiErr, err
+}
@@ -15,8 +15,14 @@ package client
 
 import (
 	"net/http"
+	"net/url"
+
+	"github.com/pingcap/errors"
 )
 
+// DefaultAddr is the default address of chaosd server.
+const DefaultAddr = "http://127.0.0.1:31767"
+
 // Client is used to communicate with the chaosd
 type Client struct {
 	cfg    Config
@@ -35,3 +41,10 @@ func NewClient(cfg Config) *Client {
 		client: http.DefaultClient,
 	}
 }
+
+// IsUnreachable returns true if the error is caused by failing to connect to chaosd server,
+// such as the server is not running.
+func IsUnreachable(err error) bool {
+	_, ok := errors.Cause(err).(*url.Error)
+	return ok
+}
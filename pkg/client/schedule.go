@@ -0,0 +1,96 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/pingcap/errors"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+	"github.com/chaos-mesh/chaosd/pkg/server/chaosd"
+	"github.com/chaos-mesh/chaosd/pkg/server/utils"
+)
+
+const (
+	schedules = "api/schedules"
+)
+
+func (c *Client) ListSchedules() ([]*chaosd.ScheduleInfo, *utils.APIError, error) {
+	var resp []*chaosd.ScheduleInfo
+	apiErr, err := c.call(http.MethodGet, schedules, &resp)
+	return resp, apiErr, err
+}
+
+func (c *Client) DescribeSchedule(uid string) (*chaosd.ScheduleInfo, *utils.APIError, error) {
+	resp := &chaosd.ScheduleInfo{}
+	apiErr, err := c.call(http.MethodGet, fmt.Sprintf("%s/%s", schedules, uid), resp)
+	return resp, apiErr, err
+}
+
+// SuspendSchedule suspends the schedule of the experiment, or unsuspends it if suspend is false.
+func (c *Client) SuspendSchedule(uid string, suspend bool) (*chaosd.ScheduleInfo, *utils.APIError, error) {
+	action := "suspend"
+	if !suspend {
+		action = "unsuspend"
+	}
+	resp := &chaosd.ScheduleInfo{}
+	apiErr, err := c.call(http.MethodPost, fmt.Sprintf("%s/%s/%s", schedules, uid, action), resp)
+	return resp, apiErr, err
+}
+
+func (c *Client) TriggerSchedule(uid string) (*core.ExperimentRun, *utils.APIError, error) {
+	resp := &core.ExperimentRun{}
+	apiErr, err := c.call(http.MethodPost, fmt.Sprintf("%s/%s/trigger", schedules, uid), resp)
+	return resp, apiErr, err
+}
+
+// call sends a request without body to the path and decodes the response into resp.
+func (c *Client) call(method, path string, resp interface{}) (*utils.APIError, error) {
+	return c.callWithBody(method, path, nil, resp)
+}
+
+// callWithBody sends a request with the JSON encoded body to the path and decodes the response into resp,
+// the request has no body if body is nil.
+func (c *Client) callWithBody(method, path string, body, resp interface{}) (*utils.APIError, error) {
+	var opts []BodyOption
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		opts = append(opts, withJsonBody(data))
+	}
+
+	url := fmt.Sprintf("%s/%s", c.cfg.Addr, path)
+	data, apiErr, err := doRequest(c.client, url, method, opts...)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if apiErr != nil {
+		aerr := &utils.APIError{}
+		if err := json.Unmarshal(apiErr, aerr); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return aerr, nil
+	}
+
+	if err := json.Unmarshal(data, resp); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return nil, nil
+}
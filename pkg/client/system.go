@@ -0,0 +1,41 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"net/http"
+
+	"github.com/chaos-mesh/chaosd/pkg/server/chaosd"
+	"github.com/chaos-mesh/chaosd/pkg/server/utils"
+)
+
+const (
+	system = "api/system"
+)
+
+// Halt recovers all the experiments of chaosd server and rejects new attacks until it is unhalted.
+func (c *Client) Halt(reason string) (*chaosd.HaltResult, *utils.APIError, error) {
+	req := struct {
+		Reason string `json:"reason"`
+	}{Reason: reason}
+	resp := &chaosd.HaltResult{}
+	apiErr, err := c.callWithBody(http.MethodPost, system+"/halt", req, resp)
+	return resp, apiErr, err
+}
+
+func (c *Client) Unhalt() (*chaosd.HaltResult, *utils.APIError, error) {
+	resp := &chaosd.HaltResult{}
+	apiErr, err := c.call(http.MethodPost, system+"/unhalt", resp)
+	return resp, apiErr, err
+}
@@ -15,9 +15,12 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/pingcap/errors"
 	flag "github.com/spf13/pflag"
+
+	"github.com/chaos-mesh/chaosd/pkg/utils"
 )
 
 // Config defines the configuration for Chaosd.
@@ -37,6 +40,59 @@ type Config struct {
 	PprofPort       int
 	Platform        string
 	ServerName      string
+	PolicyFile      string
+	// FixNetworkRules enables fixing the leaked or missing network rules when the server starts,
+	// otherwise they are only reported. The server fixes them by default.
+	FixNetworkRules bool
+	Guardrails      Guardrails
+}
+
+// Guardrails defines the thresholds of the host resources. The experiments which exhaust a resource
+// are recovered once its threshold is crossed. A threshold is disabled if it is zero or empty.
+type Guardrails struct {
+	// Interval is the interval of sampling the host metrics.
+	Interval time.Duration
+	// MaxCPUPercent is the max usage of all the CPUs, such as 90.
+	MaxCPUPercent float64
+	// MinFreeMemory is the min available memory, such as "512MB".
+	MinFreeMemory string
+	// MinFreeDisk is the min free space of the mount points, such as {"/": "1GB"}.
+	MinFreeDisk map[string]string
+	// MaxLoadAverage is the max load average of the last minute.
+	MaxLoadAverage float64
+}
+
+// Enabled returns true if any of the thresholds is set.
+func (g Guardrails) Enabled() bool {
+	return g.MaxCPUPercent > 0 || len(g.MinFreeMemory) > 0 || len(g.MinFreeDisk) > 0 || g.MaxLoadAverage > 0
+}
+
+// Validate is used to validate if the thresholds are right.
+func (g Guardrails) Validate() error {
+	if !g.Enabled() {
+		return nil
+	}
+
+	if g.Interval <= 0 {
+		return errors.Errorf("guardrail interval %s should be positive", g.Interval)
+	}
+	if g.MaxCPUPercent < 0 || g.MaxCPUPercent > 100 {
+		return errors.Errorf("guardrail max cpu percent %v should be between 0 and 100", g.MaxCPUPercent)
+	}
+	if g.MaxLoadAverage < 0 {
+		return errors.Errorf("guardrail max load average %v should not be negative", g.MaxLoadAverage)
+	}
+	if len(g.MinFreeMemory) > 0 {
+		if _, err := utils.ParseUnit(g.MinFreeMemory); err != nil {
+			return errors.Annotatef(err, "invalid guardrail min free memory %s", g.MinFreeMemory)
+		}
+	}
+	for mount, size := range g.MinFreeDisk {
+		if _, err := utils.ParseUnit(size); err != nil {
+			return errors.Annotatef(err, "invalid guardrail min free disk %s of %s", size, mount)
+		}
+	}
+	return nil
 }
 
 // Parse parses flag definitions from the argument list.
@@ -76,7 +132,7 @@ func (c *Config) Validate() error {
 		return errors.New("attempting to use client CA without providing server certificate")
 	}
 
-	return nil
+	return c.Guardrails.Validate()
 }
 
 type Platform string
@@ -99,7 +155,7 @@ func checkPlatform(platform string) bool {
 	return false
 }
 
-var supportRuntimes = []string{"docker", "runtime"}
+var supportRuntimes = []string{"docker", "containerd"}
 
 func checkRuntime(runtime string) bool {
 	for _, r := range supportRuntimes {
@@ -17,7 +17,9 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/containerd/containerd"
 	"github.com/docker/docker/api/types"
@@ -40,11 +42,41 @@ const (
 	containerdDefaultNS      = "k8s.io"
 )
 
+// The states of the containers returned by CRIClient.ContainerState.
+const (
+	ContainerRunning = "running"
+	ContainerPaused  = "paused"
+	ContainerStopped = "stopped"
+)
+
 // CRIClient represents a struct which can give you information about container runtime
 type CRIClient interface {
 	GetPidFromContainerID(ctx context.Context, containerID string) (uint32, error)
 	ContainerKillByContainerID(ctx context.Context, containerID string) error
 	FormatContainerID(ctx context.Context, containerID string) (string, error)
+
+	// ListContainers returns the IDs with the protocol prefix of the running and paused containers
+	// whose name is the name and which have all the labels, the empty name matches all the names.
+	ListContainers(ctx context.Context, name string, labels map[string]string) ([]string, error)
+	// ContainerState returns the state of the container, such as ContainerRunning.
+	ContainerState(ctx context.Context, containerID string) (string, error)
+	ContainerStop(ctx context.Context, containerID string) error
+	ContainerStart(ctx context.Context, containerID string) error
+	ContainerRestart(ctx context.Context, containerID string) error
+	ContainerPause(ctx context.Context, containerID string) error
+	ContainerUnpause(ctx context.Context, containerID string) error
+}
+
+// RuntimeOf returns the runtime of the container ID with the protocol prefix, such as "containerd" of
+// "containerd://<id>". It returns an empty string if the ID has no known prefix.
+func RuntimeOf(containerID string) string {
+	switch {
+	case strings.HasPrefix(containerID, dockerProtocolPrefix):
+		return containerRuntimeDocker
+	case strings.HasPrefix(containerID, containerdProtocolPrefix):
+		return containerRuntimeContainerd
+	}
+	return ""
 }
 
 // NewCRIClient creates a container runtime information client.
@@ -79,6 +111,12 @@ func NewCRIClient(conf *config.Config) (CRIClient, error) {
 type DockerClientInterface interface {
 	ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error)
 	ContainerKill(ctx context.Context, containerID, signal string) error
+	ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error)
+	ContainerStop(ctx context.Context, containerID string, timeout *time.Duration) error
+	ContainerStart(ctx context.Context, containerID string, options types.ContainerStartOptions) error
+	ContainerRestart(ctx context.Context, containerID string, timeout *time.Duration) error
+	ContainerPause(ctx context.Context, containerID string) error
+	ContainerUnpause(ctx context.Context, containerID string) error
 }
 
 // DockerClient can get information from docker
@@ -114,6 +152,7 @@ func (c DockerClient) GetPidFromContainerID(ctx context.Context, containerID str
 // ContainerdClientInterface represents the ContainerClient, it's used to simply unit test
 type ContainerdClientInterface interface {
 	LoadContainer(ctx context.Context, id string) (containerd.Container, error)
+	Containers(ctx context.Context, filters ...string) ([]containerd.Container, error)
 }
 
 // ContainerdClient can get information from containerd
@@ -0,0 +1,328 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"context"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/cio"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/pingcap/errors"
+)
+
+// stopTimeout is how long the container is waited to exit after SIGTERM before it is killed.
+const stopTimeout = 10 * time.Second
+
+// containerdNameLabels are the labels of the container names set by the clients of containerd.
+var containerdNameLabels = []string{"nerdctl/name", "io.kubernetes.container.name"}
+
+// ListContainers returns the running and paused containers with the name and the labels
+func (c DockerClient) ListContainers(ctx context.Context, name string, labels map[string]string) ([]string, error) {
+	args := filters.NewArgs()
+	for key, value := range labels {
+		args.Add("label", key+"="+value)
+	}
+	containers, err := c.client.ContainerList(ctx, types.ContainerListOptions{Filters: args})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var ids []string
+	for _, container := range containers {
+		if len(name) > 0 && !hasDockerName(container.Names, name) {
+			continue
+		}
+		ids = append(ids, dockerProtocolPrefix+container.ID)
+	}
+	return ids, nil
+}
+
+func hasDockerName(names []string, name string) bool {
+	for _, n := range names {
+		if strings.TrimPrefix(n, "/") == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainerState returns the state of the container
+func (c DockerClient) ContainerState(ctx context.Context, containerID string) (string, error) {
+	id, err := c.FormatContainerID(ctx, containerID)
+	if err != nil {
+		return "", err
+	}
+	container, err := c.client.ContainerInspect(ctx, id)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	switch {
+	case container.State == nil:
+		return ContainerStopped, nil
+	case container.State.Paused:
+		return ContainerPaused, nil
+	case container.State.Running:
+		return ContainerRunning, nil
+	}
+	return ContainerStopped, nil
+}
+
+// ContainerStop stops the container with SIGTERM, it is killed if it does not exit in time
+func (c DockerClient) ContainerStop(ctx context.Context, containerID string) error {
+	id, err := c.FormatContainerID(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	timeout := stopTimeout
+	return errors.WithStack(c.client.ContainerStop(ctx, id, &timeout))
+}
+
+// ContainerStart starts the stopped container
+func (c DockerClient) ContainerStart(ctx context.Context, containerID string) error {
+	id, err := c.FormatContainerID(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	return errors.WithStack(c.client.ContainerStart(ctx, id, types.ContainerStartOptions{}))
+}
+
+// ContainerRestart stops the container and starts it again
+func (c DockerClient) ContainerRestart(ctx context.Context, containerID string) error {
+	id, err := c.FormatContainerID(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	timeout := stopTimeout
+	return errors.WithStack(c.client.ContainerRestart(ctx, id, &timeout))
+}
+
+// ContainerPause freezes the processes of the container
+func (c DockerClient) ContainerPause(ctx context.Context, containerID string) error {
+	id, err := c.FormatContainerID(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	return errors.WithStack(c.client.ContainerPause(ctx, id))
+}
+
+// ContainerUnpause unfreezes the processes of the container
+func (c DockerClient) ContainerUnpause(ctx context.Context, containerID string) error {
+	id, err := c.FormatContainerID(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	return errors.WithStack(c.client.ContainerUnpause(ctx, id))
+}
+
+// ListContainers returns the running and paused containers with the name and the labels. The name of a containerd
+// container is its ID, or the name set by its client, such as nerdctl and kubelet.
+func (c ContainerdClient) ListContainers(ctx context.Context, name string, labels map[string]string) ([]string, error) {
+	containers, err := c.client.Containers(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var ids []string
+	for _, container := range containers {
+		containerLabels, err := container.Labels(ctx)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if len(name) > 0 && !hasContainerdName(container.ID(), containerLabels, name) {
+			continue
+		}
+		if !hasLabels(containerLabels, labels) {
+			continue
+		}
+
+		state, err := containerdState(ctx, container)
+		if err != nil {
+			return nil, err
+		}
+		if state != ContainerStopped {
+			ids = append(ids, containerdProtocolPrefix+container.ID())
+		}
+	}
+	return ids, nil
+}
+
+func hasContainerdName(id string, labels map[string]string, name string) bool {
+	if id == name {
+		return true
+	}
+	for _, label := range containerdNameLabels {
+		if labels[label] == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasLabels(labels map[string]string, selector map[string]string) bool {
+	for key, value := range selector {
+		if v, ok := labels[key]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+func (c ContainerdClient) loadContainer(ctx context.Context, containerID string) (containerd.Container, error) {
+	id, err := c.FormatContainerID(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+	container, err := c.client.LoadContainer(ctx, id)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return container, nil
+}
+
+// loadTask returns the task of the container, it returns nil if the container has no task
+func loadTask(ctx context.Context, container containerd.Container) (containerd.Task, error) {
+	task, err := container.Task(ctx, nil)
+	if errdefs.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return task, nil
+}
+
+func containerdState(ctx context.Context, container containerd.Container) (string, error) {
+	task, err := loadTask(ctx, container)
+	if err != nil || task == nil {
+		return ContainerStopped, err
+	}
+	status, err := task.Status(ctx)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	switch status.Status {
+	case containerd.Running:
+		return ContainerRunning, nil
+	case containerd.Paused, containerd.Pausing:
+		return ContainerPaused, nil
+	}
+	return ContainerStopped, nil
+}
+
+// ContainerState returns the state of the container
+func (c ContainerdClient) ContainerState(ctx context.Context, containerID string) (string, error) {
+	container, err := c.loadContainer(ctx, containerID)
+	if err != nil {
+		return "", err
+	}
+	return containerdState(ctx, container)
+}
+
+// ContainerStop stops the task of the container with SIGTERM, it is killed if it does not exit in time.
+// The stopped task is deleted, so that the container can be started again.
+func (c ContainerdClient) ContainerStop(ctx context.Context, containerID string) error {
+	container, err := c.loadContainer(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	task, err := loadTask(ctx, container)
+	if err != nil || task == nil {
+		return err
+	}
+
+	exited, err := task.Wait(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := task.Kill(ctx, syscall.SIGTERM); err != nil && !errdefs.IsNotFound(err) {
+		return errors.WithStack(err)
+	}
+	select {
+	case <-exited:
+	case <-time.After(stopTimeout):
+		if err := task.Kill(ctx, syscall.SIGKILL); err != nil && !errdefs.IsNotFound(err) {
+			return errors.WithStack(err)
+		}
+		<-exited
+	}
+
+	if _, err := task.Delete(ctx); err != nil && !errdefs.IsNotFound(err) {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// ContainerStart starts a new task of the container, the stopped task of the container is deleted first.
+// The IO of the new task is discarded.
+func (c ContainerdClient) ContainerStart(ctx context.Context, containerID string) error {
+	container, err := c.loadContainer(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	task, err := loadTask(ctx, container)
+	if err != nil {
+		return err
+	}
+	if task != nil {
+		if _, err := task.Delete(ctx); err != nil && !errdefs.IsNotFound(err) {
+			return errors.WithStack(err)
+		}
+	}
+
+	task, err = container.NewTask(ctx, cio.NullIO)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(task.Start(ctx))
+}
+
+// ContainerRestart stops the container and starts it again
+func (c ContainerdClient) ContainerRestart(ctx context.Context, containerID string) error {
+	if err := c.ContainerStop(ctx, containerID); err != nil {
+		return err
+	}
+	return c.ContainerStart(ctx, containerID)
+}
+
+// ContainerPause freezes the processes of the container
+func (c ContainerdClient) ContainerPause(ctx context.Context, containerID string) error {
+	container, err := c.loadContainer(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	task, err := container.Task(ctx, nil)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(task.Pause(ctx))
+}
+
+// ContainerUnpause unfreezes the processes of the container
+func (c ContainerdClient) ContainerUnpause(ctx context.Context, containerID string) error {
+	container, err := c.loadContainer(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	task, err := container.Task(ctx, nil)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(task.Resume(ctx))
+}
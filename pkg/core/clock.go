@@ -61,6 +61,10 @@ func NewClockOption() *ClockOption {
 }
 
 func (opt *ClockOption) PreProcess() error {
+	if err := opt.CommonAttackConfig.Validate(); err != nil {
+		return err
+	}
+
 	clkIds := strings.Split(opt.ClockIdsSlice, ",")
 
 	offset, err := time.ParseDuration(opt.TimeOffset)
@@ -23,6 +23,8 @@ type AttackConfig interface {
 	Validate() error
 	Cron() string
 	ScheduleDuration() (*time.Duration, error)
+	// GetSchedulerConfig returns the schedule of the attack
+	GetSchedulerConfig() SchedulerConfig
 	// String is replacement of .Action
 	String() string
 	// RecoverData is replacement of earlier .String()
@@ -36,17 +38,53 @@ type AttackConfig interface {
 
 	// GetUID returns the experiment's ID
 	GetUID() string
+
+	// IsDryRun returns true if the attack should only be planned but not executed
+	IsDryRun() bool
+
+	// GetLabels returns the labels of the experiment, they can be used to select experiments
+	GetLabels() map[string]string
+	// GetAnnotations returns the annotations of the experiment, they are not used to select experiments
+	GetAnnotations() map[string]string
+
+	// GetProbes returns the steady-state probes of the experiment
+	GetProbes() []Probe
 }
 
 type SchedulerConfig struct {
 	Schedule string `json:"schedule"`
 	Duration string `json:"duration"`
+
+	// The options below are only used by the scheduled attacks.
+
+	// TimeZone is the time zone of the schedule, such as "Asia/Shanghai", the default value is UTC.
+	TimeZone string `json:"time_zone,omitempty"`
+	// StartAt and EndAt are the window of the runs in RFC3339, such as "2023-06-01T09:00:00+08:00".
+	// The schedule is finished after EndAt.
+	StartAt string `json:"start_at,omitempty"`
+	EndAt   string `json:"end_at,omitempty"`
+	// MaxRuns is the max number of the runs, the schedule is finished after that.
+	MaxRuns int `json:"max_runs,omitempty"`
+	// Jitter is the max random delay before each run, such as "30s". Its key is not "jitter",
+	// which is the jitter of the network delay attack.
+	Jitter string `json:"schedule_jitter,omitempty"`
+	// Blackouts are the windows in which no run may start.
+	Blackouts []BlackoutWindow `json:"blackouts,omitempty"`
+	// ConcurrencyPolicy decides what to do when a run starts before the previous one is recovered,
+	// it is one of Forbid, Replace and Allow, the default value is Forbid.
+	ConcurrencyPolicy string `json:"concurrency_policy,omitempty"`
+	// Suspend stops starting new runs, the run in progress is still recovered at the end of its duration.
+	Suspend bool `json:"suspend,omitempty"`
 }
 
 func (config SchedulerConfig) Cron() string {
 	return config.Schedule
 }
 
+func (config SchedulerConfig) GetSchedulerConfig() SchedulerConfig {
+	return config
+}
+
 func (config SchedulerConfig) ScheduleDuration() (*time.Duration, error) {
 	if len(config.Duration) == 0 {
 		return nil, nil
@@ -61,6 +99,16 @@ type CommonAttackConfig struct {
 	Action string `json:"action"`
 	Kind   string `json:"kind"`
 	UID    string `json:"uid"`
+	// DryRun means the attack is validated and planned, but not executed.
+	DryRun bool `json:"dry_run,omitempty"`
+
+	// Labels are used to tag and select experiments, such as team=payments.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations record any other information of the experiment, such as the game day it belongs to.
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Probes are the steady-state checks of the experiment, the experiment is aborted if any of them fails.
+	Probes []Probe `json:"probes,omitempty"`
 }
 
 func (config CommonAttackConfig) String() string {
@@ -81,9 +129,54 @@ func (config *CommonAttackConfig) Validate() error {
 			return errors.New("Provide a valid duration for the scheduled attack")
 		}
 	}
-	return nil
+	if err := config.SchedulerConfig.Validate(); err != nil {
+		return err
+	}
+	for _, probe := range config.Probes {
+		if err := probe.Validate(); err != nil {
+			return err
+		}
+	}
+	return ValidateLabels(config.Labels)
 }
 
 func (config *CommonAttackConfig) GetUID() string {
 	return config.UID
 }
+
+func (config *CommonAttackConfig) IsDryRun() bool {
+	return config.DryRun
+}
+
+func (config *CommonAttackConfig) GetLabels() map[string]string {
+	return config.Labels
+}
+
+func (config *CommonAttackConfig) GetAnnotations() map[string]string {
+	return config.Annotations
+}
+
+// SetLabels adds the labels and annotations to the config, the existing ones with the same keys are overwritten.
+func (config *CommonAttackConfig) SetLabels(labels, annotations map[string]string) {
+	if len(labels) > 0 && config.Labels == nil {
+		config.Labels = make(map[string]string, len(labels))
+	}
+	for k, v := range labels {
+		config.Labels[k] = v
+	}
+	if len(annotations) > 0 && config.Annotations == nil {
+		config.Annotations = make(map[string]string, len(annotations))
+	}
+	for k, v := range annotations {
+		config.Annotations[k] = v
+	}
+}
+
+func (config *CommonAttackConfig) GetProbes() []Probe {
+	return config.Probes
+}
+
+// AddProbes appends the probes to the probes of the config.
+func (config *CommonAttackConfig) AddProbes(probes []Probe) {
+	config.Probes = append(config.Probes, probes...)
+}
@@ -0,0 +1,108 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+const (
+	ContainerKillAction    = "kill"
+	ContainerStopAction    = "stop"
+	ContainerRestartAction = "restart"
+	// ContainerPauseAction pauses the containers, they are unpaused on recovery.
+	ContainerPauseAction = "pause"
+	// ContainerUnpauseAction unpauses the paused containers, they are paused again on recovery.
+	ContainerUnpauseAction = "unpause"
+)
+
+var _ AttackConfig = &ContainerCommand{}
+
+type ContainerCommand struct {
+	CommonAttackConfig
+
+	// ContainerIDs are the IDs of the containers with the protocol prefix of the runtime,
+	// such as "docker://<id>" or "containerd://<id>".
+	ContainerIDs []string `json:"container_ids,omitempty"`
+	// ContainerName and ContainerLabels select the running containers by the name and the labels,
+	// if ContainerIDs is not provided.
+	ContainerName   string            `json:"container_name,omitempty"`
+	ContainerLabels map[string]string `json:"container_labels,omitempty"`
+
+	// Attacked are the IDs of the attacked containers, they are recovered by the recovery.
+	Attacked []string `json:"attacked,omitempty"`
+}
+
+func (c *ContainerCommand) Validate() error {
+	if err := c.CommonAttackConfig.Validate(); err != nil {
+		return err
+	}
+	switch c.Action {
+	case ContainerKillAction, ContainerStopAction, ContainerRestartAction, ContainerPauseAction, ContainerUnpauseAction:
+	default:
+		return errors.Errorf("invalid action %s of container attack", c.Action)
+	}
+
+	if len(c.ContainerIDs) == 0 {
+		if len(c.ContainerName) == 0 && len(c.ContainerLabels) == 0 {
+			return errors.New("container ids, container name or container labels should be provided")
+		}
+		return nil
+	}
+	if len(c.ContainerName) > 0 || len(c.ContainerLabels) > 0 {
+		return errors.New("container ids can not be used with container name or container labels")
+	}
+	for _, id := range c.ContainerIDs {
+		if !strings.HasPrefix(id, "docker://") && !strings.HasPrefix(id, "containerd://") {
+			return errors.Errorf("invalid container id %s, it should start with docker:// or containerd://", id)
+		}
+	}
+	return nil
+}
+
+// Selector returns the description of the containers to be attacked.
+func (c *ContainerCommand) Selector() string {
+	if len(c.ContainerIDs) > 0 {
+		return strings.Join(c.ContainerIDs, ", ")
+	}
+
+	var selectors []string
+	if len(c.ContainerName) > 0 {
+		selectors = append(selectors, "name "+c.ContainerName)
+	}
+	labels := make([]string, 0, len(c.ContainerLabels))
+	for key, value := range c.ContainerLabels {
+		labels = append(labels, "label "+key+"="+value)
+	}
+	sort.Strings(labels)
+	return strings.Join(append(selectors, labels...), ", ")
+}
+
+func (c ContainerCommand) RecoverData() string {
+	data, _ := json.Marshal(c)
+
+	return string(data)
+}
+
+func NewContainerCommand() *ContainerCommand {
+	return &ContainerCommand{
+		CommonAttackConfig: CommonAttackConfig{
+			Kind: ContainerAttack,
+		},
+	}
+}
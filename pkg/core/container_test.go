@@ -0,0 +1,50 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainerCommand_Validate(t *testing.T) {
+	labels := map[string]string{"app": "shop", "tier": "web"}
+	for _, test := range []struct {
+		action  string
+		command ContainerCommand
+		valid   bool
+	}{
+		{"kill", ContainerCommand{ContainerIDs: []string{"docker://web", "containerd://db"}}, true},
+		{"stop", ContainerCommand{ContainerName: "web"}, true},
+		{"pause", ContainerCommand{ContainerName: "web", ContainerLabels: labels}, true},
+		{"unpause", ContainerCommand{ContainerIDs: []string{"containerd://db"}}, true},
+		{"restart", ContainerCommand{}, false},
+		{"kill", ContainerCommand{ContainerIDs: []string{"web"}}, false},
+		{"stop", ContainerCommand{ContainerIDs: []string{"docker://web"}, ContainerName: "web"}, false},
+		{"remove", ContainerCommand{ContainerName: "web"}, false},
+	} {
+		test.command.Kind = ContainerAttack
+		test.command.Action = test.action
+		test.command.CompleteDefaults()
+		if test.valid {
+			assert.NoError(t, test.command.Validate(), test.action)
+		} else {
+			assert.Error(t, test.command.Validate(), test.action)
+		}
+	}
+
+	command := ContainerCommand{ContainerName: "web", ContainerLabels: labels}
+	assert.Equal(t, "name web, label app=shop, label tier=web", command.Selector())
+}
@@ -19,4 +19,13 @@ var (
 	ErrNs                     = errorx.NewNamespace("error.core")
 	ErrAttackConfigValidation = ErrNs.NewType("attack_config_validation_error")
 	ErrNonRecoverableAttack   = ErrNs.NewType("non_recoverable_attack")
+	ErrHalted                 = ErrNs.NewType("halted")
+	// ErrAttackConflict is returned when an attack conflicts with an active experiment on the same resource
+	ErrAttackConflict = ErrNs.NewType("attack_conflict")
+	// ErrPolicyViolation is returned when an attack violates the policy of chaosd server,
+	// it is a subtype of ErrAttackConfigValidation.
+	ErrPolicyViolation = ErrAttackConfigValidation.NewSubtype("policy_violation")
+
+	// PolicyRule is the rule of the policy violated by an attack
+	PolicyRule = errorx.RegisterPrintableProperty("policy_rule")
 )
@@ -28,6 +28,9 @@ const (
 	Scheduled = "scheduled"
 	Destroyed = "destroyed"
 	Revoked   = "revoked"
+	Paused    = "paused"
+	// Aborted means the experiment is recovered automatically because one of its probes failed.
+	Aborted = "aborted"
 )
 
 const (
@@ -45,6 +48,8 @@ const (
 	HTTPAttack        = "http"
 	VMAttack          = "vm"
 	UserDefinedAttack = "userDefined"
+	SystemdAttack     = "systemd"
+	ContainerAttack   = "container"
 )
 
 const (
@@ -76,10 +81,25 @@ type Experiment struct {
 	Action         string `json:"action"`
 	RecoverCommand string `json:"recover_command"`
 	LaunchMode     string `json:"launch_mode"`
+	// Deadline is the time when a non-scheduled experiment should be recovered automatically,
+	// it is nil if the experiment has no duration.
+	Deadline *time.Time `json:"deadline,omitempty"`
+	// Labels and Annotations are copied from the config of the experiment.
+	Labels      Labels `gorm:"type:text" json:"labels,omitempty"`
+	Annotations Labels `gorm:"type:text" json:"annotations,omitempty"`
 
 	cachedRequestCommand AttackConfig
 }
 
+// WithRecoverCommand returns a copy of the experiment with the recover command, such as the config of one of
+// its runs. The config decoded from the copy is not shared with the experiment.
+func (exp *Experiment) WithRecoverCommand(recoverCommand string) Experiment {
+	copied := *exp
+	copied.RecoverCommand = recoverCommand
+	copied.cachedRequestCommand = nil
+	return copied
+}
+
 func (exp *Experiment) GetRequestCommand() (AttackConfig, error) {
 	if exp.cachedRequestCommand != nil {
 		return exp.cachedRequestCommand, nil
@@ -97,40 +117,26 @@ func (exp *Experiment) GetRequestCommand() (AttackConfig, error) {
 	return *attackConfig, nil
 }
 
+// attackConfigFactories are the factories of the configs of the attack kinds, they are registered
+// with the attack kinds in package chaosd.
+var attackConfigFactories = make(map[string]func() AttackConfig)
+
+// RegisterAttackConfig registers the factory of the config of an attack kind,
+// the factory is used to decode the configs stored in the DB.
+// It should be called in init, it panics if the kind has been registered.
+func RegisterAttackConfig(kind string, factory func() AttackConfig) {
+	if _, ok := attackConfigFactories[kind]; ok {
+		panic("attack config of kind " + kind + " has been registered")
+	}
+	attackConfigFactories[kind] = factory
+}
+
 func GetAttackByKind(kind string) *AttackConfig {
-	var attackConfig AttackConfig
-	switch kind {
-	case ProcessAttack:
-		attackConfig = &ProcessCommand{}
-	case NetworkAttack:
-		attackConfig = &NetworkCommand{}
-	case HostAttack:
-		attackConfig = &HostCommand{}
-	case StressAttack:
-		attackConfig = &StressCommand{}
-	case DiskAttack:
-		attackConfig = &DiskAttackConfig{}
-	case DiskServerAttack:
-		attackConfig = &DiskAttackConfig{}
-	case JVMAttack:
-		attackConfig = &JVMCommand{}
-	case ClockAttack:
-		attackConfig = &ClockOption{}
-	case KafkaAttack:
-		attackConfig = &KafkaCommand{}
-	case RedisAttack:
-		attackConfig = &RedisCommand{}
-	case FileAttack:
-		attackConfig = &FileCommand{}
-	case HTTPAttack:
-		attackConfig = &HTTPAttackConfig{}
-	case VMAttack:
-		attackConfig = &VMOption{}
-	case UserDefinedAttack:
-		attackConfig = &UserDefinedOption{}
-	default:
+	factory, ok := attackConfigFactories[kind]
+	if !ok {
 		return nil
 	}
 
+	attackConfig := factory()
 	return &attackConfig
 }
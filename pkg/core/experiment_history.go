@@ -0,0 +1,44 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	HistoryUpdated = "updated"
+	// HistoryProcessKilled is recorded when the crash-loop attack kills a process.
+	HistoryProcessKilled = "process-killed"
+)
+
+// ExperimentHistoryStore defines operations for working with the history of experiments
+type ExperimentHistoryStore interface {
+	ListByExperimentUID(ctx context.Context, uid string) ([]*ExperimentHistory, error)
+
+	Add(ctx context.Context, history *ExperimentHistory) error
+}
+
+// ExperimentHistory represents a change of an experiment, Before and After
+// are the configs of the experiment before and after the change.
+// For the events happened while the experiment is running, After is the detail of the event.
+type ExperimentHistory struct {
+	ID            uint      `gorm:"primary_key" json:"id"`
+	ExperimentUID string    `gorm:"index:experiment_uid" json:"experiment_uid"`
+	CreatedAt     time.Time `json:"created_at"`
+	Event         string    `json:"event"`
+	Before        string    `json:"before"`
+	After         string    `json:"after"`
+}
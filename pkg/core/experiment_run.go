@@ -25,6 +25,10 @@ const (
 	RunFailed    = "failed"
 	RunSuccess   = "success"
 	RunRecovered = "recovered"
+	// RunSkipped is the run which is not executed by its schedule, such as in a blackout window
+	RunSkipped = "skipped"
+	// RunAborted is the run recovered before its end because one of the probes of its experiment failed
+	RunAborted = "aborted"
 )
 
 // ExperimentRunStore defines operations for working with experiment runs
@@ -32,21 +36,27 @@ type ExperimentRunStore interface {
 	ListByExperimentID(ctx context.Context, id uint) ([]*ExperimentRun, error)
 	ListByExperimentUID(ctx context.Context, uid string) ([]*ExperimentRun, error)
 	LatestRun(ctx context.Context, id uint) (*ExperimentRun, error)
+	ListByStatus(ctx context.Context, status string) ([]*ExperimentRun, error)
 
 	NewRun(ctx context.Context, expRun *ExperimentRun) error
 	Update(ctx context.Context, runUid string, status string, message string) error
+	// SetRecoverCommand records the data to recover the run
+	SetRecoverCommand(ctx context.Context, runUid string, recoverCommand string) error
 }
 
 // ExperimentRun represents a run of an experiment
 type ExperimentRun struct {
-	ID           uint      `gorm:"primary_key" json:"id"`
-	UID          string    `gorm:"index:uid" json:"uid"`
-	StartAt      time.Time `gorm:"autoCreateTime" json:"start_at"`
-	FinishedAt   time.Time `json:"finished_at"`
-	Status       string    `json:"status"`
-	Message      string    `json:"error"`
-	ExperimentID uint
-	Experiment   Experiment `gorm:"foreignKey:ExperimentID" json:"experiment"`
+	ID         uint      `gorm:"primary_key" json:"id"`
+	UID        string    `gorm:"index:uid" json:"uid"`
+	StartAt    time.Time `gorm:"autoCreateTime" json:"start_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Status     string    `json:"status"`
+	Message    string    `json:"error"`
+	// RecoverCommand is the data to recover the run, such as the pid of the process started by the run.
+	// The run is recovered with the config of its experiment if it is empty.
+	RecoverCommand string `json:"recover_command,omitempty"`
+	ExperimentID   uint
+	Experiment     Experiment `gorm:"foreignKey:ExperimentID" json:"experiment"`
 }
 
 func (exp Experiment) NewRun() *ExperimentRun {
@@ -0,0 +1,35 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"time"
+)
+
+// HaltStateStore defines operations for working with the halt state of chaosd
+type HaltStateStore interface {
+	// Get returns the halt state, chaosd is not halted if the state has never been set.
+	Get(ctx context.Context) (*HaltState, error)
+	Set(ctx context.Context, state *HaltState) error
+}
+
+// HaltState records whether chaosd is halted, no attack can be executed while it is halted.
+// It is persisted so that chaosd stays halted after a restart.
+type HaltState struct {
+	ID        uint      `gorm:"primary_key" json:"-"`
+	Halted    bool      `json:"halted"`
+	Reason    string    `json:"reason,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
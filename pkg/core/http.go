@@ -47,11 +47,25 @@ type HTTPAttackConfig struct {
 	Config   tproxyconfig.Config
 	ProxyPID int
 
-	Logger logr.Logger
+	// Logger is not stored, the configs decoded from the DB log nothing.
+	Logger logr.Logger `json:"-"`
 
 	HTTPRequestConfig
 }
 
+// GetLogger returns the logger of the attack, it discards the logs if the logger is not set.
+func (c *HTTPAttackConfig) GetLogger() logr.Logger {
+	if c.Logger.GetSink() == nil {
+		return logr.Discard()
+	}
+	return c.Logger
+}
+
+// AllowConcurrentRuns implements ConcurrentRunsAttack, the runs sending requests don't affect each other.
+func (c HTTPAttackConfig) AllowConcurrentRuns() bool {
+	return c.Action == HTTPRequestAction
+}
+
 func (c HTTPAttackConfig) RecoverData() string {
 	data, _ := json.Marshal(c)
 	return string(data)
@@ -90,6 +104,10 @@ func NewHTTPAttackOption() *HTTPAttackOption {
 }
 
 func (o *HTTPAttackOption) PreProcess() (*HTTPAttackConfig, error) {
+	if err := o.CommonAttackConfig.Validate(); err != nil {
+		return nil, err
+	}
+
 	var c tproxyconfig.Config
 	zapLogger, err := zap.NewDevelopment()
 	if err != nil {
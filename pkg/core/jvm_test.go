@@ -30,6 +30,17 @@ func TestJVMCommand(t *testing.T) {
 			&JVMCommand{},
 			"pid can't be 0",
 		},
+		{
+			&JVMCommand{
+				CommonAttackConfig: CommonAttackConfig{
+					SchedulerConfig: SchedulerConfig{Schedule: "@every 1m"},
+				},
+				JVMCommonSpec: JVMCommonSpec{
+					Pid: 1234,
+				},
+			},
+			"Provide a valid duration for the scheduled attack",
+		},
 		{
 			&JVMCommand{
 				JVMCommonSpec: JVMCommonSpec{
@@ -0,0 +1,87 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Labels is a set of key/value pairs attached to an experiment, such as team=payments.
+// It is stored as a JSON object in the DB.
+type Labels map[string]string
+
+// Value implements driver.Valuer interface
+func (l Labels) Value() (driver.Value, error) {
+	if len(l) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(l)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner interface
+func (l *Labels) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return errors.Errorf("can not scan %T into labels", value)
+	}
+
+	if len(data) == 0 {
+		return nil
+	}
+	return errors.WithStack(json.Unmarshal(data, l))
+}
+
+// Matches returns true if the labels match the selector
+func (l Labels) Matches(selector labels.Selector) bool {
+	return selector.Matches(labels.Set(l))
+}
+
+// ParseSelector parses a label selector, such as "team=payments,env!=prod" or "tier in (web,db)".
+func ParseSelector(selector string) (labels.Selector, error) {
+	s, err := labels.Parse(selector)
+	if err != nil {
+		return nil, errors.Annotatef(err, "invalid selector %s", selector)
+	}
+	return s, nil
+}
+
+// ValidateLabels checks the keys and values of the labels are valid as Kubernetes labels.
+func ValidateLabels(l map[string]string) error {
+	for k, v := range l {
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return errors.Errorf("invalid label key %s: %s", k, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+			return errors.Errorf("invalid value of label %s: %s", k, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
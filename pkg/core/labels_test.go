@@ -0,0 +1,77 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabels_ValueAndScan(t *testing.T) {
+	l := Labels{"team": "payments", "gameday": "q3"}
+	value, err := l.Value()
+	assert.NoError(t, err)
+
+	var scanned Labels
+	assert.NoError(t, scanned.Scan(value))
+	assert.Equal(t, l, scanned)
+
+	var empty Labels
+	value, err = empty.Value()
+	assert.NoError(t, err)
+	assert.NoError(t, empty.Scan(value))
+	assert.Nil(t, empty)
+	assert.NoError(t, empty.Scan(nil))
+	assert.Error(t, empty.Scan(1))
+}
+
+func TestLabels_Matches(t *testing.T) {
+	l := Labels{"team": "payments", "env": "staging"}
+
+	for selector, matched := range map[string]bool{
+		"team=payments":                     true,
+		"team=payments,env!=prod":           true,
+		"env in (prod,staging)":             true,
+		"team=search":                       false,
+		"owner":                             false,
+		"!owner":                            true,
+		"team=payments,env notin (staging)": false,
+	} {
+		s, err := ParseSelector(selector)
+		assert.NoError(t, err, selector)
+		assert.Equal(t, matched, l.Matches(s), selector)
+	}
+
+	_, err := ParseSelector("team in (payments")
+	assert.Error(t, err)
+}
+
+func TestCommonAttackConfig_Labels(t *testing.T) {
+	config := &CommonAttackConfig{Labels: map[string]string{"team": "search"}}
+	config.SetLabels(map[string]string{"team": "payments", "gameday": "q3"}, map[string]string{"note": "any text"})
+	assert.Equal(t, map[string]string{"team": "payments", "gameday": "q3"}, config.GetLabels())
+	assert.Equal(t, map[string]string{"note": "any text"}, config.GetAnnotations())
+	assert.NoError(t, config.Validate())
+
+	config.SetLabels(map[string]string{"team": "not valid"}, nil)
+	assert.Error(t, config.Validate())
+	config.SetLabels(map[string]string{"team": "payments", "-bad": "key"}, nil)
+	assert.Error(t, config.Validate())
+}
+
+func TestSearchCommand_ValidateSelector(t *testing.T) {
+	assert.NoError(t, SearchCommand{Selector: "team=payments"}.Validate())
+	assert.Error(t, SearchCommand{Selector: "team in (payments"}.Validate())
+}
@@ -66,6 +66,12 @@ type NetworkCommand struct {
 	// used for flood
 	// the pid of iperf
 	IperfPid int32 `json:"iperf-pid,omitempty"`
+
+	// ContainerID or PID selects the network namespace to be attacked instead of the one of the host,
+	// the ipset, iptables and tc rules are injected in the network namespace of the container or the process.
+	// ContainerID has the protocol prefix of the runtime, such as "docker://<id>" or "containerd://<id>".
+	ContainerID string `json:"container-id,omitempty"`
+	PID         int    `json:"pid,omitempty"`
 }
 
 var _ AttackConfig = &NetworkCommand{}
@@ -89,6 +95,9 @@ func (n *NetworkCommand) Validate() error {
 	if err := n.CommonAttackConfig.Validate(); err != nil {
 		return err
 	}
+	if err := n.validNetworkNamespace(); err != nil {
+		return err
+	}
 	switch n.Action {
 	case NetworkDelayAction:
 		return n.validNetworkDelay()
@@ -111,6 +120,37 @@ func (n *NetworkCommand) Validate() error {
 	}
 }
 
+func (n *NetworkCommand) validNetworkNamespace() error {
+	if len(n.ContainerID) == 0 && n.PID == 0 {
+		return nil
+	}
+	if !n.NeedApplyTC() && n.Action != NetworkPartitionAction {
+		return errors.Errorf("container id and pid are not supported by network %s attack", n.Action)
+	}
+	if len(n.ContainerID) > 0 && n.PID != 0 {
+		return errors.New("container id and pid can not be used together")
+	}
+	if n.PID < 0 {
+		return errors.Errorf("invalid pid %d", n.PID)
+	}
+	if len(n.ContainerID) > 0 && !strings.HasPrefix(n.ContainerID, "docker://") && !strings.HasPrefix(n.ContainerID, "containerd://") {
+		return errors.Errorf("invalid container id %s, it should start with docker:// or containerd://", n.ContainerID)
+	}
+	return nil
+}
+
+// NetNSTarget returns the target whose network namespace is attacked, it is the container ID, or "pid://<pid>"
+// for the process. It is empty for the network namespace of the host.
+func (n *NetworkCommand) NetNSTarget() string {
+	if len(n.ContainerID) > 0 {
+		return n.ContainerID
+	}
+	if n.PID > 0 {
+		return fmt.Sprintf("pid://%d", n.PID)
+	}
+	return ""
+}
+
 func (n *NetworkCommand) validNetworkDelay() error {
 	if len(n.Latency) == 0 {
 		return errors.New("delay is required")
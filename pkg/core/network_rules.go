@@ -25,6 +25,7 @@ import (
 
 	"github.com/pingcap/errors"
 
+	"github.com/chaos-mesh/chaos-mesh/pkg/chaosdaemon/netem"
 	"github.com/chaos-mesh/chaos-mesh/pkg/chaosdaemon/pb"
 )
 
@@ -43,6 +44,22 @@ type IPSetRule struct {
 	Cidrs string `json:"cidrs"`
 	// Experiment represents the experiment which the rule belong to.
 	Experiment string `gorm:"index:experiment" json:"experiment"`
+	// Target is the container or the process whose network namespace the rule is applied in,
+	// it is empty for the host. See NetworkCommand.NetNSTarget.
+	Target string `json:"target,omitempty"`
+}
+
+type IPSetRuleList []*IPSetRule
+
+// OfTarget returns the rules applied in the network namespace of the target.
+func (l IPSetRuleList) OfTarget(target string) IPSetRuleList {
+	rules := make(IPSetRuleList, 0, len(l))
+	for _, rule := range l {
+		if rule.Target == target {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
 }
 
 type Cidr struct {
@@ -70,6 +87,9 @@ type IptablesRule struct {
 	Direction string `json:"direction"`
 	// Experiment represents the experiment which the rule belong to.
 	Experiment string `gorm:"index:experiment" json:"experiment"`
+	// Target is the container or the process whose network namespace the rule is applied in,
+	// it is empty for the host.
+	Target string `json:"target,omitempty"`
 
 	Protocol string `json:"protocol"`
 }
@@ -97,6 +117,17 @@ func (l IptablesRuleList) ToChains() []*pb.Chain {
 	return chains
 }
 
+// OfTarget returns the rules applied in the network namespace of the target.
+func (l IptablesRuleList) OfTarget(target string) IptablesRuleList {
+	rules := make(IptablesRuleList, 0, len(l))
+	for _, rule := range l {
+		if rule.Target == target {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
 type TCRuleStore interface {
 	List(ctx context.Context) ([]*TCRule, error)
 	ListGroupDevice(ctx context.Context) (map[string][]*TCRule, error)
@@ -116,6 +147,9 @@ type TCRule struct {
 	IPSet string `json:"ipset,omitempty"`
 	// Experiment represents the experiment which the rule belong to.
 	Experiment string `gorm:"index:experiment" json:"experiment"`
+	// Target is the container or the process whose network namespace the rule is applied in,
+	// it is empty for the host.
+	Target string `json:"target,omitempty"`
 
 	Protocal   string
 	SourcePort string
@@ -124,6 +158,7 @@ type TCRule struct {
 
 func (t *TCRule) ToTC() (*pb.Tc, error) {
 	tc := &pb.Tc{
+		Device:     t.Device,
 		Ipset:      t.IPSet,
 		Protocol:   t.Protocal,
 		SourcePort: t.SourcePort,
@@ -159,6 +194,17 @@ func (t *TCRule) ToTC() (*pb.Tc, error) {
 
 type TCRuleList []*TCRule
 
+// OfTarget returns the rules applied in the network namespace of the target.
+func (t TCRuleList) OfTarget(target string) TCRuleList {
+	rules := make(TCRuleList, 0, len(t))
+	for _, rule := range t {
+		if rule.Target == target {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
 func (t TCRuleList) ToTCs() ([]*pb.Tc, error) {
 	tcs := make([]*pb.Tc, 0)
 	for _, rule := range t {
@@ -170,7 +216,40 @@ func (t TCRuleList) ToTCs() ([]*pb.Tc, error) {
 		tcs = append(tcs, tc)
 	}
 
-	return tcs, nil
+	return MergeTCs(tcs), nil
+}
+
+// MergeTCs merges the netem tcs which filter the same traffic into one netem tc, such as a delay and a loss
+// of the same device, because they would be piped one by one in the same band of the tc tree.
+// The order of the tcs is kept.
+func MergeTCs(tcs []*pb.Tc) []*pb.Tc {
+	merged := make([]*pb.Tc, 0, len(tcs))
+	netems := make(map[string]*pb.Tc)
+	for _, tc := range tcs {
+		if tc.Type != pb.Tc_NETEM {
+			merged = append(merged, tc)
+			continue
+		}
+
+		filter := strings.Join([]string{tc.Device, tc.Ipset, tc.Protocol, tc.SourcePort, tc.EgressPort}, "|")
+		if existing, ok := netems[filter]; ok {
+			existing.Netem = netem.MergeNetem(existing.Netem, tc.Netem)
+			continue
+		}
+
+		copied := &pb.Tc{
+			Type:       tc.Type,
+			Netem:      tc.Netem,
+			Ipset:      tc.Ipset,
+			Protocol:   tc.Protocol,
+			SourcePort: tc.SourcePort,
+			EgressPort: tc.EgressPort,
+			Device:     tc.Device,
+		}
+		netems[filter] = copied
+		merged = append(merged, copied)
+	}
+	return merged
 }
 
 // TcParameter represents the parameters for a traffic control chaos
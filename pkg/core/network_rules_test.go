@@ -0,0 +1,41 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/chaos-mesh/chaos-mesh/pkg/chaosdaemon/pb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeTCs(t *testing.T) {
+	delay := &pb.Tc{Type: pb.Tc_NETEM, Device: "eth0", Netem: &pb.Netem{Time: 100000}}
+	loss := &pb.Tc{Type: pb.Tc_NETEM, Device: "eth0", Netem: &pb.Netem{Loss: 50}}
+	filtered := &pb.Tc{Type: pb.Tc_NETEM, Device: "eth0", Ipset: "chaos-a", Netem: &pb.Netem{Loss: 10}}
+	bandwidth := &pb.Tc{Type: pb.Tc_BANDWIDTH, Device: "eth0", Tbf: &pb.Tbf{Rate: 1024}}
+	other := &pb.Tc{Type: pb.Tc_NETEM, Device: "eth1", Netem: &pb.Netem{Loss: 20}}
+
+	tcs := MergeTCs([]*pb.Tc{delay, bandwidth, filtered, loss, other})
+	assert.Len(t, tcs, 4)
+	assert.Equal(t, uint32(100000), tcs[0].Netem.Time)
+	assert.Equal(t, float32(50), tcs[0].Netem.Loss)
+	assert.Equal(t, bandwidth, tcs[1])
+	assert.Equal(t, "chaos-a", tcs[2].Ipset)
+	assert.Equal(t, float32(10), tcs[2].Netem.Loss)
+	assert.Equal(t, "eth1", tcs[3].Device)
+
+	// the tcs passed in are not changed
+	assert.Equal(t, float32(0), delay.Netem.Loss)
+}
@@ -14,9 +14,11 @@
 package core
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/chaos-mesh/chaos-mesh/pkg/chaosdaemon/pb"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestPatitionChain(t *testing.T) {
@@ -154,3 +156,48 @@ func TestPatitionChain(t *testing.T) {
 		}
 	})
 }
+
+func TestNetworkCommand_NetNSTarget(t *testing.T) {
+	for _, test := range []struct {
+		action      string
+		containerID string
+		pid         int
+		target      string
+		valid       bool
+	}{
+		{NetworkLossAction, "", 0, "", true},
+		{NetworkLossAction, "docker://web", 0, "docker://web", true},
+		{NetworkPartitionAction, "containerd://web", 0, "containerd://web", true},
+		{NetworkLossAction, "", 1234, "pid://1234", true},
+		{NetworkLossAction, "web", 0, "web", false},
+		{NetworkLossAction, "docker://web", 1234, "docker://web", false},
+		{NetworkLossAction, "", -1, "", false},
+		{NetworkNICDownAction, "docker://web", 0, "docker://web", false},
+	} {
+		command := NewNetworkCommand()
+		command.Action, command.ContainerID, command.PID = test.action, test.containerID, test.pid
+		command.Device, command.IPAddress, command.Percent, command.Direction = "eth0", "10.0.0.1", "10", "both"
+		command.CompleteDefaults()
+
+		assert.Equal(t, test.target, command.NetNSTarget())
+		if test.valid {
+			assert.NoError(t, command.Validate(), "%+v", test)
+		} else {
+			assert.Error(t, command.Validate(), "%+v", test)
+		}
+	}
+}
+
+func TestNetworkCommand_DecodeJitter(t *testing.T) {
+	command := NewNetworkCommand()
+	assert.NoError(t, json.Unmarshal([]byte(`{"action":"delay","latency":"10ms","jitter":"5ms",`+
+		`"schedule":"@every 1h","duration":"10m","schedule_jitter":"30s"}`), command))
+	assert.Equal(t, "5ms", command.Jitter)
+	assert.Equal(t, "30s", command.SchedulerConfig.Jitter)
+
+	// the jitters are kept apart in the recover data
+	decoded := NewNetworkCommand()
+	assert.NoError(t, json.Unmarshal([]byte(command.RecoverData()), decoded))
+	assert.Equal(t, "5ms", decoded.Jitter)
+	assert.Equal(t, "30s", decoded.SchedulerConfig.Jitter)
+}
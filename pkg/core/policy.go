@@ -0,0 +1,162 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	MaxActiveExperimentsRule        = "max_active_experiments"
+	MaxActiveExperimentsPerKindRule = "max_active_experiments_per_kind"
+	ProtectedProcessesRule          = "protected_processes"
+	ProtectedPIDsRule               = "protected_pids"
+	ProtectedDevicesRule            = "protected_devices"
+	ProtectedPathsRule              = "protected_paths"
+	ProtectedCIDRsRule              = "protected_cidrs"
+)
+
+// Policy limits the blast radius of the attacks executed by chaosd server.
+// The rules which are empty or zero are not enforced.
+type Policy struct {
+	// MaxActiveExperiments is the max number of the active experiments.
+	MaxActiveExperiments int `json:"max_active_experiments,omitempty"`
+	// MaxActiveExperimentsPerKind is the max number of the active experiments of the kinds.
+	MaxActiveExperimentsPerKind map[string]int `json:"max_active_experiments_per_kind,omitempty"`
+
+	// ProtectedProcesses are the names of the processes which the process attacks may never signal.
+	// chaosd itself is always protected.
+	ProtectedProcesses []string `json:"protected_processes,omitempty"`
+	// ProtectedPIDs are the PIDs of the processes which the process attacks may never signal.
+	ProtectedPIDs []int `json:"protected_pids,omitempty"`
+
+	// ProtectedDevices are the network devices which the network attacks may not touch.
+	ProtectedDevices []string `json:"protected_devices,omitempty"`
+
+	// ProtectedPaths are the path prefixes which the file and disk attacks may not modify.
+	ProtectedPaths []string `json:"protected_paths,omitempty"`
+
+	// ProtectedCIDRs are the CIDRs which must never be partitioned.
+	ProtectedCIDRs []string `json:"protected_cidrs,omitempty"`
+}
+
+// DefaultPolicyFile is the policy file loaded if no policy file is specified, so that the policy
+// is enforced on the attacks executed by both chaosd server and the attack commands.
+const DefaultPolicyFile = "/etc/chaosd/policy.yaml"
+
+var defaultPolicyFile = DefaultPolicyFile
+
+// LoadPolicy loads the policy from a YAML or JSON file. If the path is empty, it loads
+// DefaultPolicyFile, and returns nil if DefaultPolicyFile does not exist.
+func LoadPolicy(path string) (*Policy, error) {
+	if len(path) == 0 {
+		if _, err := os.Stat(defaultPolicyFile); os.IsNotExist(err) {
+			return nil, nil
+		}
+		path = defaultPolicyFile
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Annotatef(err, "failed to read policy file %s", path)
+	}
+
+	policy := &Policy{}
+	if err := yaml.UnmarshalStrict(data, policy); err != nil {
+		return nil, errors.Annotatef(err, "failed to parse policy file %s", path)
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, errors.Annotatef(err, "invalid policy file %s", path)
+	}
+	return policy, nil
+}
+
+func (p *Policy) Validate() error {
+	if p.MaxActiveExperiments < 0 {
+		return errors.Errorf("%s should not be negative", MaxActiveExperimentsRule)
+	}
+	for kind, max := range p.MaxActiveExperimentsPerKind {
+		if max < 0 {
+			return errors.Errorf("%s of %s should not be negative", MaxActiveExperimentsPerKindRule, kind)
+		}
+	}
+	for _, path := range p.ProtectedPaths {
+		if !filepath.IsAbs(path) {
+			return errors.Errorf("protected path %s should be absolute", path)
+		}
+	}
+	for _, cidr := range p.ProtectedCIDRs {
+		if _, err := parseCIDR(cidr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsProtectedPath returns true if the path is one of the protected paths or under them.
+func (p *Policy) IsProtectedPath(path string) (string, bool) {
+	path = filepath.Clean(path)
+	for _, protected := range p.ProtectedPaths {
+		protected = filepath.Clean(protected)
+		if path == protected || protected == "/" || strings.HasPrefix(path, protected+"/") {
+			return protected, true
+		}
+	}
+	return "", false
+}
+
+// OverlapsProtectedCIDR returns the protected CIDR overlapping with the CIDR.
+func (p *Policy) OverlapsProtectedCIDR(cidr string) (string, bool, error) {
+	target, err := parseCIDR(cidr)
+	if err != nil {
+		return "", false, err
+	}
+	for _, protected := range p.ProtectedCIDRs {
+		protectedNet, err := parseCIDR(protected)
+		if err != nil {
+			return "", false, err
+		}
+		if protectedNet.Contains(target.IP) || target.Contains(protectedNet.IP) {
+			return protected, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// parseCIDR parses the CIDR or the IP, which is regarded as a CIDR of a single address.
+func parseCIDR(s string) (*net.IPNet, error) {
+	if !strings.Contains(s, "/") {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return nil, errors.Errorf("invalid CIDR %s", s)
+		}
+		bits := 8 * net.IPv6len
+		if ip4 := ip.To4(); ip4 != nil {
+			ip, bits = ip4, 8*net.IPv4len
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+	}
+
+	_, ipNet, err := net.ParseCIDR(s)
+	if err != nil {
+		return nil, errors.Errorf("invalid CIDR %s", s)
+	}
+	return ipNet, nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadPolicy(t *testing.T) {
+	dir := t.TempDir()
+	defaultPolicyFile = filepath.Join(dir, "default.yaml")
+	defer func() { defaultPolicyFile = DefaultPolicyFile }()
+
+	policy, err := LoadPolicy("")
+	assert.NoError(t, err)
+	assert.Nil(t, policy)
+
+	assert.NoError(t, os.WriteFile(defaultPolicyFile, []byte("protected_pids: [1]"), 0600))
+	policy, err = LoadPolicy("")
+	assert.NoError(t, err)
+	assert.Equal(t, &Policy{ProtectedPIDs: []int{1}}, policy)
+
+	path := filepath.Join(dir, "policy.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(`
+max_active_experiments: 3
+max_active_experiments_per_kind:
+  stress: 1
+protected_processes: [sshd, systemd]
+protected_devices: [eth0]
+protected_paths: [/etc]
+protected_cidrs: [10.0.0.0/8]
+`), 0600))
+	policy, err = LoadPolicy(path)
+	assert.NoError(t, err)
+	assert.Equal(t, &Policy{
+		MaxActiveExperiments:        3,
+		MaxActiveExperimentsPerKind: map[string]int{"stress": 1},
+		ProtectedProcesses:          []string{"sshd", "systemd"},
+		ProtectedDevices:            []string{"eth0"},
+		ProtectedPaths:              []string{"/etc"},
+		ProtectedCIDRs:              []string{"10.0.0.0/8"},
+	}, policy)
+
+	for _, content := range []string{
+		"max_active_experiments: -1",
+		"protected_paths: [etc]",
+		"protected_cidrs: [10.0.0.0/33]",
+		"unknown_rule: true",
+	} {
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		_, err = LoadPolicy(path)
+		assert.Error(t, err, content)
+	}
+
+	_, err = LoadPolicy(filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestPolicy_IsProtectedPath(t *testing.T) {
+	policy := &Policy{ProtectedPaths: []string{"/etc", "/var/lib/mysql/"}}
+	for path, protected := range map[string]bool{
+		"/etc":                 true,
+		"/etc/passwd":          true,
+		"/etc/../tmp/a":        false,
+		"/etcetera":            false,
+		"/var/lib/mysql":       true,
+		"/var/lib/mysql/ibd":   true,
+		"/var/lib/mysql-files": false,
+	} {
+		_, ok := policy.IsProtectedPath(path)
+		assert.Equal(t, protected, ok, path)
+	}
+}
+
+func TestPolicy_OverlapsProtectedCIDR(t *testing.T) {
+	policy := &Policy{ProtectedCIDRs: []string{"10.0.0.0/8", "192.168.1.1"}}
+	for cidr, overlapped := range map[string]bool{
+		"10.1.2.3/32":    true,
+		"10.1.2.3":       true,
+		"0.0.0.0/0":      true,
+		"192.168.1.0/24": true,
+		"192.168.2.1":    false,
+		"172.16.0.0/12":  false,
+	} {
+		_, ok, err := policy.OverlapsProtectedCIDR(cidr)
+		assert.NoError(t, err)
+		assert.Equal(t, overlapped, ok, cidr)
+	}
+
+	_, _, err := policy.OverlapsProtectedCIDR("10.0.0")
+	assert.Error(t, err)
+}
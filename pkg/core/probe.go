@@ -0,0 +1,196 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+)
+
+const (
+	HTTPProbe    = "http"
+	TCPProbe     = "tcp"
+	CommandProbe = "command"
+)
+
+const (
+	DefaultProbeInterval = 10 * time.Second
+	DefaultProbeTimeout  = 5 * time.Second
+)
+
+// Probe is a steady-state check of an experiment. It is checked before the attack is injected,
+// periodically while the attack is running and after the attack is recovered.
+// The experiment is aborted if the probe fails more than Tolerance times in a row while the attack is running.
+type Probe struct {
+	Name string `json:"name,omitempty"`
+	Type string `json:"type"`
+
+	// used for http probe
+	URL    string `json:"url,omitempty"`
+	Method string `json:"method,omitempty"`
+	// ExpectedStatus is the expected status code of the response, the default value is 200.
+	ExpectedStatus int `json:"expected_status,omitempty"`
+	// MaxLatency is the max latency of the response, such as "200ms", the latency is not checked if it is empty.
+	MaxLatency string `json:"max_latency,omitempty"`
+
+	// used for tcp probe, such as "127.0.0.1:3306"
+	Address string `json:"address,omitempty"`
+
+	// used for command probe, the probe succeeds if the command exits with 0
+	Command string `json:"command,omitempty"`
+
+	// Interval is the interval of the checks while the attack is running, the default value is 10s.
+	Interval string `json:"interval,omitempty"`
+	// Timeout is the timeout of a check, the default value is 5s.
+	Timeout string `json:"timeout,omitempty"`
+	// Tolerance is the number of the failures in a row tolerated while the attack is running.
+	Tolerance int `json:"tolerance,omitempty"`
+}
+
+// ParseProbe parses the probe from a JSON object or comma separated key=value pairs whose keys are
+// the JSON keys of Probe, such as "type=http,url=http://127.0.0.1:8080/health,max_latency=200ms".
+// The value of command must be the last pair, because it may contain commas.
+func ParseProbe(s string) (Probe, error) {
+	var probe Probe
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "{") {
+		if err := json.Unmarshal([]byte(s), &probe); err != nil {
+			return probe, errors.Annotatef(err, "invalid probe %s", s)
+		}
+		return probe, nil
+	}
+
+	fields := make(map[string]interface{})
+	for len(s) > 0 {
+		var pair string
+		if strings.HasPrefix(s, "command=") {
+			pair, s = s, ""
+		} else if i := strings.Index(s, ","); i >= 0 {
+			pair, s = s[:i], s[i+1:]
+		} else {
+			pair, s = s, ""
+		}
+
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return probe, errors.Errorf("invalid probe field %s, it should be key=value", pair)
+		}
+		switch kv[0] {
+		case "expected_status", "tolerance":
+			n, err := strconv.Atoi(kv[1])
+			if err != nil {
+				return probe, errors.Errorf("invalid probe field %s, it should be an integer", pair)
+			}
+			fields[kv[0]] = n
+		default:
+			fields[kv[0]] = kv[1]
+		}
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return probe, errors.WithStack(err)
+	}
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&probe); err != nil {
+		return probe, errors.Annotate(err, "invalid probe")
+	}
+	return probe, nil
+}
+
+func (p Probe) String() string {
+	if len(p.Name) > 0 {
+		return p.Name
+	}
+	switch p.Type {
+	case HTTPProbe:
+		return "http " + p.URL
+	case TCPProbe:
+		return "tcp " + p.Address
+	case CommandProbe:
+		return "command " + p.Command
+	}
+	return p.Type
+}
+
+func (p Probe) Validate() error {
+	switch p.Type {
+	case HTTPProbe:
+		if len(p.URL) == 0 {
+			return errors.Errorf("url is required by http probe %s", p)
+		}
+		if len(p.MaxLatency) > 0 {
+			if _, err := time.ParseDuration(p.MaxLatency); err != nil {
+				return errors.Errorf("invalid max latency %s of probe %s", p.MaxLatency, p)
+			}
+		}
+	case TCPProbe:
+		if len(p.Address) == 0 {
+			return errors.Errorf("address is required by tcp probe %s", p)
+		}
+	case CommandProbe:
+		if len(p.Command) == 0 {
+			return errors.Errorf("command is required by command probe %s", p)
+		}
+	default:
+		return errors.Errorf("probe type %s not supported, supported value: http, tcp, command", p.Type)
+	}
+
+	if p.Tolerance < 0 {
+		return errors.Errorf("tolerance of probe %s should not be negative", p)
+	}
+	if _, err := p.GetInterval(); err != nil {
+		return err
+	}
+	_, err := p.GetTimeout()
+	return err
+}
+
+func (p Probe) GetInterval() (time.Duration, error) {
+	return parseProbeDuration(p.Interval, DefaultProbeInterval)
+}
+
+func (p Probe) GetTimeout() (time.Duration, error) {
+	return parseProbeDuration(p.Timeout, DefaultProbeTimeout)
+}
+
+func parseProbeDuration(s string, defaultValue time.Duration) (time.Duration, error) {
+	if len(s) == 0 {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, errors.Errorf("invalid duration %s of probe, it should be a positive duration", s)
+	}
+	return d, nil
+}
+
+// ProbeResult is the result of a check of the probe
+type ProbeResult struct {
+	Probe   string `json:"probe"`
+	Success bool   `json:"success"`
+	Output  string `json:"output,omitempty"`
+}
+
+func (r ProbeResult) String() string {
+	if r.Success {
+		return "probe " + r.Probe + " succeeded"
+	}
+	return "probe " + r.Probe + " failed: " + r.Output
+}
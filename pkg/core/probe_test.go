@@ -0,0 +1,62 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseProbe(t *testing.T) {
+	probe, err := ParseProbe("type=http,url=http://127.0.0.1:8080/health,max_latency=200ms,expected_status=204,tolerance=2")
+	assert.NoError(t, err)
+	assert.Equal(t, Probe{
+		Type:           HTTPProbe,
+		URL:            "http://127.0.0.1:8080/health",
+		MaxLatency:     "200ms",
+		ExpectedStatus: 204,
+		Tolerance:      2,
+	}, probe)
+	assert.NoError(t, probe.Validate())
+
+	probe, err = ParseProbe("type=command,interval=5s,command=test $(echo a,b | cut -d, -f2) = b")
+	assert.NoError(t, err)
+	assert.Equal(t, Probe{Type: CommandProbe, Interval: "5s", Command: "test $(echo a,b | cut -d, -f2) = b"}, probe)
+
+	probe, err = ParseProbe(`{"name": "mysql", "type": "tcp", "address": "127.0.0.1:3306"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, Probe{Name: "mysql", Type: TCPProbe, Address: "127.0.0.1:3306"}, probe)
+	assert.Equal(t, "mysql", probe.String())
+
+	for _, s := range []string{"type=http,url", "type=http,tolerance=two", "type=http,unknown=1", "{"} {
+		_, err = ParseProbe(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestProbe_Validate(t *testing.T) {
+	for _, probe := range []Probe{
+		{Type: "udp", Address: "127.0.0.1:53"},
+		{Type: HTTPProbe},
+		{Type: HTTPProbe, URL: "http://127.0.0.1", MaxLatency: "fast"},
+		{Type: TCPProbe},
+		{Type: CommandProbe},
+		{Type: CommandProbe, Command: "true", Tolerance: -1},
+		{Type: CommandProbe, Command: "true", Interval: "0s"},
+		{Type: CommandProbe, Command: "true", Timeout: "soon"},
+	} {
+		assert.Error(t, probe.Validate(), probe.String())
+	}
+}
@@ -15,6 +15,11 @@ package core
 
 import (
 	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/pingcap/errors"
 )
@@ -22,6 +27,23 @@ import (
 const (
 	ProcessKillAction = "kill"
 	ProcessStopAction = "stop"
+	// ProcessCrashLoopAction kills the processes every interval until the attack is recovered,
+	// it runs in chaosd server.
+	ProcessCrashLoopAction = "crash-loop"
+)
+
+// The modes choosing the processes to be signaled from the matched ones, they are the same as the modes of Chaos Mesh.
+const (
+	// AllMode chooses all the matched processes.
+	AllMode = "all"
+	// OneMode chooses one random process.
+	OneMode = "one"
+	// FixedMode chooses Value random processes.
+	FixedMode = "fixed"
+	// FixedPercentMode chooses Value percent of the processes randomly.
+	FixedPercentMode = "fixed-percent"
+	// RandomMaxPercentMode chooses a random percent, up to Value, of the processes randomly.
+	RandomMaxPercentMode = "random-max-percent"
 )
 
 var _ AttackConfig = &ProcessCommand{}
@@ -30,24 +52,92 @@ type ProcessCommand struct {
 	CommonAttackConfig
 
 	// Process defines the process name or the process ID.
-	Process    string `json:"process,omitempty"`
-	Signal     int    `json:"signal,omitempty"`
-	PIDs       []int
-	RecoverCmd string `json:"recoverCmd,omitempty"`
-	// TODO: support these feature
-	// Newest       bool
-	// Oldest       bool
-	// Exact        bool
-	// KillChildren bool
-	// User         string
+	Process string `json:"process,omitempty"`
+	// Cmdline is a regular expression matched against the full command line of the process,
+	// such as "java -jar .*order-service.jar".
+	Cmdline string `json:"cmdline,omitempty"`
+	// Exact makes Cmdline match the whole command line instead of a part of it.
+	Exact bool `json:"exact,omitempty"`
+	// ParentPID selects the children of the process.
+	ParentPID int `json:"parent_pid,omitempty"`
+	// User selects the processes whose real user is the user name or the user ID.
+	User string `json:"user,omitempty"`
+	// Newest and Oldest select only the newest or the oldest one of the matched processes.
+	Newest bool `json:"newest,omitempty"`
+	Oldest bool `json:"oldest,omitempty"`
+	// KillChildren signals the whole process tree of the selected processes.
+	KillChildren bool `json:"kill_children,omitempty"`
+	// Mode chooses the processes to be signaled from the matched ones, the default value is all.
+	// Value is the number of the processes for FixedMode, and the percent for the percent modes.
+	Mode  string `json:"mode,omitempty"`
+	Value string `json:"value,omitempty"`
+	// Interval is how often the crash-loop attack kills the processes, such as "30s".
+	Interval string `json:"interval,omitempty"`
+	// WaitRestart makes the crash-loop attack kill the processes only when they have come back up,
+	// that is a process with a new pid and the command line of a killed process.
+	WaitRestart bool `json:"wait_restart,omitempty"`
+
+	Signal int `json:"signal,omitempty"`
+	// PIDs are the processes signaled by the attack, they are recorded for auditing and recovery.
+	PIDs []int
+	// Snapshots are what the kill attack captured of the processes before killing them, they are used to
+	// restart the processes on recovery if RecoverCmd is not provided.
+	Snapshots  []ProcessSnapshot `json:"snapshots,omitempty"`
+	RecoverCmd string            `json:"recoverCmd,omitempty"`
+}
+
+// ProcessSnapshot is how a process was started, it is enough to start the process again.
+// The environment of the process is not recorded in the snapshot, since it may contain secrets and the snapshots are
+// visible to anyone who can read the experiments. It is saved in EnvFile which only root can read.
+type ProcessSnapshot struct {
+	PID        int   `json:"pid"`
+	PPID       int   `json:"ppid"`
+	CreateTime int64 `json:"create_time"`
+	// Exe is the path of the executable, Args are the command line arguments including the name of the program.
+	Exe  string   `json:"exe"`
+	Args []string `json:"args"`
+	Cwd  string   `json:"cwd"`
+	UID  uint32   `json:"uid"`
+	GID  uint32   `json:"gid"`
+	// Unit is the systemd service the process belongs to, the service is started instead of the process if it is set.
+	Unit string `json:"unit,omitempty"`
+	// EnvFile is the file of the environment of the process, the process is restarted with the minimal environment
+	// if it is empty.
+	EnvFile string `json:"env_file,omitempty"`
 }
 
 func (p *ProcessCommand) Validate() error {
 	if err := p.CommonAttackConfig.Validate(); err != nil {
 		return err
 	}
-	if len(p.Process) == 0 {
-		return errors.New("process not provided")
+	if len(p.Process) == 0 && len(p.Cmdline) == 0 && p.ParentPID == 0 && len(p.User) == 0 {
+		return errors.New("process, cmdline, parent pid or user should be provided")
+	}
+	if len(p.Cmdline) > 0 {
+		if _, err := p.CmdlineRegexp(); err != nil {
+			return errors.Annotatef(err, "invalid cmdline %s", p.Cmdline)
+		}
+	} else if p.Exact {
+		return errors.New("exact is only used with cmdline")
+	}
+	if p.ParentPID < 0 {
+		return errors.Errorf("invalid parent pid %d", p.ParentPID)
+	}
+	if p.Newest && p.Oldest {
+		return errors.New("newest and oldest can not be used together")
+	}
+	if err := p.validateMode(); err != nil {
+		return err
+	}
+	if p.Action == ProcessCrashLoopAction {
+		if _, err := p.GetInterval(); err != nil {
+			return err
+		}
+		if p.Signal == int(syscall.SIGSTOP) {
+			return errors.Errorf("signal SIGSTOP is not supported by action %s", ProcessCrashLoopAction)
+		}
+	} else if len(p.Interval) > 0 || p.WaitRestart {
+		return errors.Errorf("interval and wait restart are only used by action %s", ProcessCrashLoopAction)
 	}
 
 	// TODO: validate signal
@@ -55,12 +145,123 @@ func (p *ProcessCommand) Validate() error {
 	return nil
 }
 
+func (p *ProcessCommand) validateMode() error {
+	switch p.Mode {
+	case "", AllMode, OneMode:
+		if len(p.Value) > 0 {
+			return errors.Errorf("value is not used by mode %s", p.GetMode())
+		}
+	case FixedMode:
+		if n, err := strconv.Atoi(p.Value); err != nil || n <= 0 {
+			return errors.Errorf("invalid value %s of mode %s, it should be a positive integer", p.Value, p.Mode)
+		}
+	case FixedPercentMode, RandomMaxPercentMode:
+		if n, err := strconv.Atoi(p.Value); err != nil || n <= 0 || n > 100 {
+			return errors.Errorf("invalid value %s of mode %s, it should be an integer in (0, 100]", p.Value, p.Mode)
+		}
+	default:
+		return errors.Errorf("invalid mode %s, it should be one of %s", p.Mode,
+			strings.Join([]string{AllMode, OneMode, FixedMode, FixedPercentMode, RandomMaxPercentMode}, ", "))
+	}
+	if p.GetMode() != AllMode && (p.Newest || p.Oldest) {
+		return errors.Errorf("newest and oldest can not be used with mode %s", p.Mode)
+	}
+	return nil
+}
+
+// GetInterval returns the interval of the crash-loop attack.
+func (p *ProcessCommand) GetInterval() (time.Duration, error) {
+	interval, err := time.ParseDuration(p.Interval)
+	if err != nil || interval <= 0 {
+		return 0, errors.Errorf("invalid interval %s, it should be a positive duration", p.Interval)
+	}
+	return interval, nil
+}
+
+// GetMode returns the mode of the attack, the default value is all.
+func (p *ProcessCommand) GetMode() string {
+	if len(p.Mode) == 0 {
+		return AllMode
+	}
+	return p.Mode
+}
+
+// ChooseCount returns the number of the processes to be chosen from the matched ones. The percent modes choose
+// at least one process if there are any, so that the attack never succeeds without signaling anything.
+// intn returns a random number in [0, n), it is used by RandomMaxPercentMode.
+func (p *ProcessCommand) ChooseCount(matched int, intn func(n int) int) int {
+	if matched == 0 {
+		return 0
+	}
+
+	value, _ := strconv.Atoi(p.Value)
+	count := matched
+	switch p.GetMode() {
+	case OneMode:
+		count = 1
+	case FixedMode:
+		count = value
+	case FixedPercentMode:
+		count = matched * value / 100
+	case RandomMaxPercentMode:
+		count = matched * (intn(value) + 1) / 100
+	}
+
+	if count < 1 {
+		count = 1
+	}
+	if count > matched {
+		count = matched
+	}
+	return count
+}
+
+// CmdlineRegexp returns the regular expression of Cmdline, it is anchored if Exact is set.
+func (p *ProcessCommand) CmdlineRegexp() (*regexp.Regexp, error) {
+	if p.Exact {
+		return regexp.Compile("^(?:" + p.Cmdline + ")$")
+	}
+	return regexp.Compile(p.Cmdline)
+}
+
+// Selector returns the description of the selectors of the processes, such as "process java, user app".
+func (p *ProcessCommand) Selector() string {
+	var selectors []string
+	if len(p.Process) > 0 {
+		selectors = append(selectors, "process "+p.Process)
+	}
+	if len(p.Cmdline) > 0 {
+		selectors = append(selectors, "cmdline "+p.Cmdline)
+	}
+	if p.ParentPID > 0 {
+		selectors = append(selectors, "parent pid "+strconv.Itoa(p.ParentPID))
+	}
+	if len(p.User) > 0 {
+		selectors = append(selectors, "user "+p.User)
+	}
+	if p.GetMode() != AllMode {
+		mode := "mode " + p.Mode
+		if len(p.Value) > 0 {
+			mode += " " + p.Value
+		}
+		selectors = append(selectors, mode)
+	}
+	return strings.Join(selectors, ", ")
+}
+
 func (p ProcessCommand) RecoverData() string {
 	data, _ := json.Marshal(p)
 
 	return string(data)
 }
 
+func (p *ProcessCommand) CompleteDefaults() {
+	p.CommonAttackConfig.CompleteDefaults()
+	if p.Action == ProcessCrashLoopAction && p.Signal == 0 {
+		p.Signal = int(syscall.SIGKILL)
+	}
+}
+
 func NewProcessCommand() *ProcessCommand {
 	return &ProcessCommand{
 		CommonAttackConfig: CommonAttackConfig{
@@ -68,3 +269,11 @@ func NewProcessCommand() *ProcessCommand {
 		},
 	}
 }
+
+// ProcessKillEvent records a kill of the crash-loop attack, it is the After of the history of the experiment.
+type ProcessKillEvent struct {
+	PID     int    `json:"pid"`
+	Name    string `json:"name"`
+	Cmdline string `json:"cmdline"`
+	Signal  int    `json:"signal"`
+}
@@ -0,0 +1,82 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessCommand_Validate(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		command ProcessCommand
+		valid   bool
+	}{
+		{"process", ProcessCommand{Process: "java"}, true},
+		{"cmdline", ProcessCommand{Cmdline: "java -jar .*order", Exact: true}, true},
+		{"parent pid", ProcessCommand{ParentPID: 1}, true},
+		{"user", ProcessCommand{User: "app", Newest: true}, true},
+		{"no selector", ProcessCommand{Newest: true}, false},
+		{"invalid cmdline", ProcessCommand{Cmdline: "java ("}, false},
+		{"exact without cmdline", ProcessCommand{Process: "java", Exact: true}, false},
+		{"negative parent pid", ProcessCommand{ParentPID: -1}, false},
+		{"newest and oldest", ProcessCommand{Process: "java", Newest: true, Oldest: true}, false},
+		{"one", ProcessCommand{Process: "nginx", Mode: OneMode}, true},
+		{"fixed", ProcessCommand{Process: "nginx", Mode: FixedMode, Value: "2"}, true},
+		{"fixed percent", ProcessCommand{Process: "nginx", Mode: FixedPercentMode, Value: "30"}, true},
+		{"invalid mode", ProcessCommand{Process: "nginx", Mode: "some"}, false},
+		{"value without mode", ProcessCommand{Process: "nginx", Value: "2"}, false},
+		{"invalid fixed", ProcessCommand{Process: "nginx", Mode: FixedMode, Value: "0"}, false},
+		{"invalid percent", ProcessCommand{Process: "nginx", Mode: RandomMaxPercentMode, Value: "120"}, false},
+		{"mode and newest", ProcessCommand{Process: "nginx", Mode: OneMode, Newest: true}, false},
+		{"crash loop", ProcessCommand{CommonAttackConfig: CommonAttackConfig{Action: ProcessCrashLoopAction},
+			Process: "nginx", Interval: "30s", WaitRestart: true}, true},
+		{"crash loop without interval", ProcessCommand{CommonAttackConfig: CommonAttackConfig{Action: ProcessCrashLoopAction},
+			Process: "nginx"}, false},
+		{"interval without crash loop", ProcessCommand{Process: "nginx", Interval: "30s"}, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			test.command.Kind = ProcessAttack
+			err := test.command.Validate()
+			if test.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestProcessCommand_ChooseCount(t *testing.T) {
+	max := func(n int) int { return n - 1 }
+	for _, test := range []struct {
+		mode, value string
+		matched     int
+		count       int
+	}{
+		{"", "", 4, 4},
+		{OneMode, "", 4, 1},
+		{FixedMode, "2", 4, 2},
+		{FixedMode, "5", 4, 4},
+		{FixedPercentMode, "30", 10, 3},
+		{FixedPercentMode, "30", 2, 1},
+		{RandomMaxPercentMode, "50", 10, 5},
+		{OneMode, "", 0, 0},
+	} {
+		p := ProcessCommand{Mode: test.mode, Value: test.value}
+		assert.Equal(t, test.count, p.ChooseCount(test.matched, max), "%s %s of %d", test.mode, test.value, test.matched)
+	}
+}
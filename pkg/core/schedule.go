@@ -0,0 +1,309 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+)
+
+const clockLayout = "15:04"
+
+const (
+	// ForbidConcurrent skips the run if the previous run is not recovered.
+	ForbidConcurrent = "Forbid"
+	// ReplaceConcurrent recovers the previous run before starting the new one.
+	ReplaceConcurrent = "Replace"
+	// AllowConcurrent starts the run even if the previous run is not recovered,
+	// it is only supported by the attacks whose runs don't affect each other, see ConcurrentRunsAttack.
+	AllowConcurrent = "Allow"
+)
+
+// ConcurrentRunsAttack is implemented by the attacks whose runs can overlap, such as sending HTTP requests.
+type ConcurrentRunsAttack interface {
+	AllowConcurrentRuns() bool
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// BlackoutWindow is a window in which no run of a schedule may start. It is either a fixed window
+// with Start and End in RFC3339, such as a release freeze, or a daily window with From and To in
+// the time zone of the schedule, such as business hours {"from": "09:00", "to": "18:00", "weekdays": ["Mon", "Fri"]}.
+// A daily window crosses midnight if To is earlier than From, and its weekdays are the days it starts.
+type BlackoutWindow struct {
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+	// Weekdays are the days of the daily window, such as "Mon" or "Monday", the default value is every day.
+	Weekdays []string `json:"weekdays,omitempty"`
+}
+
+func (w BlackoutWindow) String() string {
+	if len(w.Start) > 0 {
+		return w.Start + "~" + w.End
+	}
+	s := w.From + "-" + w.To
+	if len(w.Weekdays) > 0 {
+		s += " " + strings.Join(w.Weekdays, ",")
+	}
+	return s
+}
+
+func (w BlackoutWindow) Validate() error {
+	fixed := len(w.Start) > 0 || len(w.End) > 0
+	daily := len(w.From) > 0 || len(w.To) > 0
+	if fixed == daily {
+		return errors.Errorf("blackout window %s should have either start and end, or from and to", w)
+	}
+
+	if fixed {
+		start, err := time.Parse(time.RFC3339, w.Start)
+		if err != nil {
+			return errors.Errorf("invalid start %s of blackout window, it should be in RFC3339", w.Start)
+		}
+		end, err := time.Parse(time.RFC3339, w.End)
+		if err != nil {
+			return errors.Errorf("invalid end %s of blackout window, it should be in RFC3339", w.End)
+		}
+		if !end.After(start) {
+			return errors.Errorf("end of blackout window %s should be after its start", w)
+		}
+		return nil
+	}
+
+	for _, clock := range []string{w.From, w.To} {
+		if _, err := time.Parse(clockLayout, clock); err != nil {
+			return errors.Errorf("invalid time %s of blackout window, it should be like 09:00", clock)
+		}
+	}
+	if w.From == w.To {
+		return errors.Errorf("from and to of blackout window %s should not be the same", w)
+	}
+	for _, day := range w.Weekdays {
+		if _, ok := parseWeekday(day); !ok {
+			return errors.Errorf("invalid weekday %s of blackout window", day)
+		}
+	}
+	return nil
+}
+
+// Contains returns true if the time is in the window, loc is the time zone of the daily window.
+func (w BlackoutWindow) Contains(t time.Time, loc *time.Location) bool {
+	if len(w.Start) > 0 {
+		start, err := time.Parse(time.RFC3339, w.Start)
+		if err != nil {
+			return false
+		}
+		end, err := time.Parse(time.RFC3339, w.End)
+		if err != nil {
+			return false
+		}
+		return !t.Before(start) && t.Before(end)
+	}
+
+	from, err := time.Parse(clockLayout, w.From)
+	if err != nil {
+		return false
+	}
+	to, err := time.Parse(clockLayout, w.To)
+	if err != nil {
+		return false
+	}
+
+	t = t.In(loc)
+	minute := t.Hour()*60 + t.Minute()
+	fromMinute, toMinute := from.Hour()*60+from.Minute(), to.Hour()*60+to.Minute()
+	day := t.Weekday()
+	switch {
+	case fromMinute < toMinute:
+		if minute < fromMinute || minute >= toMinute {
+			return false
+		}
+	case minute >= fromMinute:
+	case minute < toMinute:
+		// the window started on the day before
+		day = t.AddDate(0, 0, -1).Weekday()
+	default:
+		return false
+	}
+
+	if len(w.Weekdays) == 0 {
+		return true
+	}
+	for _, s := range w.Weekdays {
+		if weekday, ok := parseWeekday(s); ok && weekday == day {
+			return true
+		}
+	}
+	return false
+}
+
+func parseWeekday(s string) (time.Weekday, bool) {
+	s = strings.ToLower(s)
+	if len(s) < 3 {
+		return 0, false
+	}
+	weekday, ok := weekdays[s[:3]]
+	if !ok || !strings.HasPrefix(strings.ToLower(weekday.String()), s) {
+		return 0, false
+	}
+	return weekday, true
+}
+
+// Validate validates the options of the schedule, they are only allowed for the scheduled attacks.
+func (config SchedulerConfig) Validate() error {
+	if len(config.Schedule) == 0 {
+		if len(config.TimeZone) > 0 || len(config.StartAt) > 0 || len(config.EndAt) > 0 ||
+			config.MaxRuns != 0 || len(config.Jitter) > 0 || len(config.Blackouts) > 0 || len(config.ConcurrencyPolicy) > 0 ||
+			config.Suspend {
+			return errors.New("time zone, start at, end at, max runs, jitter, blackouts, concurrency policy and suspend " +
+				"are only used by the scheduled attacks")
+		}
+		return nil
+	}
+
+	if _, err := config.Location(); err != nil {
+		return err
+	}
+	startAt, err := parseOptionalTime(config.StartAt)
+	if err != nil {
+		return errors.Errorf("invalid start at %s, it should be in RFC3339", config.StartAt)
+	}
+	endAt, err := parseOptionalTime(config.EndAt)
+	if err != nil {
+		return errors.Errorf("invalid end at %s, it should be in RFC3339", config.EndAt)
+	}
+	if startAt != nil && endAt != nil && !endAt.After(*startAt) {
+		return errors.Errorf("end at %s should be after start at %s", config.EndAt, config.StartAt)
+	}
+	if config.MaxRuns < 0 {
+		return errors.Errorf("max runs %d should not be negative", config.MaxRuns)
+	}
+	if _, err := config.GetJitter(); err != nil {
+		return err
+	}
+	for _, window := range config.Blackouts {
+		if err := window.Validate(); err != nil {
+			return err
+		}
+	}
+	switch config.ConcurrencyPolicy {
+	case "", ForbidConcurrent, ReplaceConcurrent, AllowConcurrent:
+	default:
+		return errors.Errorf("invalid concurrency policy %s, it should be one of %s, %s and %s",
+			config.ConcurrencyPolicy, ForbidConcurrent, ReplaceConcurrent, AllowConcurrent)
+	}
+	return nil
+}
+
+// GetConcurrencyPolicy returns the concurrency policy of the schedule, the default value is Forbid.
+func (config SchedulerConfig) GetConcurrencyPolicy() string {
+	if len(config.ConcurrencyPolicy) == 0 {
+		return ForbidConcurrent
+	}
+	return config.ConcurrencyPolicy
+}
+
+// ValidateConcurrencyPolicy checks that the runs of the attack can overlap if its concurrency policy is Allow,
+// the runs of the other attacks would break the recovery of each other.
+func ValidateConcurrencyPolicy(options AttackConfig) error {
+	if options.GetSchedulerConfig().ConcurrencyPolicy != AllowConcurrent {
+		return nil
+	}
+	if attack, ok := options.(ConcurrentRunsAttack); ok && attack.AllowConcurrentRuns() {
+		return nil
+	}
+	return errors.Errorf("concurrency policy %s is not supported by attack %s", AllowConcurrent, options.String())
+}
+
+// Location returns the time zone of the schedule.
+func (config SchedulerConfig) Location() (*time.Location, error) {
+	if len(config.TimeZone) == 0 {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(config.TimeZone)
+	if err != nil {
+		return nil, errors.Errorf("invalid time zone %s", config.TimeZone)
+	}
+	return loc, nil
+}
+
+// CronSpec returns the spec of the schedule in its time zone.
+func (config SchedulerConfig) CronSpec() string {
+	if len(config.TimeZone) == 0 {
+		return config.Schedule
+	}
+	return fmt.Sprintf("CRON_TZ=%s %s", config.TimeZone, config.Schedule)
+}
+
+func (config SchedulerConfig) GetJitter() (time.Duration, error) {
+	if len(config.Jitter) == 0 {
+		return 0, nil
+	}
+	jitter, err := time.ParseDuration(config.Jitter)
+	if err != nil || jitter < 0 {
+		return 0, errors.Errorf("invalid jitter %s, it should be a non-negative duration", config.Jitter)
+	}
+	return jitter, nil
+}
+
+// SkipReason returns the reason why the run of the schedule starting at now should be skipped,
+// runs is the number of the runs executed. finished is true if no run will be executed anymore.
+// It returns an empty reason if the run should be executed.
+func (config SchedulerConfig) SkipReason(now time.Time, runs int) (reason string, finished bool) {
+	if config.MaxRuns > 0 && runs >= config.MaxRuns {
+		return fmt.Sprintf("max runs %d is reached", config.MaxRuns), true
+	}
+	if endAt, err := parseOptionalTime(config.EndAt); err == nil && endAt != nil && !now.Before(*endAt) {
+		return fmt.Sprintf("the schedule ended at %s", config.EndAt), true
+	}
+	if startAt, err := parseOptionalTime(config.StartAt); err == nil && startAt != nil && now.Before(*startAt) {
+		return fmt.Sprintf("the schedule starts at %s", config.StartAt), false
+	}
+
+	loc, err := config.Location()
+	if err != nil {
+		loc = time.UTC
+	}
+	for _, window := range config.Blackouts {
+		if window.Contains(now, loc) {
+			return fmt.Sprintf("in blackout window %s", window), false
+		}
+	}
+	return "", false
+}
+
+func parseOptionalTime(s string) (*time.Time, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedulerConfig_Validate(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		config SchedulerConfig
+		valid  bool
+	}{
+		{"no schedule", SchedulerConfig{}, true},
+		{"options without schedule", SchedulerConfig{MaxRuns: 1}, false},
+		{"valid options", SchedulerConfig{
+			Schedule:  "@every 1h",
+			TimeZone:  "Asia/Shanghai",
+			StartAt:   "2023-06-01T09:00:00+08:00",
+			EndAt:     "2023-07-01T09:00:00+08:00",
+			MaxRuns:   3,
+			Jitter:    "30s",
+			Blackouts: []BlackoutWindow{{From: "09:00", To: "18:00", Weekdays: []string{"Mon", "friday"}}},
+		}, true},
+		{"invalid time zone", SchedulerConfig{Schedule: "@every 1h", TimeZone: "Mars/Olympus"}, false},
+		{"invalid start at", SchedulerConfig{Schedule: "@every 1h", StartAt: "2023-06-01"}, false},
+		{"end before start", SchedulerConfig{Schedule: "@every 1h",
+			StartAt: "2023-07-01T09:00:00Z", EndAt: "2023-06-01T09:00:00Z"}, false},
+		{"negative max runs", SchedulerConfig{Schedule: "@every 1h", MaxRuns: -1}, false},
+		{"invalid jitter", SchedulerConfig{Schedule: "@every 1h", Jitter: "-1s"}, false},
+		{"mixed blackout", SchedulerConfig{Schedule: "@every 1h",
+			Blackouts: []BlackoutWindow{{Start: "2023-06-01T09:00:00Z", From: "09:00"}}}, false},
+		{"invalid clock", SchedulerConfig{Schedule: "@every 1h", Blackouts: []BlackoutWindow{{From: "9am", To: "18:00"}}}, false},
+		{"invalid weekday", SchedulerConfig{Schedule: "@every 1h",
+			Blackouts: []BlackoutWindow{{From: "09:00", To: "18:00", Weekdays: []string{"Mo"}}}}, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.config.Validate()
+			if test.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestSchedulerConfig_SkipReason(t *testing.T) {
+	config := SchedulerConfig{
+		Schedule: "@every 1h",
+		TimeZone: "Asia/Shanghai",
+		StartAt:  "2023-06-01T00:00:00+08:00",
+		EndAt:    "2023-07-01T00:00:00+08:00",
+		MaxRuns:  10,
+		Blackouts: []BlackoutWindow{
+			// business hours
+			{From: "09:00", To: "18:00", Weekdays: []string{"Mon", "Tue", "Wed", "Thu", "Fri"}},
+			// nightly backup crossing midnight on Saturday
+			{From: "23:00", To: "02:00", Weekdays: []string{"Sat"}},
+			// release freeze
+			{Start: "2023-06-20T00:00:00+08:00", End: "2023-06-21T00:00:00+08:00"},
+		},
+	}
+	loc, err := config.Location()
+	assert.NoError(t, err)
+	at := func(s string) time.Time {
+		tm, err := time.ParseInLocation("2006-01-02 15:04", s, loc)
+		assert.NoError(t, err)
+		return tm
+	}
+
+	for _, test := range []struct {
+		name     string
+		now      time.Time
+		runs     int
+		skipped  bool
+		finished bool
+	}{
+		{"before start", at("2023-05-31 20:00"), 0, true, false},
+		// 2023-06-05 is Monday
+		{"business hours", at("2023-06-05 10:00"), 0, true, false},
+		{"business hours in utc", at("2023-06-05 10:00").UTC(), 0, true, false},
+		{"after business hours", at("2023-06-05 19:00"), 0, false, false},
+		{"weekend", at("2023-06-04 10:00"), 0, false, false},
+		{"saturday night", at("2023-06-03 23:30"), 0, true, false},
+		{"after midnight of saturday", at("2023-06-04 01:00"), 0, true, false},
+		{"after midnight of sunday", at("2023-06-05 01:00"), 0, false, false},
+		{"release freeze", at("2023-06-20 20:00"), 0, true, false},
+		{"max runs", at("2023-06-05 19:00"), 10, true, true},
+		{"after end", at("2023-07-01 00:00"), 0, true, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			reason, finished := config.SkipReason(test.now, test.runs)
+			assert.Equal(t, test.skipped, len(reason) > 0, reason)
+			assert.Equal(t, test.finished, finished)
+		})
+	}
+}
+
+func TestSchedulerConfig_CronSpec(t *testing.T) {
+	assert.Equal(t, "0 9 * * *", SchedulerConfig{Schedule: "0 9 * * *"}.CronSpec())
+	assert.Equal(t, "CRON_TZ=Asia/Shanghai 0 9 * * *", SchedulerConfig{Schedule: "0 9 * * *", TimeZone: "Asia/Shanghai"}.CronSpec())
+}
+
+func TestValidateConcurrencyPolicy(t *testing.T) {
+	schedule := SchedulerConfig{Schedule: "@every 1h", ConcurrencyPolicy: AllowConcurrent}
+
+	request := &HTTPAttackConfig{CommonAttackConfig: CommonAttackConfig{SchedulerConfig: schedule, Action: HTTPRequestAction}}
+	assert.NoError(t, ValidateConcurrencyPolicy(request))
+
+	stress := &StressCommand{CommonAttackConfig: CommonAttackConfig{SchedulerConfig: schedule}}
+	assert.Error(t, ValidateConcurrencyPolicy(stress))
+
+	stress.ConcurrencyPolicy = ReplaceConcurrent
+	assert.NoError(t, ValidateConcurrencyPolicy(stress))
+
+	assert.Error(t, SchedulerConfig{Schedule: "@every 1h", ConcurrencyPolicy: "Queue"}.Validate())
+}
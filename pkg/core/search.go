@@ -25,6 +25,8 @@ type SearchCommand struct {
 	Limit  uint32
 	Offset uint32
 	UID    string
+	// Selector is a label selector, such as "team=payments", it works together with the other conditions.
+	Selector string
 }
 
 func (s SearchCommand) Validate() error {
@@ -41,14 +43,20 @@ func (s SearchCommand) Validate() error {
 
 	if len(s.Status) > 0 {
 		switch s.Status {
-		case Created, Success, Error, Destroyed, Revoked:
+		case Created, Success, Error, Scheduled, Paused, Destroyed, Revoked, Aborted:
 			break
 		default:
 			return errors.Errorf("status %s not supported", s.Status)
 		}
 	}
 
-	if len(s.Status) == 0 && len(s.Kind) == 0 && !s.All {
+	if len(s.Selector) > 0 {
+		if _, err := ParseSelector(s.Selector); err != nil {
+			return err
+		}
+	}
+
+	if len(s.Status) == 0 && len(s.Kind) == 0 && len(s.Selector) == 0 && !s.All {
 		return errors.New("UID is required")
 	}
 
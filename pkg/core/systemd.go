@@ -0,0 +1,107 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"encoding/json"
+	"strings"
+	"syscall"
+
+	"github.com/pingcap/errors"
+)
+
+const (
+	SystemdStopAction    = "stop"
+	SystemdRestartAction = "restart"
+	SystemdKillAction    = "kill"
+	// SystemdFreezeAction freezes the processes of the unit with the cgroup freezer, it requires systemd 246 or later.
+	SystemdFreezeAction = "freeze"
+	// SystemdMaskAction masks the unit and stops it, so that it can not be started until it is recovered.
+	SystemdMaskAction = "mask"
+)
+
+var _ AttackConfig = &SystemdCommand{}
+
+type SystemdCommand struct {
+	CommonAttackConfig
+
+	// Unit is the name of the systemd unit, ".service" is appended to it if it has no suffix.
+	Unit string `json:"unit,omitempty"`
+	// Signal is sent to all the processes of the unit by the kill action.
+	Signal int `json:"signal,omitempty"`
+	// Runtime masks the unit until the next reboot only.
+	Runtime bool `json:"runtime,omitempty"`
+
+	// ActiveState and UnitFileState are the states of the unit before the attack, such as "active" and "enabled",
+	// the unit is restored to them on recovery.
+	ActiveState   string `json:"active_state,omitempty"`
+	UnitFileState string `json:"unit_file_state,omitempty"`
+}
+
+func (s *SystemdCommand) Validate() error {
+	if err := s.CommonAttackConfig.Validate(); err != nil {
+		return err
+	}
+	if len(s.Unit) == 0 {
+		return errors.New("unit is required")
+	}
+	switch s.Action {
+	case SystemdStopAction, SystemdRestartAction, SystemdFreezeAction, SystemdMaskAction:
+		if s.Signal != 0 {
+			return errors.Errorf("signal is only used by action %s", SystemdKillAction)
+		}
+	case SystemdKillAction:
+		if s.Signal <= 0 {
+			return errors.Errorf("invalid signal %d", s.Signal)
+		}
+	default:
+		return errors.Errorf("invalid action %s of systemd attack", s.Action)
+	}
+	if s.Runtime && s.Action != SystemdMaskAction {
+		return errors.Errorf("runtime is only used by action %s", SystemdMaskAction)
+	}
+	return nil
+}
+
+func (s *SystemdCommand) CompleteDefaults() {
+	if len(s.Unit) > 0 && !strings.Contains(s.Unit, ".") {
+		s.Unit += ".service"
+	}
+	if s.Action == SystemdKillAction && s.Signal == 0 {
+		s.Signal = int(syscall.SIGKILL)
+	}
+}
+
+// WasActive returns true if the unit was active or being activated before the attack.
+func (s *SystemdCommand) WasActive() bool {
+	switch s.ActiveState {
+	case "active", "activating", "reloading":
+		return true
+	}
+	return false
+}
+
+func (s SystemdCommand) RecoverData() string {
+	data, _ := json.Marshal(s)
+
+	return string(data)
+}
+
+func NewSystemdCommand() *SystemdCommand {
+	return &SystemdCommand{
+		CommonAttackConfig: CommonAttackConfig{
+			Kind: SystemdAttack,
+		},
+	}
+}
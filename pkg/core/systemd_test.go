@@ -0,0 +1,47 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSystemdCommand_Validate(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		command SystemdCommand
+		valid   bool
+	}{
+		{"stop", SystemdCommand{Unit: "nginx"}, true},
+		{"kill", SystemdCommand{Unit: "nginx.service", Signal: 15}, true},
+		{"mask", SystemdCommand{Unit: "nginx", Runtime: true}, true},
+		{"freeze", SystemdCommand{}, false},
+		{"stop", SystemdCommand{Unit: "nginx", Signal: 15}, false},
+		{"restart", SystemdCommand{Unit: "nginx", Runtime: true}, false},
+		{"reload", SystemdCommand{Unit: "nginx"}, false},
+	} {
+		test.command.Kind = SystemdAttack
+		test.command.Action = test.name
+		test.command.CompleteDefaults()
+		err := test.command.Validate()
+		if test.valid {
+			assert.NoError(t, err, test.name)
+			assert.Equal(t, "nginx.service", test.command.Unit)
+		} else {
+			assert.Error(t, err, test.name)
+		}
+	}
+}
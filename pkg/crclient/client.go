@@ -15,10 +15,34 @@ package crclient
 
 import (
 	"context"
+	"strconv"
+	"strings"
 
 	"github.com/chaos-mesh/chaos-mesh/pkg/chaosdaemon/crclients"
+	"github.com/containerd/containerd/errdefs"
+	dockerclient "github.com/docker/docker/client"
+	"github.com/pingcap/errors"
+
+	"github.com/chaos-mesh/chaosd/pkg/config"
+	"github.com/chaos-mesh/chaosd/pkg/container"
 )
 
+// PidProtocolPrefix is the prefix of the container ID standing for a process, such as "pid://1234",
+// the chaosdaemon requests with it enter the namespaces of the process.
+const PidProtocolPrefix = "pid://"
+
+// ErrTargetGone means the container or the process of the target has been removed or has exited,
+// so its namespaces are gone.
+var ErrTargetGone = errors.New("target is gone")
+
+// IsTargetGone returns true if the namespaces of the target are gone.
+func IsTargetGone(err error) bool {
+	return errors.Cause(err) == ErrTargetGone
+}
+
+// newCRIClient creates the client of the container runtime, it is replaced in the tests.
+var newCRIClient = container.NewCRIClient
+
 func NewNodeCRClient(pid int) crclients.ContainerRuntimeInfoClient {
 	return &NodeCRClient{
 		Pid: uint32(pid),
@@ -37,8 +61,40 @@ func (n *NodeCRClient) GetLabelsFromContainerID(_ context.Context, _ string) (ma
 	return nil, nil
 }
 
-func (n *NodeCRClient) GetPidFromContainerID(_ context.Context, _ string) (uint32, error) {
-	return n.Pid, nil
+// GetPidFromContainerID returns the pid whose namespaces are entered by the chaosdaemon requests with the container ID.
+// It is the pid of chaosd for the empty container ID, the pid of "pid://<pid>", or the pid of the container
+// "docker://<id>" or "containerd://<id>" got from its runtime.
+func (n *NodeCRClient) GetPidFromContainerID(ctx context.Context, containerID string) (uint32, error) {
+	if len(containerID) == 0 {
+		return n.Pid, nil
+	}
+	if strings.HasPrefix(containerID, PidProtocolPrefix) {
+		pid, err := strconv.ParseUint(strings.TrimPrefix(containerID, PidProtocolPrefix), 10, 32)
+		if err != nil || pid == 0 {
+			return 0, errors.Errorf("invalid pid of %s", containerID)
+		}
+		return uint32(pid), nil
+	}
+
+	runtime := container.RuntimeOf(containerID)
+	if len(runtime) == 0 {
+		return 0, errors.Errorf("container id %s has no protocol prefix of the runtime", containerID)
+	}
+	client, err := newCRIClient(&config.Config{Runtime: runtime})
+	if err != nil {
+		return 0, err
+	}
+	pid, err := client.GetPidFromContainerID(ctx, containerID)
+	if dockerclient.IsErrNotFound(err) || errdefs.IsNotFound(errors.Cause(err)) {
+		return 0, errors.Annotatef(ErrTargetGone, "container %s is not found, %v", containerID, err)
+	}
+	if err != nil {
+		return 0, errors.Annotatef(err, "get pid of container %s", containerID)
+	}
+	if pid == 0 {
+		return 0, errors.Annotatef(ErrTargetGone, "container %s is not running", containerID)
+	}
+	return pid, nil
 }
 
 func (n *NodeCRClient) ContainerKillByContainerID(_ context.Context, _ string) error {
@@ -0,0 +1,74 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crclient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chaos-mesh/chaosd/pkg/config"
+	"github.com/chaos-mesh/chaosd/pkg/container"
+)
+
+// fakeCRIClient returns the pids of the running containers.
+type fakeCRIClient struct {
+	container.CRIClient
+	pids map[string]uint32
+}
+
+func (c fakeCRIClient) GetPidFromContainerID(_ context.Context, containerID string) (uint32, error) {
+	if pid, ok := c.pids[containerID]; ok {
+		return pid, nil
+	}
+	return 0, errors.Errorf("container %s not found", containerID)
+}
+
+func TestNodeCRClient_GetPidFromContainerID(t *testing.T) {
+	var runtimes []string
+	newClient := newCRIClient
+	newCRIClient = func(conf *config.Config) (container.CRIClient, error) {
+		runtimes = append(runtimes, conf.Runtime)
+		return fakeCRIClient{pids: map[string]uint32{"docker://web": 42, "containerd://db": 43}}, nil
+	}
+	defer func() { newCRIClient = newClient }()
+
+	client := NewNodeCRClient(1)
+	ctx := context.Background()
+	for containerID, pid := range map[string]uint32{
+		"":                1,
+		"pid://1234":      1234,
+		"docker://web":    42,
+		"containerd://db": 43,
+	} {
+		got, err := client.GetPidFromContainerID(ctx, containerID)
+		assert.NoError(t, err, containerID)
+		assert.Equal(t, pid, got, containerID)
+	}
+	assert.ElementsMatch(t, []string{"docker", "containerd"}, runtimes)
+
+	for _, containerID := range []string{"pid://", "pid://web", "docker://cache", "web"} {
+		_, err := client.GetPidFromContainerID(ctx, containerID)
+		assert.Error(t, err, containerID)
+		assert.False(t, IsTargetGone(err), containerID)
+	}
+
+	newCRIClient = func(conf *config.Config) (container.CRIClient, error) {
+		return fakeCRIClient{pids: map[string]uint32{"docker://exited": 0}}, nil
+	}
+	_, err := client.GetPidFromContainerID(ctx, "docker://exited")
+	assert.True(t, IsTargetGone(err))
+}
@@ -15,6 +15,8 @@ package scheduler
 
 import (
 	"context"
+	"math/rand"
+	"sort"
 	"sync"
 	"time"
 
@@ -33,56 +35,482 @@ type Scheduler struct {
 	expRunStore core.ExperimentRunStore
 	cronStore   CronStore
 }
+
+// AttackFunc executes the run runUid of a scheduled attack, it returns the data to recover the run,
+// such as the pid of the process started by the run.
+type AttackFunc func(runUid string) (recoverData string, err error)
+
+// RecoverFunc recovers a run of a scheduled attack with the data returned by its AttackFunc.
+type RecoverFunc func(recoverData string) error
+
 type CronJob struct {
 	// immutable fields
 	scheduler   *Scheduler
 	experiment  *core.Experiment
-	attackFunc  func() error
-	recoverFunc func() error
+	attackFunc  AttackFunc
+	recoverFunc RecoverFunc
 
 	// mutable fields protected by sync.Locker
-	waitForRecovery bool
-	lock            sync.Mutex
+	paused bool
+	// suspended is loaded from the config of the experiment, a suspended job starts no new run on its schedule
+	suspended bool
+	// pendingRuns are the runs waiting for recovery or being recovered, indexed by the uid of the run
+	pendingRuns map[string]*pendingRun
+	// running is true while the job is run by its schedule, the ticks coming meanwhile are skipped
+	running bool
+	lock    sync.Mutex
+
+	// runLock serializes the scheduled runs and the triggered runs
+	runLock sync.Mutex
+
+	// removed is closed when the job is removed from the scheduler, to stop waiting for the jitter
+	removed    chan struct{}
+	removeOnce sync.Once
+}
+
+// pendingRun is a run waiting for recovery, its timer recovers it at the end of its duration,
+// and it can be recovered earlier when the job is paused or the run is replaced.
+type pendingRun struct {
+	run   *core.ExperimentRun
+	timer *time.Timer
+	// deadline is when the timer recovers the run, it is zero if the run has no timer
+	deadline time.Time
+	// recover is bound to the runtime state of this run
+	recover    func() error
+	recovering bool
 }
 
 func hasCronDurationExceeded(startedAt time.Time, duration time.Duration) bool {
 	return time.Until(startedAt.Add(duration)).Milliseconds() < 0
 }
 
+// recoverFuncOf returns the func to recover the run with its own recover data.
+func (cj *CronJob) recoverFuncOf(expRun *core.ExperimentRun) func() error {
+	recoverData := expRun.RecoverCommand
+	return func() error {
+		return cj.recoverFunc(recoverData)
+	}
+}
+
+// RecoverRun recovers the run at once and updates it in the DB.
 func (cj *CronJob) RecoverRun(expRun *core.ExperimentRun) {
-	defer cj.setWaitForRecovery(false)
-	log.Info("recovering attack on exp run", zap.String("expRunUID", expRun.UID))
-	if err := cj.recoverFunc(); err != nil {
-		log.Warn("recovery failed", zap.Error(err))
-	} else {
-		if err := cj.scheduler.expRunStore.Update(context.Background(), expRun.UID, core.RunRecovered, ""); err != nil {
+	cj.addPendingRun(expRun, nil)
+	_ = cj.recoverRun(expRun.UID, true)
+}
+
+// recoverRun recovers the pending run, it does nothing if the run is recovered or being recovered.
+// The run is marked as failed if it fails to recover and markFailed is true,
+// otherwise it is kept waiting for recovery in the DB.
+func (cj *CronJob) recoverRun(runUid string, markFailed bool) error {
+	p := cj.takePendingRun(runUid)
+	if p == nil {
+		return nil
+	}
+	defer cj.removePendingRun(runUid)
+
+	log.Info("recovering attack on exp run", zap.String("expRunUID", runUid))
+	if err := p.recover(); err != nil {
+		log.Warn("recovery failed", zap.String("expRunUID", runUid), zap.Error(err))
+		if !markFailed {
+			return perr.WithMessagef(err, "failed to recover exp run %s", runUid)
+		}
+		if err := cj.scheduler.expRunStore.Update(context.Background(), runUid, core.RunFailed, err.Error()); err != nil {
 			log.Error("failed to update in DB", zap.Error(err))
 		}
+		return perr.WithMessagef(err, "failed to recover exp run %s", runUid)
+	}
+	return perr.WithStack(cj.scheduler.expRunStore.Update(context.Background(), runUid, core.RunRecovered, ""))
+}
+
+// abortRun recovers the pending run before its end, such as when a probe of the experiment fails, and marks it
+// as aborted with the reason. The run is marked as failed if it fails to recover, the other runs are not affected.
+func (cj *CronJob) abortRun(runUid string, reason string) error {
+	p := cj.takePendingRun(runUid)
+	if p == nil {
+		return nil
+	}
+	defer cj.removePendingRun(runUid)
+
+	log.Warn("aborting exp run", zap.String("expRunUID", runUid), zap.String("reason", reason))
+	status, message := core.RunAborted, "aborted, "+reason
+	if err := p.recover(); err != nil {
+		log.Warn("recovery failed", zap.String("expRunUID", runUid), zap.Error(err))
+		status, message = core.RunFailed, message+", but failed to recover: "+err.Error()
+	}
+	return perr.WithStack(cj.scheduler.expRunStore.Update(context.Background(), runUid, status, message))
+}
+
+// recoverRuns recovers all the pending runs at once, it returns the first error of the recoveries.
+func (cj *CronJob) recoverRuns(markFailed bool) error {
+	var firstErr error
+	for _, runUid := range cj.pendingRunUIDs() {
+		if err := cj.recoverRun(runUid, markFailed); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// addPendingRun makes the run wait for recovery, it is recovered after d if d isn't nil.
+func (cj *CronJob) addPendingRun(expRun *core.ExperimentRun, d *time.Duration) {
+	cj.lock.Lock()
+	defer cj.lock.Unlock()
+	p := &pendingRun{run: expRun, recover: cj.recoverFuncOf(expRun)}
+	if d != nil {
+		p.deadline = time.Now().Add(*d)
+	}
+	cj.putPendingRunLocked(p)
+}
+
+// putPendingRunLocked adds the pending run to the job, and starts a timer to recover it on its deadline.
+// The caller must hold cj.lock.
+func (cj *CronJob) putPendingRunLocked(p *pendingRun) {
+	if cj.pendingRuns == nil {
+		cj.pendingRuns = make(map[string]*pendingRun)
+	}
+	if !p.deadline.IsZero() {
+		runUid := p.run.UID
+		p.timer = time.AfterFunc(time.Until(p.deadline), func() {
+			_ = cj.recoverRun(runUid, true)
+		})
+	}
+	cj.pendingRuns[p.run.UID] = p
+}
+
+// takePendingRuns removes the pending runs which are not being recovered from the job and stops their timers,
+// so that they can be moved to another job.
+func (cj *CronJob) takePendingRuns() []*pendingRun {
+	cj.lock.Lock()
+	defer cj.lock.Unlock()
+	var runs []*pendingRun
+	for runUid, p := range cj.pendingRuns {
+		if p.recovering {
+			continue
+		}
+		if p.timer != nil {
+			p.timer.Stop()
+			p.timer = nil
+		}
+		delete(cj.pendingRuns, runUid)
+		runs = append(runs, p)
+	}
+	return runs
+}
+
+// recoverAfter recovers the run after d, the run can be recovered earlier when the job is paused.
+func (cj *CronJob) recoverAfter(expRun *core.ExperimentRun, d time.Duration) {
+	cj.addPendingRun(expRun, &d)
+}
+
+// takePendingRun marks the pending run as being recovered and stops its timer,
+// it returns nil if the run is not pending or is being recovered.
+func (cj *CronJob) takePendingRun(runUid string) *pendingRun {
+	cj.lock.Lock()
+	defer cj.lock.Unlock()
+	p, ok := cj.pendingRuns[runUid]
+	if !ok || p.recovering {
+		return nil
+	}
+	p.recovering = true
+	if p.timer != nil {
+		p.timer.Stop()
+	}
+	return p
+}
+
+func (cj *CronJob) removePendingRun(runUid string) {
+	cj.lock.Lock()
+	delete(cj.pendingRuns, runUid)
+	cj.lock.Unlock()
+}
+
+// pendingRunUIDs returns the uids of the runs waiting for recovery or being recovered.
+func (cj *CronJob) pendingRunUIDs() []string {
+	cj.lock.Lock()
+	defer cj.lock.Unlock()
+	uids := make([]string, 0, len(cj.pendingRuns))
+	for uid := range cj.pendingRuns {
+		uids = append(uids, uid)
+	}
+	return uids
+}
+
+func (cj *CronJob) isPaused() bool {
+	cj.lock.Lock()
+	defer cj.lock.Unlock()
+	return cj.paused
+}
+
+// startRunning marks the job running by its schedule, it returns false if the job is already running.
+func (cj *CronJob) startRunning() bool {
+	cj.lock.Lock()
+	defer cj.lock.Unlock()
+	if cj.running {
+		return false
 	}
+	cj.running = true
+	return true
+}
+
+func (cj *CronJob) stopRunning() {
+	cj.lock.Lock()
+	defer cj.lock.Unlock()
+	cj.running = false
+}
+
+func (cj *CronJob) isSuspended() bool {
+	cj.lock.Lock()
+	defer cj.lock.Unlock()
+	return cj.suspended
+}
+
+func (cj *CronJob) suspend(suspend bool) {
+	cj.lock.Lock()
+	cj.suspended = suspend
+	cj.lock.Unlock()
 }
 
-func (cj *CronJob) assertNoRecovery() bool {
+// stop suspends the job and recovers the pending runs at once like pause, but the error of the recovery
+// is returned, and the runs are kept waiting for recovery in the DB if they fail to recover.
+func (cj *CronJob) stop() error {
 	cj.lock.Lock()
-	waitingForRecovery := cj.waitForRecovery
+	cj.paused = true
 	cj.lock.Unlock()
-	return !waitingForRecovery
+	return cj.recoverRuns(false)
 }
 
-func (cj *CronJob) setWaitForRecovery(waitForRecovery bool) {
+// pause suspends the job and recovers the pending runs at once.
+func (cj *CronJob) pause() {
 	cj.lock.Lock()
-	cj.waitForRecovery = waitForRecovery
+	cj.paused = true
 	cj.lock.Unlock()
+	_ = cj.recoverRuns(true)
+}
+
+func (cj *CronJob) resume() {
+	cj.lock.Lock()
+	cj.paused = false
+	cj.lock.Unlock()
+}
+
+func (cj *CronJob) remove() {
+	cj.removeOnce.Do(func() {
+		close(cj.removed)
+	})
+}
+
+func (cj *CronJob) isRemoved() bool {
+	select {
+	case <-cj.removed:
+		return true
+	default:
+		return false
+	}
+}
+
+// wait waits for a random jitter of the schedule before the run, it returns false if the job
+// is paused or removed in the meantime.
+func (cj *CronJob) wait(config core.SchedulerConfig) bool {
+	jitter, err := config.GetJitter()
+	if err != nil || jitter <= 0 {
+		return true
+	}
+
+	delay := time.Duration(rand.Int63n(int64(jitter)))
+	log.Info("waiting for jitter before the run", zap.String("expId", cj.experiment.Uid), zap.Duration("delay", delay))
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return !cj.isPaused()
+	case <-cj.removed:
+		return false
+	}
+}
+
+// skip records a skipped run if the run should not be executed now, such as in a blackout window,
+// and removes the job when the schedule is finished. It returns true if the run is skipped.
+func (cj *CronJob) skip(config core.SchedulerConfig) bool {
+	runs, err := cj.scheduler.expRunStore.ListByExperimentID(context.Background(), cj.experiment.ID)
+	if err != nil {
+		log.Error("failed to list exp runs", zap.String("expId", cj.experiment.Uid), zap.Error(err))
+		return false
+	}
+	executed := 0
+	for _, run := range runs {
+		if run.Status != core.RunSkipped {
+			executed++
+		}
+	}
+
+	reason, finished := config.SkipReason(time.Now(), executed)
+	if len(reason) == 0 {
+		return false
+	}
+
+	cj.skipRun(reason)
+
+	if finished {
+		log.Info("schedule is finished", zap.String("expId", cj.experiment.Uid), zap.String("reason", reason))
+		_ = cj.scheduler.Remove(cj.experiment.ID)
+		if err := cj.scheduler.expStore.Update(context.Background(), cj.experiment.Uid, core.Destroyed,
+			"schedule is finished, "+reason, cj.experiment.RecoverCommand); err != nil {
+			log.Error("failed to update in DB", zap.Error(err))
+		}
+	}
+	return true
+}
+
+// admit applies the concurrency policy of the schedule when the previous runs are not recovered,
+// it returns the reason if the run can not start.
+func (cj *CronJob) admit(config core.SchedulerConfig) string {
+	if len(cj.pendingRunUIDs()) == 0 {
+		return ""
+	}
+
+	switch config.GetConcurrencyPolicy() {
+	case core.AllowConcurrent:
+		return ""
+	case core.ReplaceConcurrent:
+		log.Info("replacing the previous runs of attack", zap.String("expId", cj.experiment.Uid))
+		if err := cj.recoverRuns(true); err != nil {
+			return "failed to replace the previous run, " + err.Error()
+		}
+		return ""
+	default:
+		return "the previous run is not recovered"
+	}
+}
+
+// skipRun records a skipped run with the reason.
+func (cj *CronJob) skipRun(reason string) {
+	log.Info("skipping scheduled execution of attack", zap.String("expId", cj.experiment.Uid), zap.String("reason", reason))
+	skippedRun := cj.experiment.NewRun()
+	skippedRun.Status = core.RunSkipped
+	skippedRun.Message = reason
+	if err := cj.scheduler.expRunStore.NewRun(context.Background(), skippedRun); err != nil {
+		log.Error("failed to update in DB", zap.Error(err))
+	}
 }
 
 // Run implements cron.Job interface, used when scheduling cron jobs
 func (cj *CronJob) Run() {
-	if !cj.assertNoRecovery() {
-		log.Info("skipping scheduled execution of attack since recovery in progress", zap.String("expId", cj.experiment.Uid))
+	if cj.isPaused() {
+		log.Info("skipping scheduled execution of attack since it is paused", zap.String("expId", cj.experiment.Uid))
+		return
+	}
+	if cj.isSuspended() {
+		log.Info("skipping scheduled execution of attack since it is suspended", zap.String("expId", cj.experiment.Uid))
+		return
+	}
+	if !cj.isScheduled() {
+		return
+	}
+	// the tick coming while the previous one is still waiting for the jitter or running is recorded as skipped,
+	// instead of being dropped silently by the cron
+	if !cj.startRunning() {
+		cj.skipRun("the previous scheduled execution is still running")
+		return
+	}
+	defer cj.stopRunning()
+
+	// the invalid config makes the run fail in execute
+	cfg, err := cj.experiment.GetRequestCommand()
+	if err == nil {
+		config := cfg.GetSchedulerConfig()
+		if !cj.wait(config) || cj.skip(config) {
+			return
+		}
+	}
+
+	cj.runLock.Lock()
+	defer cj.runLock.Unlock()
+	if cj.isRemoved() {
+		// the job is replaced while waiting for the previous run
 		return
 	}
+	if err == nil {
+		if reason := cj.admit(cfg.GetSchedulerConfig()); len(reason) > 0 {
+			cj.skipRun(reason)
+			return
+		}
+	}
+	cj.execute()
+}
+
+// isScheduled re-checks the status of the experiment in the DB, because it may be paused or recovered
+// by another process, such as `chaosd recover` without chaosd server. The job is unscheduled if the
+// experiment is neither scheduled nor paused.
+func (cj *CronJob) isScheduled() bool {
+	exp, err := cj.scheduler.expStore.FindByUid(context.Background(), cj.experiment.Uid)
+	if err != nil {
+		log.Error("failed to find the experiment, skipping scheduled execution of attack",
+			zap.String("expId", cj.experiment.Uid), zap.Error(err))
+		return false
+	}
+
+	switch exp.Status {
+	case core.Scheduled:
+		return true
+	case core.Paused:
+		log.Info("skipping scheduled execution of attack since it is paused", zap.String("expId", cj.experiment.Uid))
+	default:
+		log.Info("unscheduling attack since it is not scheduled any more",
+			zap.String("expId", cj.experiment.Uid), zap.String("status", exp.Status))
+		cj.unschedule()
+	}
+	return false
+}
+
+// unschedule removes the job from the scheduler. The pending runs which are already recovered by
+// another process are dropped, and the others are recovered at once.
+func (cj *CronJob) unschedule() {
+	_ = cj.scheduler.Remove(cj.experiment.ID)
+
+	runs, err := cj.scheduler.expRunStore.ListByExperimentID(context.Background(), cj.experiment.ID)
+	if err != nil {
+		log.Error("failed to list exp runs", zap.String("expId", cj.experiment.Uid), zap.Error(err))
+	}
+	recovered := make(map[string]bool)
+	for _, run := range runs {
+		if run.Status != core.RunStarted && run.Status != core.RunSuccess {
+			recovered[run.UID] = true
+		}
+	}
+	for _, runUid := range cj.pendingRunUIDs() {
+		if !recovered[runUid] {
+			_ = cj.recoverRun(runUid, true)
+		} else if cj.takePendingRun(runUid) != nil {
+			cj.removePendingRun(runUid)
+		}
+	}
+}
 
-	var newRun *core.ExperimentRun
-	var recoverTimer *time.Timer
+// trigger starts an out-of-band run at once, regardless of the windows, the jitter and the suspension
+// of the schedule. The concurrency policy is still applied, the run is returned after the attack is injected.
+func (cj *CronJob) trigger() (*core.ExperimentRun, error) {
+	if cj.isPaused() {
+		return nil, core.ErrAttackConflict.New("can not trigger the paused experiment %s", cj.experiment.Uid)
+	}
+	cfg, err := cj.experiment.GetRequestCommand()
+	if err != nil {
+		return nil, err
+	}
+
+	cj.runLock.Lock()
+	defer cj.runLock.Unlock()
+	if reason := cj.admit(cfg.GetSchedulerConfig()); len(reason) > 0 {
+		return nil, core.ErrAttackConflict.New("can not trigger experiment %s, %s", cj.experiment.Uid, reason)
+	}
+	newRun := cj.execute()
+	if newRun == nil {
+		return nil, perr.Errorf("failed to start a run of experiment %s", cj.experiment.Uid)
+	}
+	return newRun, nil
+}
+
+// execute starts a new run, it returns nil if the run can not be created.
+func (cj *CronJob) execute() (newRun *core.ExperimentRun) {
 	defer func() {
 		var updErr error
 		if panicRec := recover(); panicRec != nil {
@@ -92,11 +520,8 @@ func (cj *CronJob) Run() {
 			}
 			log.Error("scheduled run errored", zap.String("expId", cj.experiment.Uid), zap.Error(panicErr))
 			if newRun != nil {
+				newRun.Status, newRun.Message = core.RunFailed, panicErr.Error()
 				updErr = cj.scheduler.expRunStore.Update(context.Background(), newRun.UID, core.RunFailed, panicErr.Error())
-				if recoverTimer != nil {
-					cj.setWaitForRecovery(false)
-					recoverTimer.Stop()
-				}
 			} else {
 				// cannot even create a new run, maybe due to config error
 				// so better to set ERROR on the experiment and remove from scheduler
@@ -106,7 +531,12 @@ func (cj *CronJob) Run() {
 		} else {
 			log.Info("scheduled run success", zap.String("expId", cj.experiment.Uid))
 			if newRun != nil {
+				newRun.Status = core.RunSuccess
 				updErr = cj.scheduler.expRunStore.Update(context.Background(), newRun.UID, core.RunSuccess, "")
+				if cj.isPaused() {
+					// the job is paused while the attack is being executed
+					_ = cj.recoverRun(newRun.UID, true)
+				}
 			}
 		}
 		if updErr != nil {
@@ -129,24 +559,27 @@ func (cj *CronJob) Run() {
 		panic(perr.WithStack(err))
 	}
 
-	if cronDuration != nil {
-		cj.setWaitForRecovery(true)
-		recoverTimer = time.AfterFunc(*cronDuration, func() {
-			cj.RecoverRun(newRun)
-		})
-	}
-
 	log.Info("executing attack on new exp run", zap.String("expRunUID", newRun.UID))
-	if err = cj.attackFunc(); err != nil {
+	startedAt := time.Now()
+	recoverData, err := cj.attackFunc(newRun.UID)
+	if err != nil {
 		panic(perr.WithMessage(err, "attack failed"))
 	}
+	newRun.RecoverCommand = recoverData
+	if err = cj.scheduler.expRunStore.SetRecoverCommand(context.Background(), newRun.UID, recoverData); err != nil {
+		log.Error("failed to update in DB", zap.Error(err))
+	}
+
+	if cronDuration != nil {
+		cj.recoverAfter(newRun, time.Until(startedAt.Add(*cronDuration)))
+	}
+	return newRun
 }
 
 func NewScheduler(expRunStore core.ExperimentRunStore, expStore core.ExperimentStore) Scheduler {
 	return Scheduler{
 		Cron: cron.New(
 			cron.WithLocation(time.UTC),
-			cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger)),
 			cron.WithLogger(zapr.NewLogger(log.L())),
 		),
 		expRunStore: expRunStore,
@@ -156,29 +589,191 @@ func NewScheduler(expRunStore core.ExperimentRunStore, expStore core.ExperimentS
 }
 
 func (scheduler *Scheduler) Schedule(
-	exp *core.Experiment, spec string, attackFunc func() error, recoverFunc func() error) error {
-	cj := CronJob{
+	exp *core.Experiment, spec string, attackFunc AttackFunc, recoverFunc RecoverFunc) error {
+	_, err := scheduler.schedule(exp, spec, attackFunc, recoverFunc)
+	return err
+}
+
+func (scheduler *Scheduler) schedule(
+	exp *core.Experiment, spec string, attackFunc AttackFunc, recoverFunc RecoverFunc) (*CronJob, error) {
+	cj := &CronJob{
 		scheduler:   scheduler,
 		experiment:  exp,
 		attackFunc:  attackFunc,
 		recoverFunc: recoverFunc,
+		removed:     make(chan struct{}),
+	}
+	if cfg, err := exp.GetRequestCommand(); err == nil {
+		cj.suspended = cfg.GetSchedulerConfig().Suspend
 	}
-	entryId, err := scheduler.AddJob(spec, &cj)
+	entryId, err := scheduler.AddJob(spec, cj)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	scheduler.cronStore.Add(exp.ID, entryId)
 	defer func() {
 		log.Info("Scheduled new attack cron", zap.String("expUid", exp.Uid), zap.String("cron", spec))
 	}()
+	return cj, nil
+}
+
+// Reschedule replaces the cron job of the experiment with a new one. The runs of the old job waiting for recovery
+// are moved to the new job, so that each of them is recovered only once, by the new job or on its deadline.
+func (scheduler *Scheduler) Reschedule(
+	exp *core.Experiment, spec string, attackFunc AttackFunc, recoverFunc RecoverFunc) error {
+	old, err := scheduler.getCronJob(exp.ID)
+	if err != nil {
+		return scheduler.Schedule(exp, spec, attackFunc, recoverFunc)
+	}
+
+	// no run of the old job can start while it is replaced
+	old.runLock.Lock()
+	defer old.runLock.Unlock()
+	_ = scheduler.Remove(exp.ID)
+	cj, err := scheduler.schedule(exp, spec, attackFunc, recoverFunc)
+	if err != nil {
+		// the pending runs are still recovered by the old job on their deadlines
+		return err
+	}
+
+	cj.lock.Lock()
+	defer cj.lock.Unlock()
+	for _, p := range old.takePendingRuns() {
+		cj.putPendingRunLocked(p)
+	}
 	return nil
 }
 
 func (scheduler Scheduler) Remove(expId uint) error {
+	if cj, err := scheduler.getCronJob(expId); err == nil {
+		cj.remove()
+	}
 	scheduler.Cron.Remove(scheduler.cronStore.Remove(expId))
 	return nil
 }
 
+// Unschedule removes the cron job of the experiment, and recovers the run waiting for recovery at once.
+// Different from Remove, it returns the error of the recovery. It does nothing if the experiment
+// is not scheduled by this scheduler.
+func (scheduler Scheduler) Unschedule(expId uint) error {
+	cj, err := scheduler.getCronJob(expId)
+	if err != nil {
+		return nil
+	}
+	err = cj.stop()
+	_ = scheduler.Remove(expId)
+	return err
+}
+
+// Pause suspends the cron job of the experiment without removing it,
+// the fault of the run waiting for recovery is recovered at once.
+func (scheduler Scheduler) Pause(expId uint) error {
+	cj, err := scheduler.getCronJob(expId)
+	if err != nil {
+		return err
+	}
+	cj.pause()
+	return nil
+}
+
+// Suspend suspends the cron job of the experiment in place, or unsuspends it if suspend is false.
+// The suspended job starts no new run on its schedule, but its runs waiting for recovery are still
+// recovered at the end of their durations.
+func (scheduler Scheduler) Suspend(expId uint, suspend bool) error {
+	cj, err := scheduler.getCronJob(expId)
+	if err != nil {
+		return err
+	}
+	cj.suspend(suspend)
+	return nil
+}
+
+// Resume makes the paused cron job of the experiment run on its schedule again.
+func (scheduler Scheduler) Resume(expId uint) error {
+	cj, err := scheduler.getCronJob(expId)
+	if err != nil {
+		return err
+	}
+	cj.resume()
+	return nil
+}
+
+// RemoveAll removes the cron jobs of all the experiments,
+// the faults of the runs waiting for recovery are recovered at once.
+func (scheduler Scheduler) RemoveAll() {
+	for _, expId := range scheduler.cronStore.List() {
+		if cj, err := scheduler.getCronJob(expId); err == nil {
+			cj.pause()
+		}
+		_ = scheduler.Remove(expId)
+	}
+}
+
+// Trigger starts an out-of-band run of the scheduled experiment at once, see CronJob.trigger.
+func (scheduler Scheduler) Trigger(expId uint) (*core.ExperimentRun, error) {
+	cj, err := scheduler.getCronJob(expId)
+	if err != nil {
+		return nil, err
+	}
+	return cj.trigger()
+}
+
+// AbortRun recovers the run of the experiment at once and marks it as aborted, it does nothing if the run
+// is recovered or being recovered.
+func (scheduler Scheduler) AbortRun(expId uint, runUid string, reason string) error {
+	cj, err := scheduler.getCronJob(expId)
+	if err != nil {
+		return err
+	}
+	return cj.abortRun(runUid, reason)
+}
+
+// JobState is the state of the cron job of a scheduled experiment.
+type JobState struct {
+	// Next and Prev are the next and the previous fire times of the job,
+	// they are zero if the job will not fire or has not fired.
+	Next time.Time
+	Prev time.Time
+	// Paused is true if the experiment is paused.
+	Paused bool
+	// PendingRuns are the uids of the runs waiting for recovery or being recovered.
+	PendingRuns []string
+}
+
+// GetJobState returns the state of the cron job of the experiment.
+func (scheduler Scheduler) GetJobState(expId uint) (*JobState, error) {
+	entryId, ok := scheduler.cronStore.Get(expId)
+	if !ok {
+		return nil, perr.Errorf("experiment %d is not scheduled", expId)
+	}
+	entry := scheduler.Entry(entryId)
+	cj, ok := entry.Job.(*CronJob)
+	if !ok {
+		return nil, perr.Errorf("cron job of experiment %d not found", expId)
+	}
+
+	pendingRuns := cj.pendingRunUIDs()
+	sort.Strings(pendingRuns)
+	return &JobState{
+		Next:        entry.Next,
+		Prev:        entry.Prev,
+		Paused:      cj.isPaused(),
+		PendingRuns: pendingRuns,
+	}, nil
+}
+
+func (scheduler Scheduler) getCronJob(expId uint) (*CronJob, error) {
+	entryId, ok := scheduler.cronStore.Get(expId)
+	if !ok {
+		return nil, perr.Errorf("experiment %d is not scheduled", expId)
+	}
+	cj, ok := scheduler.Entry(entryId).Job.(*CronJob)
+	if !ok {
+		return nil, perr.Errorf("cron job of experiment %d not found", expId)
+	}
+	return cj, nil
+}
+
 func (scheduler Scheduler) Start() {
 	log.Info("Starting Scheduler")
 	scheduler.Cron.Start()
@@ -14,9 +14,17 @@
 package scheduler
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
 )
 
 func TestScheduler_CronDurationExceeded(t *testing.T) {
@@ -47,3 +55,478 @@ func TestScheduler_CronDurationExceeded(t *testing.T) {
 		})
 	}
 }
+
+func TestScheduler_PauseResume(t *testing.T) {
+	exp := &core.Experiment{
+		ID:             1,
+		Uid:            "exp",
+		Status:         core.Scheduled,
+		Kind:           core.StressAttack,
+		RecoverCommand: `{"schedule":"@every 1h","duration":"1h","action":"cpu","kind":"stress"}`,
+	}
+	runStore := &fakeRunStore{}
+	scheduler := NewScheduler(runStore, &fakeExpStore{exps: []*core.Experiment{exp}})
+
+	attacked, recovered := 0, 0
+	cj, err := scheduler.schedule(exp, "@every 1h",
+		func(string) (string, error) { attacked++; return "", nil },
+		func(string) error { recovered++; return nil })
+	assert.NoError(t, err)
+
+	cj.Run()
+	assert.Equal(t, 1, attacked)
+	assert.Len(t, runStore.runs, 1)
+
+	// the run waiting for recovery is recovered at once
+	assert.NoError(t, scheduler.Pause(exp.ID))
+	assert.Equal(t, 1, recovered)
+	assert.Equal(t, core.RunRecovered, runStore.status(runStore.runs[0].UID))
+
+	// the paused job is kept in the scheduler but doesn't run
+	cj.Run()
+	assert.Equal(t, 1, attacked)
+	assert.Len(t, scheduler.Entries(), 1)
+
+	assert.NoError(t, scheduler.Resume(exp.ID))
+	cj.Run()
+	assert.Equal(t, 2, attacked)
+
+	assert.Error(t, scheduler.Pause(2))
+}
+
+func TestScheduler_RemoveAll(t *testing.T) {
+	exps := []*core.Experiment{
+		{ID: 1, Uid: "exp-1", Status: core.Scheduled, Kind: core.StressAttack,
+			RecoverCommand: `{"schedule":"@every 1h","duration":"1h","action":"cpu","kind":"stress"}`},
+		{ID: 2, Uid: "exp-2", Status: core.Scheduled, Kind: core.StressAttack,
+			RecoverCommand: `{"schedule":"@every 1h","duration":"1h","action":"cpu","kind":"stress"}`},
+	}
+	runStore := &fakeRunStore{}
+	scheduler := NewScheduler(runStore, &fakeExpStore{exps: exps})
+
+	recovered := 0
+	cj, err := scheduler.schedule(exps[0], "@every 1h",
+		func(string) (string, error) { return "", nil },
+		func(string) error { recovered++; return nil })
+	assert.NoError(t, err)
+	_, err = scheduler.schedule(exps[1], "@every 1h",
+		func(string) (string, error) { return "", nil },
+		func(string) error { recovered++; return nil })
+	assert.NoError(t, err)
+
+	cj.Run()
+	scheduler.RemoveAll()
+	// only the run waiting for recovery is recovered
+	assert.Equal(t, 1, recovered)
+	assert.Equal(t, core.RunRecovered, runStore.status(runStore.runs[0].UID))
+	assert.Empty(t, scheduler.Entries())
+	assert.Empty(t, scheduler.cronStore.List())
+}
+
+func TestScheduler_Unschedule(t *testing.T) {
+	exps := []*core.Experiment{
+		{ID: 1, Uid: "exp-1", Status: core.Scheduled, Kind: core.StressAttack,
+			RecoverCommand: `{"schedule":"@every 1h","duration":"1h","action":"cpu","kind":"stress"}`},
+		{ID: 2, Uid: "exp-2", Status: core.Scheduled, Kind: core.StressAttack,
+			RecoverCommand: `{"schedule":"@every 1h","duration":"1h","action":"cpu","kind":"stress"}`},
+	}
+	runStore := &fakeRunStore{}
+	scheduler := NewScheduler(runStore, &fakeExpStore{exps: exps})
+
+	recovered := 0
+	cj, err := scheduler.schedule(exps[0], "@every 1h",
+		func(string) (string, error) { return "", nil },
+		func(string) error { recovered++; return nil })
+	assert.NoError(t, err)
+	failedCj, err := scheduler.schedule(exps[1], "@every 1h",
+		func(string) (string, error) { return "", nil },
+		func(string) error { return errors.New("recover failed") })
+	assert.NoError(t, err)
+
+	cj.Run()
+	assert.NoError(t, scheduler.Unschedule(exps[0].ID))
+	assert.Equal(t, 1, recovered)
+	assert.Equal(t, core.RunRecovered, runStore.status(runStore.runs[0].UID))
+	assert.Len(t, scheduler.Entries(), 1)
+
+	// the run is kept waiting for recovery if it fails to recover
+	failedCj.Run()
+	assert.Error(t, scheduler.Unschedule(exps[1].ID))
+	assert.Equal(t, core.RunSuccess, runStore.status(runStore.runs[1].UID))
+	assert.Empty(t, scheduler.Entries())
+
+	assert.NoError(t, scheduler.Unschedule(exps[1].ID))
+}
+
+func TestScheduler_SkipRuns(t *testing.T) {
+	exp := &core.Experiment{
+		ID:     1,
+		Uid:    "exp",
+		Status: core.Scheduled,
+		Kind:   core.StressAttack,
+		RecoverCommand: `{"schedule":"@every 1h","duration":"1h","action":"cpu","kind":"stress",` +
+			`"max_runs":1,"blackouts":[{"start":"2000-01-01T00:00:00Z","end":"2100-01-01T00:00:00Z"}]}`,
+	}
+	expStore := &fakeExpStore{exps: []*core.Experiment{exp}}
+	runStore := &fakeRunStore{}
+	scheduler := NewScheduler(runStore, expStore)
+
+	attacked := 0
+	cj, err := scheduler.schedule(exp, "@every 1h",
+		func(string) (string, error) { attacked++; return "", nil },
+		func(string) error { return nil })
+	assert.NoError(t, err)
+
+	// the run in the blackout window is recorded as skipped
+	cj.Run()
+	assert.Equal(t, 0, attacked)
+	assert.Len(t, runStore.runs, 1)
+	assert.Equal(t, core.RunSkipped, runStore.runs[0].Status)
+	assert.Contains(t, runStore.runs[0].Message, "blackout")
+	assert.Equal(t, core.Scheduled, exp.Status)
+
+	// the skipped runs are not counted in the max runs
+	runStore.runs = append(runStore.runs, &core.ExperimentRun{UID: "executed", Status: core.RunRecovered, ExperimentID: exp.ID})
+	cj.Run()
+	assert.Equal(t, 0, attacked)
+	assert.Len(t, runStore.runs, 3)
+	assert.Equal(t, core.Destroyed, exp.Status)
+	assert.Contains(t, exp.Message, "max runs 1 is reached")
+	assert.Empty(t, scheduler.Entries())
+}
+
+func TestScheduler_Jitter(t *testing.T) {
+	exp := &core.Experiment{
+		ID:             1,
+		Uid:            "exp",
+		Status:         core.Scheduled,
+		Kind:           core.StressAttack,
+		RecoverCommand: `{"schedule":"@every 1h","duration":"1h","action":"cpu","kind":"stress","schedule_jitter":"1h"}`,
+	}
+	runStore := &fakeRunStore{}
+	scheduler := NewScheduler(runStore, &fakeExpStore{exps: []*core.Experiment{exp}})
+
+	attacked := 0
+	cj, err := scheduler.schedule(exp, "@every 1h",
+		func(string) (string, error) { attacked++; return "", nil },
+		func(string) error { return nil })
+	assert.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		cj.Run()
+		close(done)
+	}()
+
+	// removing the job stops waiting for the jitter
+	time.Sleep(10 * time.Millisecond)
+	assert.NoError(t, scheduler.Remove(exp.ID))
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("run is still waiting for the jitter after the job is removed")
+	}
+	assert.Equal(t, 0, attacked)
+	assert.Empty(t, runStore.runs)
+}
+
+func TestScheduler_ConcurrencyPolicy(t *testing.T) {
+	for _, test := range []struct {
+		policy    string
+		runs      []string
+		recovered []string
+		pending   int
+	}{
+		{
+			policy:    core.ForbidConcurrent,
+			runs:      []string{core.RunSuccess, core.RunSkipped},
+			recovered: nil,
+			pending:   1,
+		},
+		{
+			policy:    core.ReplaceConcurrent,
+			runs:      []string{core.RunRecovered, core.RunSuccess},
+			recovered: []string{"run-1"},
+			pending:   1,
+		},
+		{
+			policy:    core.AllowConcurrent,
+			runs:      []string{core.RunSuccess, core.RunSuccess},
+			recovered: nil,
+			pending:   2,
+		},
+	} {
+		t.Run(test.policy, func(t *testing.T) {
+			exp := &core.Experiment{
+				ID:     1,
+				Uid:    "exp",
+				Status: core.Scheduled,
+				Kind:   core.StressAttack,
+				RecoverCommand: `{"schedule":"@every 1h","duration":"1h","action":"cpu","kind":"stress",` +
+					`"concurrency_policy":"` + test.policy + `"}`,
+			}
+			runStore := &fakeRunStore{}
+			scheduler := NewScheduler(runStore, &fakeExpStore{exps: []*core.Experiment{exp}})
+
+			attacked := 0
+			var recovered []string
+			cj, err := scheduler.schedule(exp, "@every 1h",
+				func(string) (string, error) { attacked++; return fmt.Sprintf("run-%d", attacked), nil },
+				func(recoverData string) error { recovered = append(recovered, recoverData); return nil })
+			assert.NoError(t, err)
+
+			cj.Run()
+			cj.Run()
+			assert.Len(t, runStore.runs, len(test.runs))
+			for i, status := range test.runs {
+				assert.Equal(t, status, runStore.status(runStore.runs[i].UID))
+			}
+			assert.Equal(t, test.recovered, recovered)
+			assert.Len(t, cj.pendingRunUIDs(), test.pending)
+
+			// each run is recovered with its own data
+			recovered = nil
+			assert.NoError(t, scheduler.Pause(exp.ID))
+			assert.Len(t, recovered, test.pending)
+			for i := 0; i < attacked; i++ {
+				if runStore.runs[i].Status != core.RunSkipped {
+					assert.Equal(t, fmt.Sprintf("run-%d", i+1), runStore.runs[i].RecoverCommand)
+				}
+			}
+			assert.Empty(t, cj.pendingRunUIDs())
+		})
+	}
+}
+
+func TestScheduler_SuspendTrigger(t *testing.T) {
+	exp := &core.Experiment{
+		ID:             1,
+		Uid:            "exp",
+		Status:         core.Scheduled,
+		Kind:           core.StressAttack,
+		RecoverCommand: `{"schedule":"@every 1h","duration":"1h","action":"cpu","kind":"stress","suspend":true}`,
+	}
+	runStore := &fakeRunStore{}
+	scheduler := NewScheduler(runStore, &fakeExpStore{exps: []*core.Experiment{exp}})
+
+	attacked := 0
+	cj, err := scheduler.schedule(exp, "@every 1h",
+		func(string) (string, error) { attacked++; return "", nil },
+		func(string) error { return nil })
+	assert.NoError(t, err)
+
+	// the suspended job doesn't run on its schedule, but can be triggered
+	cj.Run()
+	assert.Equal(t, 0, attacked)
+	assert.Empty(t, runStore.runs)
+
+	run, err := scheduler.Trigger(exp.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, attacked)
+	assert.Equal(t, core.RunSuccess, run.Status)
+
+	state, err := scheduler.GetJobState(exp.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{run.UID}, state.PendingRuns)
+	assert.False(t, state.Paused)
+
+	// the job is unsuspended in place with its pending run
+	assert.NoError(t, scheduler.Suspend(exp.ID, false))
+	cj.Run()
+	assert.Len(t, runStore.runs, 2)
+	assert.Equal(t, core.RunSkipped, runStore.runs[1].Status)
+	assert.Equal(t, []string{run.UID}, cj.pendingRunUIDs())
+	assert.Error(t, scheduler.Suspend(2, true))
+
+	_, err = scheduler.Trigger(exp.ID)
+	assert.Error(t, err)
+	assert.NoError(t, scheduler.Pause(exp.ID))
+	_, err = scheduler.Trigger(exp.ID)
+	assert.Error(t, err)
+
+	_, err = scheduler.GetJobState(2)
+	assert.Error(t, err)
+}
+
+func TestScheduler_ExperimentNotScheduled(t *testing.T) {
+	exps := []*core.Experiment{
+		{ID: 1, Uid: "exp-1", Status: core.Scheduled, Kind: core.StressAttack,
+			RecoverCommand: `{"schedule":"@every 1h","duration":"1h","action":"cpu","kind":"stress"}`},
+		{ID: 2, Uid: "exp-2", Status: core.Scheduled, Kind: core.StressAttack,
+			RecoverCommand: `{"schedule":"@every 1h","duration":"1h","action":"cpu","kind":"stress"}`},
+	}
+	runStore := &fakeRunStore{}
+	scheduler := NewScheduler(runStore, &fakeExpStore{exps: exps})
+
+	attacked, recovered := 0, 0
+	cj, err := scheduler.schedule(exps[0], "@every 1h",
+		func(string) (string, error) { attacked++; return "", nil },
+		func(string) error { recovered++; return nil })
+	assert.NoError(t, err)
+	otherCj, err := scheduler.schedule(exps[1], "@every 1h",
+		func(string) (string, error) { attacked++; return "", nil },
+		func(string) error { recovered++; return nil })
+	assert.NoError(t, err)
+
+	// the experiment paused by another process is skipped but kept in the scheduler
+	exps[0].Status = core.Paused
+	cj.Run()
+	assert.Equal(t, 0, attacked)
+	assert.Len(t, scheduler.Entries(), 2)
+
+	exps[0].Status = core.Scheduled
+	cj.Run()
+	otherCj.Run()
+	assert.Equal(t, 2, attacked)
+
+	// the experiment recovered by another process is unscheduled, only its run
+	// which is not recovered by that process is recovered
+	assert.NoError(t, runStore.Update(context.Background(), runStore.runs[0].UID, core.RunRecovered, ""))
+	exps[0].Status = core.Destroyed
+	cj.Run()
+	assert.Equal(t, 2, attacked)
+	assert.Equal(t, 0, recovered)
+	assert.Len(t, scheduler.Entries(), 1)
+	assert.Empty(t, cj.pendingRunUIDs())
+
+	exps[1].Status = core.Destroyed
+	otherCj.Run()
+	assert.Equal(t, 1, recovered)
+	assert.Equal(t, core.RunRecovered, runStore.status(runStore.runs[1].UID))
+	assert.Empty(t, scheduler.Entries())
+}
+
+func TestScheduler_Reschedule(t *testing.T) {
+	exp := &core.Experiment{
+		ID:             1,
+		Uid:            "exp",
+		Status:         core.Scheduled,
+		Kind:           core.StressAttack,
+		RecoverCommand: `{"schedule":"@every 1h","duration":"100ms","action":"cpu","kind":"stress"}`,
+	}
+	runStore := &fakeRunStore{}
+	scheduler := NewScheduler(runStore, &fakeExpStore{exps: []*core.Experiment{exp}})
+
+	var lock sync.Mutex
+	recovered := make(map[string]int)
+	recoverFunc := func(job string) RecoverFunc {
+		return func(string) error {
+			lock.Lock()
+			defer lock.Unlock()
+			recovered[job]++
+			return nil
+		}
+	}
+	cj, err := scheduler.schedule(exp, "@every 1h", func(string) (string, error) { return "", nil }, recoverFunc("old"))
+	assert.NoError(t, err)
+	cj.Run()
+	assert.Len(t, cj.pendingRunUIDs(), 1)
+
+	// the run waiting for recovery is moved to the new job, and is recovered once on its deadline
+	assert.NoError(t, scheduler.Reschedule(exp, "@every 2h", func(string) (string, error) { return "", nil }, recoverFunc("new")))
+	assert.Len(t, scheduler.Entries(), 1)
+	assert.Empty(t, cj.pendingRunUIDs())
+	newCj, err := scheduler.getCronJob(exp.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{runStore.runs[0].UID}, newCj.pendingRunUIDs())
+
+	// the old job doesn't run any more
+	cj.Run()
+	assert.Len(t, runStore.runs, 1)
+
+	assert.Eventually(t, func() bool {
+		return runStore.status(runStore.runs[0].UID) == core.RunRecovered
+	}, time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	lock.Lock()
+	defer lock.Unlock()
+	// the run is recovered with the recover func of the job which started it
+	assert.Equal(t, map[string]int{"old": 1}, recovered)
+	assert.Empty(t, newCj.pendingRunUIDs())
+}
+
+func TestScheduler_AbortRun(t *testing.T) {
+	exp := &core.Experiment{
+		ID:     1,
+		Uid:    "exp",
+		Status: core.Scheduled,
+		Kind:   core.StressAttack,
+		RecoverCommand: `{"schedule":"@every 1h","duration":"1h","action":"cpu","kind":"stress",` +
+			`"concurrency_policy":"` + core.AllowConcurrent + `"}`,
+	}
+	runStore := &fakeRunStore{}
+	scheduler := NewScheduler(runStore, &fakeExpStore{exps: []*core.Experiment{exp}})
+
+	var recovered []string
+	recoverErr := error(nil)
+	_, err := scheduler.schedule(exp, "@every 1h",
+		func(runUid string) (string, error) { return runUid, nil },
+		func(recoverData string) error { recovered = append(recovered, recoverData); return recoverErr })
+	assert.NoError(t, err)
+
+	first, err := scheduler.Trigger(exp.ID)
+	assert.NoError(t, err)
+	second, err := scheduler.Trigger(exp.ID)
+	assert.NoError(t, err)
+
+	// only the aborted run is recovered, the experiment stays scheduled with its other runs
+	assert.NoError(t, scheduler.AbortRun(exp.ID, first.UID, "probe failed"))
+	assert.Equal(t, []string{first.UID}, recovered)
+	assert.Equal(t, core.RunAborted, runStore.status(first.UID))
+	assert.Equal(t, core.Scheduled, exp.Status)
+	state, err := scheduler.GetJobState(exp.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{second.UID}, state.PendingRuns)
+
+	// the run is aborted only once
+	assert.NoError(t, scheduler.AbortRun(exp.ID, first.UID, "probe failed"))
+	assert.Len(t, recovered, 1)
+
+	recoverErr = errors.New("recover failed")
+	assert.NoError(t, scheduler.AbortRun(exp.ID, second.UID, "probe failed"))
+	assert.Equal(t, core.RunFailed, runStore.status(second.UID))
+	assert.Error(t, scheduler.AbortRun(2, second.UID, "probe failed"))
+}
+
+func TestScheduler_SkipOverlappingTicks(t *testing.T) {
+	exp := &core.Experiment{
+		ID:             1,
+		Uid:            "exp",
+		Status:         core.Scheduled,
+		Kind:           core.StressAttack,
+		RecoverCommand: `{"schedule":"@every 1h","duration":"1h","action":"cpu","kind":"stress"}`,
+	}
+	runStore := &fakeRunStore{}
+	scheduler := NewScheduler(runStore, &fakeExpStore{exps: []*core.Experiment{exp}})
+
+	attacking, release := make(chan struct{}), make(chan struct{})
+	cj, err := scheduler.schedule(exp, "@every 1h",
+		func(string) (string, error) { close(attacking); <-release; return "", nil },
+		func(string) error { return nil })
+	assert.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		cj.Run()
+		close(done)
+	}()
+	<-attacking
+
+	// the tick coming while the previous one is running is recorded instead of being dropped
+	cj.Run()
+	runs, err := runStore.ListByExperimentID(context.Background(), exp.ID)
+	assert.NoError(t, err)
+	var skipped []string
+	for _, run := range runs {
+		if run.Status == core.RunSkipped {
+			skipped = append(skipped, run.Message)
+		}
+	}
+	assert.Equal(t, []string{"the previous scheduled execution is still running"}, skipped)
+
+	close(release)
+	<-done
+	runs, err = runStore.ListByExperimentID(context.Background(), exp.ID)
+	assert.NoError(t, err)
+	assert.Len(t, runs, 2)
+}
@@ -0,0 +1,146 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/log"
+	perr "github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+)
+
+// JobFuncsBuilder rebuilds the attack and recover functions of a stored experiment.
+type JobFuncsBuilder func(exp *core.Experiment) (attackFunc AttackFunc, recoverFunc RecoverFunc, err error)
+
+// Restore registers all the experiments in the Scheduled and Paused status to the scheduler again,
+// and reconciles the runs which were not recovered before chaosd exited.
+// It should be called before the scheduler starts.
+func (scheduler *Scheduler) Restore(build JobFuncsBuilder) error {
+	exps, err := scheduler.expStore.ListByStatus(context.Background(), core.Scheduled)
+	if err != nil {
+		return perr.WithStack(err)
+	}
+	pausedExps, err := scheduler.expStore.ListByStatus(context.Background(), core.Paused)
+	if err != nil {
+		return perr.WithStack(err)
+	}
+
+	jobs := make(map[uint]*CronJob)
+	for _, exp := range append(exps, pausedExps...) {
+		if exp.Status == core.Paused {
+			// only the paused experiments which have schedule are registered to the scheduler
+			cfg, err := exp.GetRequestCommand()
+			if err != nil || len(cfg.Cron()) == 0 {
+				continue
+			}
+		}
+
+		cj, err := scheduler.restoreExperiment(exp, build)
+		if err != nil {
+			log.Error("failed to restore scheduled experiment", zap.String("expUid", exp.Uid), zap.Error(err))
+			if err := scheduler.expStore.Update(context.Background(), exp.Uid, core.Error, err.Error(), exp.RecoverCommand); err != nil {
+				log.Error("failed to update in DB", zap.Error(err))
+			}
+			continue
+		}
+		if exp.Status == core.Paused {
+			cj.pause()
+		}
+		jobs[exp.ID] = cj
+	}
+
+	// The runs in the started status were interrupted during the attack,
+	// and the runs in the success status are still waiting for recovery.
+	for _, status := range []string{core.RunStarted, core.RunSuccess} {
+		runs, err := scheduler.expRunStore.ListByStatus(context.Background(), status)
+		if err != nil {
+			return perr.WithStack(err)
+		}
+
+		for _, run := range runs {
+			scheduler.reconcileRun(run, jobs[run.ExperimentID], build)
+		}
+	}
+	return nil
+}
+
+func (scheduler *Scheduler) restoreExperiment(exp *core.Experiment, build JobFuncsBuilder) (*CronJob, error) {
+	cfg, err := exp.GetRequestCommand()
+	if err != nil {
+		return nil, err
+	}
+
+	attackFunc, recoverFunc, err := build(exp)
+	if err != nil {
+		return nil, err
+	}
+
+	return scheduler.schedule(exp, cfg.GetSchedulerConfig().CronSpec(), attackFunc, recoverFunc)
+}
+
+func (scheduler *Scheduler) reconcileRun(run *core.ExperimentRun, cj *CronJob, build JobFuncsBuilder) {
+	exp := &run.Experiment
+	cfg, err := exp.GetRequestCommand()
+	if err != nil {
+		scheduler.failRun(run, err)
+		return
+	}
+
+	duration, err := cfg.ScheduleDuration()
+	if err != nil {
+		scheduler.failRun(run, err)
+		return
+	}
+
+	if duration == nil {
+		// nothing to recover for a run without duration
+		if run.Status == core.RunStarted {
+			scheduler.failRun(run, perr.New("the run was interrupted since chaosd exited"))
+		}
+		return
+	}
+
+	if cj == nil {
+		// the experiment is not scheduled anymore, but the fault of this run may be still there
+		_, recoverFunc, err := build(exp)
+		if err != nil {
+			scheduler.failRun(run, err)
+			return
+		}
+		cj = &CronJob{
+			scheduler:   scheduler,
+			experiment:  exp,
+			recoverFunc: recoverFunc,
+		}
+	}
+
+	if run.Status == core.RunStarted || hasCronDurationExceeded(run.StartAt, *duration) {
+		cj.RecoverRun(run)
+		return
+	}
+
+	log.Info("run will be recovered after the rest of duration", zap.String("expRunUID", run.UID))
+	cj.recoverAfter(run, time.Until(run.StartAt.Add(*duration)))
+}
+
+func (scheduler *Scheduler) failRun(run *core.ExperimentRun, err error) {
+	log.Warn("failed to reconcile exp run", zap.String("expRunUID", run.UID), zap.Error(err))
+	if err := scheduler.expRunStore.Update(context.Background(), run.UID, core.RunFailed, err.Error()); err != nil {
+		log.Error("failed to update in DB", zap.Error(err))
+	}
+}
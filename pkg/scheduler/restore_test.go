@@ -0,0 +1,165 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+)
+
+func init() {
+	// the attack kinds are registered by package chaosd, which imports this package
+	core.RegisterAttackConfig(core.StressAttack, func() core.AttackConfig { return &core.StressCommand{} })
+}
+
+type fakeExpStore struct {
+	core.ExperimentStore
+	exps []*core.Experiment
+}
+
+func (s *fakeExpStore) ListByStatus(_ context.Context, status string) ([]*core.Experiment, error) {
+	var exps []*core.Experiment
+	for _, exp := range s.exps {
+		if exp.Status == status {
+			exps = append(exps, exp)
+		}
+	}
+	return exps, nil
+}
+
+func (s *fakeExpStore) FindByUid(_ context.Context, uid string) (*core.Experiment, error) {
+	for _, exp := range s.exps {
+		if exp.Uid == uid {
+			return exp, nil
+		}
+	}
+	return nil, errors.New("experiment not found")
+}
+
+func (s *fakeExpStore) Update(_ context.Context, uid, status, msg string, _ string) error {
+	for _, exp := range s.exps {
+		if exp.Uid == uid {
+			exp.Status = status
+			exp.Message = msg
+		}
+	}
+	return nil
+}
+
+type fakeRunStore struct {
+	core.ExperimentRunStore
+	sync.Mutex
+	runs []*core.ExperimentRun
+}
+
+func (s *fakeRunStore) ListByStatus(_ context.Context, status string) ([]*core.ExperimentRun, error) {
+	s.Lock()
+	defer s.Unlock()
+	var runs []*core.ExperimentRun
+	for _, run := range s.runs {
+		if run.Status == status {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
+func (s *fakeRunStore) ListByExperimentID(_ context.Context, id uint) ([]*core.ExperimentRun, error) {
+	s.Lock()
+	defer s.Unlock()
+	var runs []*core.ExperimentRun
+	for _, run := range s.runs {
+		if run.ExperimentID == id {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
+func (s *fakeRunStore) Update(_ context.Context, runUid string, status string, message string) error {
+	s.Lock()
+	defer s.Unlock()
+	for _, run := range s.runs {
+		if run.UID == runUid {
+			run.Status = status
+			run.Message = message
+		}
+	}
+	return nil
+}
+
+func (s *fakeRunStore) SetRecoverCommand(_ context.Context, runUid string, recoverCommand string) error {
+	s.Lock()
+	defer s.Unlock()
+	for _, run := range s.runs {
+		if run.UID == runUid {
+			run.RecoverCommand = recoverCommand
+		}
+	}
+	return nil
+}
+
+func (s *fakeRunStore) NewRun(_ context.Context, run *core.ExperimentRun) error {
+	s.Lock()
+	defer s.Unlock()
+	s.runs = append(s.runs, run)
+	return nil
+}
+
+func (s *fakeRunStore) status(runUid string) string {
+	s.Lock()
+	defer s.Unlock()
+	for _, run := range s.runs {
+		if run.UID == runUid {
+			return run.Status
+		}
+	}
+	return ""
+}
+
+func TestScheduler_Restore(t *testing.T) {
+	exp := core.Experiment{
+		ID:             1,
+		Uid:            "exp",
+		Status:         core.Scheduled,
+		Kind:           core.StressAttack,
+		RecoverCommand: `{"schedule":"@every 1h","duration":"1m","action":"cpu","kind":"stress"}`,
+	}
+	expStore := &fakeExpStore{exps: []*core.Experiment{&exp}}
+	runStore := &fakeRunStore{runs: []*core.ExperimentRun{
+		{UID: "interrupted", Status: core.RunStarted, StartAt: time.Now(), ExperimentID: exp.ID, Experiment: exp},
+		{UID: "exceeded", Status: core.RunSuccess, StartAt: time.Now().Add(-time.Hour), ExperimentID: exp.ID, Experiment: exp},
+		{UID: "running", Status: core.RunSuccess, StartAt: time.Now(), ExperimentID: exp.ID, Experiment: exp},
+	}}
+
+	scheduler := NewScheduler(runStore, expStore)
+	recovered := 0
+	err := scheduler.Restore(func(exp *core.Experiment) (AttackFunc, RecoverFunc, error) {
+		return func(string) (string, error) { return "", nil }, func(string) error { recovered++; return nil }, nil
+	})
+	assert.NoError(t, err)
+
+	assert.Len(t, scheduler.Entries(), 1)
+	assert.Equal(t, 2, recovered)
+	assert.Equal(t, core.RunRecovered, runStore.status("interrupted"))
+	assert.Equal(t, core.RunRecovered, runStore.status("exceeded"))
+	assert.Equal(t, core.RunSuccess, runStore.status("running"))
+}
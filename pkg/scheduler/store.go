@@ -13,23 +13,56 @@
 
 package scheduler
 
-import cron "github.com/robfig/cron/v3"
+import (
+	"sync"
+
+	cron "github.com/robfig/cron/v3"
+)
 
 type CronStore interface {
 	Add(experimentId uint, cronEntryId cron.EntryID)
 	Remove(experimentId uint) cron.EntryID
+	Get(experimentId uint) (cron.EntryID, bool)
+	// List returns the IDs of all the scheduled experiments
+	List() []uint
 }
 
 type cronStore struct {
+	sync.Mutex
 	entry map[uint]cron.EntryID
 }
 
 func (cs *cronStore) Add(experimentId uint, cronEntryId cron.EntryID) {
+	cs.Lock()
+	defer cs.Unlock()
+
 	cs.entry[experimentId] = cronEntryId
 }
 
 func (cs *cronStore) Remove(experimentId uint) cron.EntryID {
+	cs.Lock()
+	defer cs.Unlock()
+
 	entryId := cs.entry[experimentId]
 	delete(cs.entry, experimentId)
 	return entryId
 }
+
+func (cs *cronStore) Get(experimentId uint) (cron.EntryID, bool) {
+	cs.Lock()
+	defer cs.Unlock()
+
+	entryId, ok := cs.entry[experimentId]
+	return entryId, ok
+}
+
+func (cs *cronStore) List() []uint {
+	cs.Lock()
+	defer cs.Unlock()
+
+	ids := make([]uint, 0, len(cs.entry))
+	for id := range cs.entry {
+		ids = append(ids, id)
+	}
+	return ids
+}
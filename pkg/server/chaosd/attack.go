@@ -17,6 +17,7 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/joomcode/errorx"
 	"github.com/pingcap/log"
 	perr "github.com/pkg/errors"
 	"go.uber.org/zap"
@@ -53,6 +54,14 @@ func (s *Server) newEnvironment(uid string) Environment {
 // If options.Schedule isn't provided, then the attack is executed immediately.
 // Otherwise the attack is scheduled based on the provided schedule spec and duration.
 func (s *Server) ExecuteAttack(attackType AttackType, options core.AttackConfig, launchMode string) (uid string, err error) {
+	if err = s.checkHalted(); err != nil {
+		return
+	}
+	if err = core.ValidateConcurrencyPolicy(options); err != nil {
+		err = core.ErrAttackConfigValidation.Wrap(err, "attack config validation failed")
+		return
+	}
+
 	uid = options.GetUID()
 	if len(uid) == 0 {
 		uid = uuid.New().String()
@@ -65,9 +74,11 @@ func (s *Server) ExecuteAttack(attackType AttackType, options core.AttackConfig,
 		Action:         options.String(),
 		RecoverCommand: options.RecoverData(),
 		LaunchMode:     launchMode,
+		Deadline:       getExperimentDeadline(options),
+		Labels:         options.GetLabels(),
+		Annotations:    options.GetAnnotations(),
 	}
-	if err = s.expStore.Set(context.Background(), exp); err != nil {
-		err = perr.WithStack(err)
+	if err = s.createExperiment(exp, options); err != nil {
 		return
 	}
 
@@ -88,15 +99,41 @@ func (s *Server) ExecuteAttack(attackType AttackType, options core.AttackConfig,
 		if err := s.expStore.Update(context.Background(), uid, newStatus, "", options.RecoverData()); err != nil {
 			log.Error("failed to update experiment", zap.Error(err))
 		}
+
+		// Halt skips the experiments which are still being injected, so the attack is recovered here
+		// if chaosd has been halted meanwhile
+		if haltErr := s.checkHalted(); errorx.IsOfType(haltErr, core.ErrHalted) {
+			if err := s.RecoverAttack(uid); err != nil {
+				log.Error("failed to recover experiment after chaosd is halted", zap.String("uid", uid), zap.Error(err))
+			}
+			err = haltErr
+			return
+		}
+
+		if err := s.armDeadline(exp); err != nil {
+			log.Error("failed to arm the deadline of experiment, it will not be recovered automatically",
+				zap.String("uid", uid), zap.Error(err))
+		}
+		if newStatus == core.Success {
+			if err := s.armProbes(exp, options.GetProbes()); err != nil {
+				log.Error("failed to arm the probes of experiment, they will not be checked while the attack is running",
+					zap.String("uid", uid), zap.Error(err))
+			}
+		}
 	}()
 
+	if result := checkProbes(options.GetProbes()); result != nil {
+		err = perr.Errorf("steady state is not met before injection, %s", result)
+		return
+	}
+
 	env := s.newEnvironment(uid)
 	if len(options.Cron()) > 0 {
 		if err = s.Cron.Schedule(
 			exp,
-			options.Cron(),
-			func() error { return attackType.Attack(options, env) },
-			func() error { return attackType.Recover(*exp, env) },
+			options.GetSchedulerConfig().CronSpec(),
+			s.scheduledAttackFunc(attackType, exp, env),
+			scheduledRecoverFunc(attackType, exp, env),
 		); err != nil {
 			err = perr.WithStack(err)
 			return
@@ -0,0 +1,153 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"github.com/chaos-mesh/chaosd/pkg/core"
+)
+
+// The built-in kinds are registered here, their subcommands of `chaosd attack` are attached by the cmd packages
+// with AttachAttackCommand, so that the configs stored in the DB can be decoded without the cmd packages.
+func init() {
+	RegisterAttackKind(AttackKind{
+		Kind:      core.ProcessAttack,
+		NewConfig: func() core.AttackConfig { return &core.ProcessCommand{} },
+		Attack:    ProcessAttack,
+		HTTP:      NewHTTPBinding("process", func() core.AttackConfig { return core.NewProcessCommand() }),
+	})
+	RegisterAttackKind(AttackKind{
+		Kind:      core.NetworkAttack,
+		NewConfig: func() core.AttackConfig { return &core.NetworkCommand{} },
+		Attack:    NetworkAttack,
+		HTTP:      NewHTTPBinding("network", func() core.AttackConfig { return core.NewNetworkCommand() }),
+	})
+	RegisterAttackKind(AttackKind{
+		Kind:      core.HostAttack,
+		NewConfig: func() core.AttackConfig { return &core.HostCommand{} },
+		Attack:    HostAttack,
+	})
+	RegisterAttackKind(AttackKind{
+		Kind:      core.StressAttack,
+		NewConfig: func() core.AttackConfig { return &core.StressCommand{} },
+		Attack:    StressAttack,
+		HTTP:      NewHTTPBinding("stress", func() core.AttackConfig { return core.NewStressCommand() }),
+	})
+	RegisterAttackKind(AttackKind{
+		Kind:      core.DiskAttack,
+		NewConfig: func() core.AttackConfig { return &core.DiskAttackConfig{} },
+		Attack:    DiskAttack,
+	})
+	// the disk attacks created by API run in the chaosd server process
+	RegisterAttackKind(AttackKind{
+		Kind:      core.DiskServerAttack,
+		NewConfig: func() core.AttackConfig { return &core.DiskAttackConfig{} },
+		Attack:    DiskServerAttack,
+		HTTP: &HTTPBinding{
+			Path: "disk",
+			Bind: func(decode func(obj interface{}) error) (core.AttackConfig, error) {
+				options := core.NewDiskOptionForServer()
+				if err := decode(options); err != nil {
+					return nil, err
+				}
+
+				options.CompleteDefaults()
+				return options.PreProcess()
+			},
+		},
+	})
+	RegisterAttackKind(AttackKind{
+		Kind:      core.JVMAttack,
+		NewConfig: func() core.AttackConfig { return &core.JVMCommand{} },
+		Attack:    JVMAttack,
+		HTTP:      NewHTTPBinding("jvm", func() core.AttackConfig { return core.NewJVMCommand() }),
+	})
+	RegisterAttackKind(AttackKind{
+		Kind:      core.ClockAttack,
+		NewConfig: func() core.AttackConfig { return &core.ClockOption{} },
+		Attack:    ClockAttack,
+		HTTP: &HTTPBinding{
+			Path: "clock",
+			Bind: func(decode func(obj interface{}) error) (core.AttackConfig, error) {
+				options := core.NewClockOption()
+				if err := decode(options); err != nil {
+					return nil, err
+				}
+
+				options.CompleteDefaults()
+				if err := options.PreProcess(); err != nil {
+					return nil, err
+				}
+				return options, nil
+			},
+		},
+	})
+	RegisterAttackKind(AttackKind{
+		Kind:      core.KafkaAttack,
+		NewConfig: func() core.AttackConfig { return &core.KafkaCommand{} },
+		Attack:    KafkaAttack,
+		HTTP:      NewHTTPBinding("kafka", func() core.AttackConfig { return core.NewKafkaCommand() }),
+	})
+	RegisterAttackKind(AttackKind{
+		Kind:      core.RedisAttack,
+		NewConfig: func() core.AttackConfig { return &core.RedisCommand{} },
+		Attack:    RedisAttack,
+		HTTP:      NewHTTPBinding("redis", func() core.AttackConfig { return core.NewRedisCommand() }),
+	})
+	RegisterAttackKind(AttackKind{
+		Kind:      core.FileAttack,
+		NewConfig: func() core.AttackConfig { return &core.FileCommand{} },
+		Attack:    FileAttack,
+	})
+	RegisterAttackKind(AttackKind{
+		Kind:      core.HTTPAttack,
+		NewConfig: func() core.AttackConfig { return &core.HTTPAttackConfig{} },
+		Attack:    HTTPAttack,
+		HTTP: &HTTPBinding{
+			Path: "http",
+			Bind: func(decode func(obj interface{}) error) (core.AttackConfig, error) {
+				options := core.NewHTTPAttackOption()
+				if err := decode(options); err != nil {
+					return nil, err
+				}
+
+				options.CompleteDefaults()
+				return options.PreProcess()
+			},
+		},
+	})
+	RegisterAttackKind(AttackKind{
+		Kind:      core.VMAttack,
+		NewConfig: func() core.AttackConfig { return &core.VMOption{} },
+		Attack:    VMAttack,
+		HTTP:      NewHTTPBinding("vm", func() core.AttackConfig { return core.NewVMOption() }),
+	})
+	RegisterAttackKind(AttackKind{
+		Kind:      core.UserDefinedAttack,
+		NewConfig: func() core.AttackConfig { return &core.UserDefinedOption{} },
+		Attack:    UserDefinedAttack,
+		HTTP:      NewHTTPBinding("user_defined", func() core.AttackConfig { return core.NewUserDefinedOption() }),
+	})
+	RegisterAttackKind(AttackKind{
+		Kind:      core.SystemdAttack,
+		NewConfig: func() core.AttackConfig { return &core.SystemdCommand{} },
+		Attack:    SystemdAttack,
+		HTTP:      NewHTTPBinding("systemd", func() core.AttackConfig { return core.NewSystemdCommand() }),
+	})
+	RegisterAttackKind(AttackKind{
+		Kind:      core.ContainerAttack,
+		NewConfig: func() core.AttackConfig { return &core.ContainerCommand{} },
+		Attack:    ContainerAttack,
+		HTTP:      NewHTTPBinding("container", func() core.AttackConfig { return core.NewContainerCommand() }),
+	})
+}
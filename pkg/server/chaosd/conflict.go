@@ -0,0 +1,156 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/log"
+	"go.uber.org/zap"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+)
+
+// resource is a resource of the host changed by an attack, such as ["clock", "1234"].
+// Two resources conflict if one of them is a prefix of the other, so that a resource
+// conflicts with its parts, such as a directory with the files in it.
+type resource []string
+
+func (r resource) String() string {
+	return strings.Join(r, "/")
+}
+
+func (r resource) conflicts(other resource) bool {
+	n := len(r)
+	if len(other) < n {
+		n = len(other)
+	}
+	for i := 0; i < n; i++ {
+		if r[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// checkConflicts rejects the attack if it changes a resource which is changed by a live experiment,
+// because the experiments could not be recovered correctly. The experiment with the uid is skipped.
+// The network attacks which change the same traffic in different ways are not conflicts,
+// their netem rules are merged into the same tc qdisc.
+func (s *Server) checkConflicts(options core.AttackConfig, uid string) error {
+	resources := attackResources(options)
+	if len(resources) == 0 {
+		return nil
+	}
+
+	exps, err := s.listLiveExperiments()
+	if err != nil {
+		return err
+	}
+	for _, exp := range exps {
+		if exp.Uid == uid || exp.Kind != options.AttackKind() {
+			continue
+		}
+		expOptions, err := exp.GetRequestCommand()
+		if err != nil {
+			log.Warn("failed to get the config of experiment, skip checking conflicts with it",
+				zap.String("uid", exp.Uid), zap.Error(err))
+			continue
+		}
+
+		for _, r := range resources {
+			for _, other := range attackResources(expOptions) {
+				if r.conflicts(other) {
+					return core.ErrAttackConflict.New("attack conflicts with %s experiment %s on %s",
+						exp.Status, exp.Uid, other)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// attackResources returns the resources changed by the attack, the attacks of other kinds
+// or changing nothing exclusively return nil.
+func attackResources(options core.AttackConfig) []resource {
+	switch config := options.(type) {
+	case *core.NetworkCommand:
+		return networkResources(config)
+	case *core.ClockOption:
+		return []resource{{"clock", strconv.Itoa(config.Pid)}}
+	case *core.FileCommand:
+		var resources []resource
+		for _, path := range []string{config.FileName, config.DirName, config.SourceFile, config.DestFile} {
+			if len(path) > 0 {
+				resources = append(resources, pathResource(path))
+			}
+		}
+		return resources
+	case *core.RedisCommand:
+		switch config.Action {
+		case core.RedisSentinelRestartAction, core.RedisSentinelStopAction:
+			return []resource{{"redis", config.Addr}}
+		case core.RedisCacheLimitAction:
+			return []resource{{"redis", config.Addr, "config", "maxmemory"}}
+		case core.RedisCacheExpirationAction:
+			if len(config.Key) == 0 {
+				return []resource{{"redis", config.Addr, "key"}}
+			}
+			return []resource{{"redis", config.Addr, "key", config.Key}}
+		}
+	case *core.KafkaCommand:
+		if len(config.Topic) > 0 {
+			return []resource{{"kafka", config.Topic}}
+		}
+	case *core.SystemdCommand:
+		return []resource{{"systemd", config.Unit}}
+	case *core.ContainerCommand:
+		var resources []resource
+		for _, id := range config.ContainerIDs {
+			resources = append(resources, resource{"container", id})
+		}
+		return resources
+	}
+	return nil
+}
+
+// networkResources returns the qdisc of the traffic changed by the network attack. The netem rules which
+// do not filter the traffic by ipset are merged, so they only conflict with the ones of the same action.
+// The rules filtering the traffic by ipset are never merged, since the ipset is created for each experiment.
+// The devices in the network namespaces of containers are different from the ones of the host.
+func networkResources(config *core.NetworkCommand) []resource {
+	if !config.NeedApplyTC() {
+		return nil
+	}
+
+	traffic := strings.Join([]string{config.IPAddress, config.Hostname, config.IPProtocol,
+		config.SourcePort, config.EgressPort, config.Direction, config.AcceptTCPFlags}, "|")
+	qdisc := resource{"network", config.Device, "qdisc", traffic}
+	if target := config.NetNSTarget(); len(target) > 0 {
+		qdisc = append(resource{"network", target}, qdisc[1:]...)
+	}
+	if config.NeedApplyIPSet() {
+		return []resource{qdisc}
+	}
+	return []resource{append(qdisc, config.Action)}
+}
+
+func pathResource(path string) resource {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return append(resource{"file"}, strings.Split(strings.TrimPrefix(filepath.Clean(path), "/"), "/")...)
+}
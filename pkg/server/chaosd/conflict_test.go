@@ -0,0 +1,106 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"testing"
+
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+)
+
+func newConflictTestExperiment(uid, status string, options core.AttackConfig) *core.Experiment {
+	return &core.Experiment{
+		Uid:            uid,
+		Kind:           options.AttackKind(),
+		Status:         status,
+		RecoverCommand: options.RecoverData(),
+	}
+}
+
+func newNetworkCommand(action, device, ip string) *core.NetworkCommand {
+	return &core.NetworkCommand{
+		CommonAttackConfig: core.CommonAttackConfig{Kind: core.NetworkAttack, Action: action},
+		Device:             device,
+		IPAddress:          ip,
+	}
+}
+
+func TestServer_CheckConflicts(t *testing.T) {
+	store := &fakeExpStore{exps: []*core.Experiment{
+		newConflictTestExperiment("delay", core.Success, newNetworkCommand(core.NetworkDelayAction, "eth0", "")),
+		newConflictTestExperiment("filtered", core.Paused, newNetworkCommand(core.NetworkLossAction, "eth0", "10.0.0.1")),
+		newConflictTestExperiment("recovered", core.Destroyed, newNetworkCommand(core.NetworkLossAction, "eth0", "")),
+		newConflictTestExperiment("clock", core.Scheduled, &core.ClockOption{
+			CommonAttackConfig: core.CommonAttackConfig{Kind: core.ClockAttack}, Pid: 1234}),
+		newConflictTestExperiment("file", core.Created, &core.FileCommand{
+			CommonAttackConfig: core.CommonAttackConfig{Kind: core.FileAttack, Action: core.FileDeleteAction}, DirName: "/tmp/data"}),
+	}}
+	s := &Server{expStore: store}
+
+	assertConflict := func(options core.AttackConfig, uid string) {
+		err := s.checkConflicts(options, "new")
+		assert.True(t, errorx.IsOfType(err, core.ErrAttackConflict), "%v", err)
+		assert.Contains(t, err.Error(), uid)
+	}
+
+	assertConflict(newNetworkCommand(core.NetworkDelayAction, "eth0", ""), "delay")
+	assertConflict(newNetworkCommand(core.NetworkDuplicateAction, "eth0", "10.0.0.1"), "filtered")
+	assertConflict(&core.ClockOption{CommonAttackConfig: core.CommonAttackConfig{Kind: core.ClockAttack}, Pid: 1234}, "clock")
+	assertConflict(&core.FileCommand{
+		CommonAttackConfig: core.CommonAttackConfig{Kind: core.FileAttack, Action: core.FileRenameAction},
+		SourceFile:         "/tmp/a", DestFile: "/tmp/data/b"}, "file")
+
+	// the netem rules of the same traffic are merged
+	assert.NoError(t, s.checkConflicts(newNetworkCommand(core.NetworkLossAction, "eth0", ""), "new"))
+	assert.NoError(t, s.checkConflicts(newNetworkCommand(core.NetworkDelayAction, "eth1", ""), "new"))
+	assert.NoError(t, s.checkConflicts(newNetworkCommand(core.NetworkDelayAction, "eth0", "10.0.0.2"), "new"))
+	assert.NoError(t, s.checkConflicts(newNetworkCommand(core.NetworkPartitionAction, "eth0", ""), "new"))
+	// the device of the container is not the one of the host
+	inContainer := newNetworkCommand(core.NetworkDelayAction, "eth0", "")
+	inContainer.ContainerID = "docker://web"
+	assert.NoError(t, s.checkConflicts(inContainer, "new"))
+	assert.NoError(t, s.checkConflicts(&core.ClockOption{CommonAttackConfig: core.CommonAttackConfig{Kind: core.ClockAttack}, Pid: 4321}, "new"))
+	assert.NoError(t, s.checkConflicts(&core.FileCommand{
+		CommonAttackConfig: core.CommonAttackConfig{Kind: core.FileAttack, Action: core.FileCreateAction}, FileName: "/tmp/database"}, "new"))
+
+	// the experiment itself is skipped when it is updated
+	assert.NoError(t, s.checkConflicts(newNetworkCommand(core.NetworkDelayAction, "eth0", ""), "delay"))
+}
+
+func TestAttackResources(t *testing.T) {
+	redis := func(action, key string) core.AttackConfig {
+		return &core.RedisCommand{
+			CommonAttackConfig: core.CommonAttackConfig{Kind: core.RedisAttack, Action: action},
+			Addr:               "127.0.0.1:6379",
+			Key:                key,
+		}
+	}
+	stop := attackResources(redis(core.RedisSentinelStopAction, ""))[0]
+	allKeys := attackResources(redis(core.RedisCacheExpirationAction, ""))[0]
+	key := attackResources(redis(core.RedisCacheExpirationAction, "user"))[0]
+	otherKey := attackResources(redis(core.RedisCacheExpirationAction, "order"))[0]
+	cacheLimit := attackResources(redis(core.RedisCacheLimitAction, ""))[0]
+
+	assert.True(t, stop.conflicts(key))
+	assert.True(t, allKeys.conflicts(key))
+	assert.False(t, key.conflicts(otherKey))
+	assert.False(t, cacheLimit.conflicts(key))
+	assert.Empty(t, attackResources(redis(core.RedisCachePenetrationAction, "")))
+
+	assert.Equal(t, "kafka/orders", attackResources(&core.KafkaCommand{Topic: "orders"})[0].String())
+	assert.Empty(t, attackResources(&core.StressCommand{}))
+}
@@ -0,0 +1,177 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"go.uber.org/zap"
+
+	"github.com/chaos-mesh/chaosd/pkg/config"
+	"github.com/chaos-mesh/chaosd/pkg/container"
+	"github.com/chaos-mesh/chaosd/pkg/core"
+)
+
+type containerAttack struct{}
+
+var ContainerAttack AttackType = containerAttack{}
+
+// newCRIClient creates the client of the container runtime, it is replaced in the tests.
+var newCRIClient = container.NewCRIClient
+
+// criClientOf returns the client of the runtime of the container ID, or the runtime of chaosd if the container ID
+// is empty. The containers are recovered with the runtime they are attacked with, even if chaosd runs with another one.
+func criClientOf(env Environment, containerID string) (container.CRIClient, error) {
+	conf := config.Config{}
+	if env.Chaos != nil && env.Chaos.conf != nil {
+		conf = *env.Chaos.conf
+	}
+	if runtime := container.RuntimeOf(containerID); len(runtime) > 0 {
+		conf.Runtime = runtime
+	}
+	return newCRIClient(&conf)
+}
+
+// Attack attacks every container with the runtime of its ID, so that the docker and containerd containers
+// can be attacked together.
+func (containerAttack) Attack(options core.AttackConfig, env Environment) error {
+	attack := options.(*core.ContainerCommand)
+
+	ctx := context.Background()
+	ids, err := findContainers(ctx, env, attack)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		client, err := criClientOf(env, id)
+		if err != nil {
+			return err
+		}
+		switch attack.Action {
+		case core.ContainerKillAction:
+			err = client.ContainerKillByContainerID(ctx, id)
+		case core.ContainerStopAction:
+			err = client.ContainerStop(ctx, id)
+		case core.ContainerRestartAction:
+			err = client.ContainerRestart(ctx, id)
+		case core.ContainerPauseAction:
+			err = client.ContainerPause(ctx, id)
+		case core.ContainerUnpauseAction:
+			err = client.ContainerUnpause(ctx, id)
+		}
+		if err != nil {
+			return errors.Annotatef(err, "%s container %s", attack.Action, id)
+		}
+		attack.Attacked = append(attack.Attacked, id)
+	}
+
+	log.Info("attack containers successfully", zap.String("action", attack.Action), zap.Strings("containers", ids))
+	return nil
+}
+
+func (containerAttack) Plan(options core.AttackConfig, env Environment) ([]string, error) {
+	attack := options.(*core.ContainerCommand)
+
+	ids, err := findContainers(context.Background(), env, attack)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := make([]string, 0, len(ids)+1)
+	for _, id := range ids {
+		steps = append(steps, fmt.Sprintf("%s container %s", attack.Action, id))
+	}
+	switch attack.Action {
+	case core.ContainerPauseAction:
+		return append(steps, "unpause the containers when recovering"), nil
+	case core.ContainerUnpauseAction:
+		return append(steps, "pause the containers again when recovering"), nil
+	}
+	return append(steps, "start the stopped containers when recovering"), nil
+}
+
+// findContainers returns the containers to be attacked, the ones selected by the name and the labels
+// should be running or paused, and are listed with the runtime of chaosd.
+func findContainers(ctx context.Context, env Environment, attack *core.ContainerCommand) ([]string, error) {
+	if len(attack.ContainerIDs) > 0 {
+		return attack.ContainerIDs, nil
+	}
+
+	client, err := criClientOf(env, "")
+	if err != nil {
+		return nil, err
+	}
+	ids, err := client.ListContainers(ctx, attack.ContainerName, attack.ContainerLabels)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, errors.Errorf("container %s not found", attack.Selector())
+	}
+	return ids, nil
+}
+
+// Recover unpauses the paused containers, pauses the unpaused containers again, and starts the containers which are
+// not running, such as the ones without restart policy. All the containers are tried even if some of them fail.
+func (containerAttack) Recover(exp core.Experiment, env Environment) error {
+	config, err := exp.GetRequestCommand()
+	if err != nil {
+		return err
+	}
+	attack := config.(*core.ContainerCommand)
+
+	var errs []string
+	for _, id := range attack.Attacked {
+		client, err := criClientOf(env, id)
+		if err == nil {
+			err = recoverContainer(context.Background(), client, attack.Action, id)
+		}
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Errorf("recover containers: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func recoverContainer(ctx context.Context, client container.CRIClient, action, id string) error {
+	state, err := client.ContainerState(ctx, id)
+	if err != nil {
+		return errors.Annotatef(err, "get state of container %s", id)
+	}
+
+	switch {
+	case action == core.ContainerPauseAction && state == container.ContainerPaused:
+		err = client.ContainerUnpause(ctx, id)
+	case action == core.ContainerUnpauseAction && state == container.ContainerRunning:
+		err = client.ContainerPause(ctx, id)
+	case action != core.ContainerPauseAction && action != core.ContainerUnpauseAction && state == container.ContainerStopped:
+		err = client.ContainerStart(ctx, id)
+	default:
+		return nil
+	}
+	if err != nil {
+		return errors.Annotatef(err, "recover container %s", id)
+	}
+
+	log.Info("recover container successfully", zap.String("container", id), zap.String("action", action))
+	return nil
+}
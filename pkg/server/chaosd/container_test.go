@@ -0,0 +1,227 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/pingcap/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chaos-mesh/chaosd/pkg/config"
+	"github.com/chaos-mesh/chaosd/pkg/container"
+	"github.com/chaos-mesh/chaosd/pkg/core"
+)
+
+type fakeContainer struct {
+	name   string
+	labels map[string]string
+	state  string
+	// restartPolicy brings the container back after it is killed, like the restart policy of docker
+	restartPolicy bool
+}
+
+// fakeCRIClient changes the states of the containers like the runtime, and records the calls.
+type fakeCRIClient struct {
+	runtime    string
+	containers map[string]*fakeContainer
+	calls      []string
+}
+
+func (c *fakeCRIClient) GetPidFromContainerID(context.Context, string) (uint32, error) {
+	return 0, errors.New("not supported")
+}
+
+func (c *fakeCRIClient) FormatContainerID(_ context.Context, containerID string) (string, error) {
+	return containerID, nil
+}
+
+func (c *fakeCRIClient) ListContainers(_ context.Context, name string, labels map[string]string) ([]string, error) {
+	var ids []string
+	for id, ctr := range c.containers {
+		if ctr.state == container.ContainerStopped || (len(name) > 0 && ctr.name != name) {
+			continue
+		}
+		matched := true
+		for key, value := range labels {
+			if ctr.labels[key] != value {
+				matched = false
+			}
+		}
+		if matched {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func (c *fakeCRIClient) ContainerState(_ context.Context, containerID string) (string, error) {
+	ctr, ok := c.containers[containerID]
+	if !ok {
+		return "", errors.Errorf("container %s not found", containerID)
+	}
+	return ctr.state, nil
+}
+
+func (c *fakeCRIClient) set(call, containerID string, change func(ctr *fakeContainer)) error {
+	c.calls = append(c.calls, c.runtime+" "+call+" "+containerID)
+	ctr, ok := c.containers[containerID]
+	if !ok {
+		return errors.Errorf("container %s not found", containerID)
+	}
+	change(ctr)
+	return nil
+}
+
+func (c *fakeCRIClient) ContainerKillByContainerID(_ context.Context, containerID string) error {
+	return c.set("kill", containerID, func(ctr *fakeContainer) {
+		if !ctr.restartPolicy {
+			ctr.state = container.ContainerStopped
+		}
+	})
+}
+
+func (c *fakeCRIClient) ContainerStop(_ context.Context, containerID string) error {
+	return c.set("stop", containerID, func(ctr *fakeContainer) { ctr.state = container.ContainerStopped })
+}
+
+func (c *fakeCRIClient) ContainerStart(_ context.Context, containerID string) error {
+	return c.set("start", containerID, func(ctr *fakeContainer) { ctr.state = container.ContainerRunning })
+}
+
+func (c *fakeCRIClient) ContainerRestart(_ context.Context, containerID string) error {
+	return c.set("restart", containerID, func(ctr *fakeContainer) { ctr.state = container.ContainerRunning })
+}
+
+func (c *fakeCRIClient) ContainerPause(_ context.Context, containerID string) error {
+	return c.set("pause", containerID, func(ctr *fakeContainer) { ctr.state = container.ContainerPaused })
+}
+
+func (c *fakeCRIClient) ContainerUnpause(_ context.Context, containerID string) error {
+	return c.set("unpause", containerID, func(ctr *fakeContainer) { ctr.state = container.ContainerRunning })
+}
+
+func TestContainerAttack(t *testing.T) {
+	client := &fakeCRIClient{}
+	newClient := newCRIClient
+	newCRIClient = func(conf *config.Config) (container.CRIClient, error) {
+		client.runtime = conf.Runtime
+		return client, nil
+	}
+	defer func() { newCRIClient = newClient }()
+
+	env := Environment{Chaos: &Server{conf: &config.Config{Runtime: "docker"}}}
+	for _, test := range []struct {
+		name    string
+		action  string
+		options core.ContainerCommand
+		attack  []string
+		recover []string
+	}{
+		{
+			name:    "kill without restart policy",
+			action:  core.ContainerKillAction,
+			options: core.ContainerCommand{ContainerIDs: []string{"docker://web"}},
+			attack:  []string{"docker kill docker://web"},
+			recover: []string{"docker start docker://web"},
+		},
+		{
+			// the container brought back by the restart policy is not started again
+			name:    "kill with restart policy",
+			action:  core.ContainerKillAction,
+			options: core.ContainerCommand{ContainerIDs: []string{"docker://db"}},
+			attack:  []string{"docker kill docker://db"},
+		},
+		{
+			name:    "stop by name",
+			action:  core.ContainerStopAction,
+			options: core.ContainerCommand{ContainerName: "web"},
+			attack:  []string{"docker stop docker://web"},
+			recover: []string{"docker start docker://web"},
+		},
+		{
+			name:    "pause by labels",
+			action:  core.ContainerPauseAction,
+			options: core.ContainerCommand{ContainerLabels: map[string]string{"app": "shop"}},
+			attack:  []string{"docker pause docker://db", "docker pause docker://web"},
+			recover: []string{"docker unpause docker://db", "docker unpause docker://web"},
+		},
+		{
+			name:    "restart",
+			action:  core.ContainerRestartAction,
+			options: core.ContainerCommand{ContainerIDs: []string{"docker://web"}},
+			attack:  []string{"docker restart docker://web"},
+		},
+		{
+			// the container is recovered with the runtime of its id instead of the runtime of chaosd
+			name:    "stop containerd container",
+			action:  core.ContainerStopAction,
+			options: core.ContainerCommand{ContainerIDs: []string{"containerd://cache"}},
+			attack:  []string{"containerd stop containerd://cache"},
+			recover: []string{"containerd start containerd://cache"},
+		},
+		{
+			// every container is attacked and recovered with the runtime of its own id
+			name:    "stop containers of both runtimes",
+			action:  core.ContainerStopAction,
+			options: core.ContainerCommand{ContainerIDs: []string{"docker://web", "containerd://cache"}},
+			attack:  []string{"docker stop docker://web", "containerd stop containerd://cache"},
+			recover: []string{"docker start docker://web", "containerd start containerd://cache"},
+		},
+		{
+			name:    "unpause",
+			action:  core.ContainerUnpauseAction,
+			options: core.ContainerCommand{ContainerName: "queue"},
+			attack:  []string{"docker unpause docker://queue"},
+			recover: []string{"docker pause docker://queue"},
+		},
+	} {
+		client.containers = map[string]*fakeContainer{
+			"docker://web":       {name: "web", labels: map[string]string{"app": "shop"}, state: container.ContainerRunning},
+			"docker://db":        {name: "db", labels: map[string]string{"app": "shop"}, state: container.ContainerRunning, restartPolicy: true},
+			"docker://queue":     {name: "queue", state: container.ContainerPaused},
+			"containerd://cache": {name: "cache", state: container.ContainerRunning},
+		}
+		client.calls = nil
+
+		options := core.NewContainerCommand()
+		options.ContainerIDs, options.ContainerName, options.ContainerLabels =
+			test.options.ContainerIDs, test.options.ContainerName, test.options.ContainerLabels
+		options.Action = test.action
+		options.CompleteDefaults()
+		assert.NoError(t, options.Validate(), test.name)
+		assert.NoError(t, ContainerAttack.Attack(options, env), test.name)
+		assert.Equal(t, test.attack, client.calls, test.name)
+
+		client.calls = nil
+		exp := core.Experiment{Kind: core.ContainerAttack, RecoverCommand: options.RecoverData()}
+		assert.NoError(t, ContainerAttack.Recover(exp, env), test.name)
+		assert.Equal(t, test.recover, client.calls, test.name)
+		// the containers are back to their states before the attack
+		for id, ctr := range client.containers {
+			want := container.ContainerRunning
+			if id == "docker://queue" {
+				want = container.ContainerPaused
+			}
+			assert.Equal(t, want, ctr.state, "%s %s", test.name, id)
+		}
+	}
+
+	options := core.NewContainerCommand()
+	options.Action, options.ContainerName = core.ContainerStopAction, "missing"
+	assert.Error(t, ContainerAttack.Attack(options, env))
+}
@@ -0,0 +1,226 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+
+	"github.com/pingcap/log"
+	perr "github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+	"github.com/chaos-mesh/chaosd/pkg/utils"
+)
+
+// getExperimentDeadline returns the time when a non-scheduled attack should be recovered,
+// it returns nil if the attack has no valid duration.
+func getExperimentDeadline(options core.AttackConfig) *time.Time {
+	if len(options.Cron()) > 0 {
+		// every run of a scheduled attack is recovered by the scheduler
+		return nil
+	}
+
+	duration, err := options.ScheduleDuration()
+	if err != nil {
+		// some attacks, such as network flood, use their own format of duration
+		log.Warn("ignore the duration which can not be parsed, the attack will not be recovered automatically",
+			zap.String("kind", options.AttackKind()), zap.Error(err))
+		return nil
+	}
+	if duration == nil || *duration <= 0 {
+		return nil
+	}
+
+	deadline := time.Now().Add(*duration)
+	return &deadline
+}
+
+// armDeadline makes sure the experiment will be recovered when its deadline is reached.
+// In chaosd server a timer is started for the experiments in server mode, otherwise a detached
+// chaosd process is started to wait for the deadline, because the current process will exit soon.
+func (s *Server) armDeadline(exp *core.Experiment) error {
+	if exp.Deadline == nil {
+		return nil
+	}
+
+	if exp.LaunchMode == core.CommandMode || !s.serving {
+		return startDeadlineHelper(exp.Uid)
+	}
+
+	s.startDeadlineTimer(exp.Uid, *exp.Deadline)
+	return nil
+}
+
+func (s *Server) startDeadlineTimer(uid string, deadline time.Time) {
+	s.deadlineLock.Lock()
+	defer s.deadlineLock.Unlock()
+
+	if timer, ok := s.deadlineTimers[uid]; ok {
+		timer.Stop()
+	}
+	s.deadlineTimers[uid] = time.AfterFunc(time.Until(deadline), func() {
+		s.recoverOnDeadline(uid)
+	})
+	log.Info("experiment will be recovered on deadline", zap.String("uid", uid), zap.Time("deadline", deadline))
+}
+
+func (s *Server) stopDeadlineTimer(uid string) {
+	s.deadlineLock.Lock()
+	defer s.deadlineLock.Unlock()
+
+	if timer, ok := s.deadlineTimers[uid]; ok {
+		timer.Stop()
+		delete(s.deadlineTimers, uid)
+	}
+}
+
+func (s *Server) recoverOnDeadline(uid string) {
+	exp, err := s.expStore.FindByUid(context.Background(), uid)
+	if err != nil {
+		log.Error("failed to find experiment", zap.String("uid", uid), zap.Error(err))
+		return
+	}
+
+	// the experiment may be recovered manually or by another chaosd process
+	if exp.Status != core.Success {
+		return
+	}
+	// the deadline is put off when the experiment is resumed
+	if exp.Deadline != nil && time.Until(*exp.Deadline) > 0 {
+		return
+	}
+
+	log.Info("recovering experiment since its deadline is reached", zap.String("uid", uid))
+	if err := s.RecoverAttack(uid); err != nil {
+		log.Error("failed to recover experiment on deadline", zap.String("uid", uid), zap.Error(err))
+	}
+}
+
+// WaitForDeadline blocks until the deadline of the experiment and then recovers it, the probes of
+// the experiment are checked while waiting. If the experiment has no deadline, it blocks until the experiment
+// is not active. It is used by the detached chaosd process which is started by an attack in command mode.
+func (s *Server) WaitForDeadline(uid string) error {
+	exp, err := s.expStore.FindByUid(context.Background(), uid)
+	if err != nil {
+		return perr.WithStack(err)
+	}
+	options, err := exp.GetRequestCommand()
+	if err != nil {
+		return err
+	}
+
+	var probesDone <-chan struct{}
+	if len(options.GetProbes()) > 0 {
+		probesDone = s.watchProbes(uid, options.GetProbes(), 0)
+	}
+	if exp.Deadline == nil {
+		if probesDone == nil {
+			return perr.Errorf("experiment %s has no deadline", uid)
+		}
+		<-probesDone
+		return nil
+	}
+
+	for {
+		exp, err := s.expStore.FindByUid(context.Background(), uid)
+		if err != nil {
+			return perr.WithStack(err)
+		}
+
+		if exp.Deadline == nil {
+			return perr.Errorf("experiment %s has no deadline", uid)
+		}
+
+		// the deadline may be put off while waiting, check it again after sleeping
+		wait := time.Until(*exp.Deadline)
+		if wait <= 0 {
+			break
+		}
+		time.Sleep(wait)
+	}
+
+	s.recoverOnDeadline(uid)
+	return nil
+}
+
+// RestoreDeadlines recovers the experiments whose deadline has been reached
+// and starts timers for the others. It is called when chaosd server starts,
+// so that the deadlines survive a restart of chaosd.
+func (s *Server) RestoreDeadlines() error {
+	exps, err := s.expStore.ListByStatus(context.Background(), core.Success)
+	if err != nil {
+		return perr.WithStack(err)
+	}
+
+	for _, exp := range exps {
+		if exp.Deadline == nil {
+			continue
+		}
+		s.startDeadlineTimer(exp.Uid, *exp.Deadline)
+	}
+	return s.restoreProbes(exps)
+}
+
+// restoreProbes checks the probes of the experiments launched by chaosd server again after it restarts,
+// the probes of the experiments launched in command mode are checked by their own processes.
+func (s *Server) restoreProbes(running []*core.Experiment) error {
+	paused, err := s.expStore.ListByStatus(context.Background(), core.Paused)
+	if err != nil {
+		return perr.WithStack(err)
+	}
+
+	for _, exp := range append(running, paused...) {
+		if exp.LaunchMode != core.ServerMode {
+			continue
+		}
+		options, err := exp.GetRequestCommand()
+		if err != nil {
+			log.Error("failed to restore the probes of experiment", zap.String("uid", exp.Uid), zap.Error(err))
+			continue
+		}
+		if len(options.Cron()) > 0 || len(options.GetProbes()) == 0 {
+			continue
+		}
+		s.watchProbes(exp.Uid, options.GetProbes(), 0)
+	}
+	return nil
+}
+
+// deadlineHelperArgs returns the arguments of the helper recovering the experiment on its deadline, the global flags
+// of the current command are passed to it, so that it logs the same way as the command.
+func deadlineHelperArgs(uid string) []string {
+	return append([]string{"recover", uid, "--wait"}, utils.GlobalFlags...)
+}
+
+func startDeadlineHelper(uid string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return perr.WithStack(err)
+	}
+
+	cmd := exec.Command(executable, deadlineHelperArgs(uid)...) // #nosec
+	// run the helper in a new session, so that it will not exit with the current command
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		return perr.WithStack(err)
+	}
+
+	log.Info("Start deadline helper process successfully", zap.String("uid", uid), zap.Int("Pid", cmd.Process.Pid))
+	return perr.WithStack(cmd.Process.Release())
+}
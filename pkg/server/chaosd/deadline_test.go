@@ -0,0 +1,76 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+	"github.com/chaos-mesh/chaosd/pkg/utils"
+)
+
+func Test_getExperimentDeadline(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		config   core.SchedulerConfig
+		deadline bool
+	}{
+		{
+			name:     "no duration",
+			config:   core.SchedulerConfig{},
+			deadline: false,
+		},
+		{
+			name:     "with duration",
+			config:   core.SchedulerConfig{Duration: "10m"},
+			deadline: true,
+		},
+		{
+			name:     "scheduled attack",
+			config:   core.SchedulerConfig{Schedule: "@every 1h", Duration: "10m"},
+			deadline: false,
+		},
+		{
+			name:     "duration of network flood",
+			config:   core.SchedulerConfig{Duration: "99999999"},
+			deadline: false,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			options := core.NewStressCommand()
+			options.SchedulerConfig = test.config
+
+			deadline := getExperimentDeadline(options)
+			if !test.deadline {
+				assert.Nil(t, deadline)
+				return
+			}
+			assert.NotNil(t, deadline)
+			assert.WithinDuration(t, time.Now().Add(10*time.Minute), *deadline, time.Minute)
+		})
+	}
+}
+
+func Test_deadlineHelperArgs(t *testing.T) {
+	flags := utils.GlobalFlags
+	defer func() { utils.GlobalFlags = flags }()
+
+	utils.GlobalFlags = nil
+	assert.Equal(t, []string{"recover", "uid", "--wait"}, deadlineHelperArgs("uid"))
+	utils.GlobalFlags = []string{"--log-level=debug"}
+	assert.Equal(t, []string{"recover", "uid", "--wait", "--log-level=debug"}, deadlineHelperArgs("uid"))
+}
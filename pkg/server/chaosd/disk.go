@@ -17,6 +17,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/pingcap/log"
@@ -95,6 +96,34 @@ func ApplyDiskAttack(options core.AttackConfig, env Environment) error {
 	return nil
 }
 
+func (diskAttack) Plan(options core.AttackConfig, _ Environment) ([]string, error) {
+	return planDiskAttack(options)
+}
+
+func planDiskAttack(options core.AttackConfig) ([]string, error) {
+	attackConf, ok := options.(*core.DiskAttackConfig)
+	if !ok {
+		return nil, fmt.Errorf("AttackConfig -> *DiskAttackConfig meet error")
+	}
+
+	var steps []string
+	if attackConf.Action == core.DiskFillAction && attackConf.FAllocateOption != nil {
+		name, args := core.FAllocateCommand.GetCmdArgs(*attackConf.FAllocateOption)
+		steps = append(steps, fmt.Sprintf("run: %s %s", name, strings.Join(args, " ")))
+	} else if attackConf.DdOptions != nil {
+		for _, ddOpt := range *attackConf.DdOptions {
+			name, args := core.DdCommand.GetCmdArgs(ddOpt)
+			steps = append(steps, fmt.Sprintf("run: %s %s", name, strings.Join(args, " ")))
+		}
+	}
+
+	switch attackConf.Action {
+	case core.DiskFillAction, core.DiskWritePayloadAction:
+		steps = append(steps, fmt.Sprintf("remove %s when recovering", attackConf.Path))
+	}
+	return steps, nil
+}
+
 func (diskAttack) Recover(exp core.Experiment, env Environment) error {
 	attackConfig, err := exp.GetRequestCommand()
 	if err != nil {
@@ -60,6 +60,10 @@ func NewOutputHandler(
 	}
 }
 
+func (diskServerAttack) Plan(options core.AttackConfig, _ Environment) ([]string, error) {
+	return planDiskAttack(options)
+}
+
 func getPoolSize(attackConf *core.DiskAttackConfig) int {
 	poolSize := 1
 	if attackConf.DdOptions != nil && len(*attackConf.DdOptions) > 0 {
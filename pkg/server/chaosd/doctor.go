@@ -0,0 +1,350 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/chaos-mesh/chaos-mesh/pkg/chaosdaemon/pb"
+	"github.com/pingcap/log"
+	perr "github.com/pkg/errors"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+)
+
+const (
+	IPSetRuleType    = "ipset"
+	IptablesRuleType = "iptables"
+	TCRuleType       = "tc"
+)
+
+const (
+	// NetworkRuleStale means the rule belongs to an experiment which is not active anymore.
+	NetworkRuleStale = "stale"
+	// NetworkRuleMissing means the rule belongs to an active experiment but it is not applied on the host.
+	NetworkRuleMissing = "missing"
+)
+
+// NetworkRuleIssue describes a network rule whose state in the DB does not match the host.
+type NetworkRuleIssue struct {
+	Type       string `json:"type"`
+	Name       string `json:"name"`
+	Experiment string `json:"experiment"`
+	// Status is the status of the experiment, it is empty if the experiment is not found.
+	Status  string `json:"status"`
+	Problem string `json:"problem"`
+	Fixed   bool   `json:"fixed"`
+}
+
+// liveNetworkRules is the network rules applied on the host.
+type liveNetworkRules struct {
+	ipsets map[string]bool
+	chains map[string]bool
+	// devices which have tc qdiscs of chaos
+	devices map[string]bool
+}
+
+// isNetworkRuleActive returns true if the rules of the experiment in the status are kept in the DB.
+// The rules of a paused experiment are kept, but they are not applied on the host until it is resumed.
+func isNetworkRuleActive(status string) bool {
+	return status == core.Success || status == core.Scheduled || status == core.Paused
+}
+
+// DiagnoseNetworkRules compares the ipset, iptables and tc rules in the DB with the rules on the host
+// and the status of the experiments. If fix is true, the rules of inactive experiments will be removed
+// and the rules of active experiments will be applied again.
+// Only the rules of the host are diagnosed, the ones in the network namespaces of containers (the rules with a target)
+// are skipped, because the namespaces may be gone with the containers and the rules are removed with them.
+func (s *Server) DiagnoseNetworkRules(fix bool) ([]*NetworkRuleIssue, error) {
+	// the rules are not changed by the attacks while they are diagnosed and fixed
+	s.networkLock.Lock()
+	defer s.networkLock.Unlock()
+
+	ctx := context.Background()
+	ipsets, chains, tcs, err := s.listHostNetworkRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make(map[string]string)
+	devices := make(map[string]bool)
+	for _, uid := range networkRuleExperiments(ipsets, chains, tcs) {
+		exp, err := s.expStore.FindByUid(ctx, uid)
+		if err != nil {
+			if perr.Is(err, gorm.ErrRecordNotFound) {
+				statuses[uid] = ""
+				continue
+			}
+			return nil, perr.WithStack(err)
+		}
+		statuses[uid] = exp.Status
+	}
+	for _, tc := range tcs {
+		devices[tc.Device] = true
+	}
+
+	live, err := readLiveNetworkRules(devices)
+	if err != nil {
+		return nil, err
+	}
+
+	issues := diagnoseNetworkRules(ipsets, chains, tcs, statuses, live)
+	if !fix || len(issues) == 0 {
+		return issues, nil
+	}
+
+	if err := s.fixNetworkRules(issues, statuses, devices); err != nil {
+		return issues, err
+	}
+	for _, issue := range issues {
+		issue.Fixed = true
+	}
+	return issues, nil
+}
+
+// listHostNetworkRules returns the ipset, iptables and tc rules applied in the network namespace of the host.
+func (s *Server) listHostNetworkRules(ctx context.Context) ([]*core.IPSetRule, []*core.IptablesRule, []*core.TCRule, error) {
+	ipsets, err := s.ipsetRule.List(ctx)
+	if err != nil {
+		return nil, nil, nil, perr.WithStack(err)
+	}
+	chains, err := s.iptablesRule.List(ctx)
+	if err != nil {
+		return nil, nil, nil, perr.WithStack(err)
+	}
+	tcs, err := s.tcRule.List(ctx)
+	if err != nil {
+		return nil, nil, nil, perr.WithStack(err)
+	}
+	return core.IPSetRuleList(ipsets).OfTarget(""), core.IptablesRuleList(chains).OfTarget(""), core.TCRuleList(tcs).OfTarget(""), nil
+}
+
+func networkRuleExperiments(ipsets []*core.IPSetRule, chains []*core.IptablesRule, tcs []*core.TCRule) []string {
+	seen := make(map[string]bool)
+	var uids []string
+	add := func(uid string) {
+		if !seen[uid] {
+			seen[uid] = true
+			uids = append(uids, uid)
+		}
+	}
+	for _, rule := range ipsets {
+		add(rule.Experiment)
+	}
+	for _, rule := range chains {
+		add(rule.Experiment)
+	}
+	for _, rule := range tcs {
+		add(rule.Experiment)
+	}
+	return uids
+}
+
+func diagnoseNetworkRules(
+	ipsets []*core.IPSetRule,
+	chains []*core.IptablesRule,
+	tcs []*core.TCRule,
+	statuses map[string]string,
+	live *liveNetworkRules,
+) []*NetworkRuleIssue {
+	var issues []*NetworkRuleIssue
+	check := func(ruleType, name, experiment string, applied bool) {
+		status := statuses[experiment]
+		issue := &NetworkRuleIssue{
+			Type:       ruleType,
+			Name:       name,
+			Experiment: experiment,
+			Status:     status,
+		}
+		switch {
+		case !isNetworkRuleActive(status):
+			issue.Problem = NetworkRuleStale
+		case status == core.Paused:
+			// the fault of a paused experiment is lifted on purpose
+			return
+		case !applied:
+			issue.Problem = NetworkRuleMissing
+		default:
+			return
+		}
+		issues = append(issues, issue)
+	}
+
+	for _, rule := range ipsets {
+		check(IPSetRuleType, rule.Name, rule.Experiment, live.ipsets[rule.Name])
+	}
+	for _, rule := range chains {
+		check(IptablesRuleType, rule.Name, rule.Experiment, live.chains[rule.Name])
+	}
+	for _, rule := range tcs {
+		check(TCRuleType, fmt.Sprintf("%s/%s", rule.Device, rule.Type), rule.Experiment, live.devices[rule.Device])
+	}
+	return issues
+}
+
+// fixNetworkRules removes the stale rules and applies the rules of the active experiments again,
+// the caller must hold networkLock.
+func (s *Server) fixNetworkRules(issues []*NetworkRuleIssue, statuses map[string]string, devices map[string]bool) error {
+	ctx := context.Background()
+
+	var staleIPSets []string
+	staleExps := make(map[string]bool)
+	for _, issue := range issues {
+		if issue.Problem != NetworkRuleStale {
+			continue
+		}
+		if issue.Type == IPSetRuleType {
+			staleIPSets = append(staleIPSets, issue.Name)
+		}
+		if staleExps[issue.Experiment] {
+			continue
+		}
+		staleExps[issue.Experiment] = true
+
+		log.Info("remove network rules of inactive experiment", zap.String("uid", issue.Experiment), zap.String("status", issue.Status))
+		if err := s.ipsetRule.DeleteByExperiment(ctx, issue.Experiment); err != nil {
+			return perr.WithStack(err)
+		}
+		if err := s.iptablesRule.DeleteByExperiment(ctx, issue.Experiment); err != nil {
+			return perr.WithStack(err)
+		}
+		if err := s.tcRule.DeleteByExperiment(ctx, issue.Experiment); err != nil {
+			return perr.WithStack(err)
+		}
+	}
+
+	// apply all the rules of the host left in the DB again except the ones of the paused experiments,
+	// the rules which are not applied will be removed from the host
+	ipsets, chains, tcRules, err := s.listHostNetworkRules(ctx)
+	if err != nil {
+		return err
+	}
+	ipsets, chains, tcRules = withoutPausedRules(ipsets, chains, tcRules, statuses)
+	if len(ipsets) > 0 {
+		sets := make([]*pb.IPSet, 0, len(ipsets))
+		for _, rule := range ipsets {
+			sets = append(sets, &pb.IPSet{
+				Name:  rule.Name,
+				Cidrs: strings.Split(rule.Cidrs, ","),
+				Type:  core.NetIPSet,
+			})
+		}
+		if _, err := s.svr.FlushIPSets(ctx, &pb.IPSetsRequest{
+			Ipsets:  sets,
+			EnterNS: false,
+		}); err != nil {
+			return perr.WithStack(err)
+		}
+	}
+
+	if _, err := s.svr.SetIptablesChains(ctx, &pb.IptablesChainsRequest{
+		Chains:  core.IptablesRuleList(chains).ToChains(),
+		EnterNS: false,
+	}); err != nil {
+		return perr.WithStack(err)
+	}
+
+	if len(devices) > 0 {
+		tcs, err := core.TCRuleList(tcRules).ToTCs()
+		if err != nil {
+			return perr.WithStack(err)
+		}
+		if _, err := s.svr.SetTcs(ctx, &pb.TcsRequest{Tcs: tcs, EnterNS: false}); err != nil {
+			return perr.WithStack(err)
+		}
+	}
+
+	// ipsets can only be destroyed after the iptables rules referencing them are removed
+	for _, name := range staleIPSets {
+		cmd := exec.Command("ipset", "destroy", name)
+		if output, err := cmd.CombinedOutput(); err != nil && !strings.Contains(string(output), "does not exist") {
+			log.Warn("failed to destroy ipset", zap.String("name", name), zap.String("output", string(output)), zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+func withoutPausedRules(
+	ipsets []*core.IPSetRule,
+	chains []*core.IptablesRule,
+	tcs []*core.TCRule,
+	statuses map[string]string,
+) ([]*core.IPSetRule, []*core.IptablesRule, []*core.TCRule) {
+	var (
+		activeIPSets []*core.IPSetRule
+		activeChains []*core.IptablesRule
+		activeTCs    []*core.TCRule
+	)
+	for _, rule := range ipsets {
+		if statuses[rule.Experiment] != core.Paused {
+			activeIPSets = append(activeIPSets, rule)
+		}
+	}
+	for _, rule := range chains {
+		if statuses[rule.Experiment] != core.Paused {
+			activeChains = append(activeChains, rule)
+		}
+	}
+	for _, rule := range tcs {
+		if statuses[rule.Experiment] != core.Paused {
+			activeTCs = append(activeTCs, rule)
+		}
+	}
+	return activeIPSets, activeChains, activeTCs
+}
+
+func readLiveNetworkRules(devices map[string]bool) (*liveNetworkRules, error) {
+	live := &liveNetworkRules{
+		ipsets:  make(map[string]bool),
+		chains:  make(map[string]bool),
+		devices: make(map[string]bool),
+	}
+
+	output, err := exec.Command("ipset", "list", "-n").CombinedOutput()
+	if err != nil {
+		return nil, perr.Wrapf(err, "list ipsets: %s", string(output))
+	}
+	for _, name := range strings.Fields(string(output)) {
+		live.ipsets[name] = true
+	}
+
+	output, err = exec.Command("iptables", "-w", "-S").CombinedOutput()
+	if err != nil {
+		return nil, perr.Wrapf(err, "list iptables chains: %s", string(output))
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		if fields := strings.Fields(line); len(fields) == 2 && fields[0] == "-N" {
+			live.chains[fields[1]] = true
+		}
+	}
+
+	for device := range devices {
+		output, err = exec.Command("tc", "qdisc", "show", "dev", device).CombinedOutput()
+		if err != nil {
+			// the device may be removed
+			log.Warn("failed to show tc qdisc", zap.String("device", device), zap.String("output", string(output)), zap.Error(err))
+			continue
+		}
+		qdisc := string(output)
+		live.devices[device] = strings.Contains(qdisc, "netem") || strings.Contains(qdisc, "tbf")
+	}
+
+	return live, nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+)
+
+func TestDiagnoseNetworkRules(t *testing.T) {
+	ipsets := []*core.IPSetRule{
+		{Name: "chaos-active", Experiment: "active"},
+		{Name: "chaos-destroyed", Experiment: "destroyed"},
+		{Name: "chaos-paused", Experiment: "paused"},
+	}
+	chains := []*core.IptablesRule{
+		{Name: "CHAOS-ACTIVE", Experiment: "active"},
+		{Name: "CHAOS-ERROR", Experiment: "error"},
+	}
+	tcs := []*core.TCRule{
+		{Device: "eth0", Type: "netem", Experiment: "active"},
+		{Device: "eth1", Type: "netem", Experiment: "unknown"},
+	}
+	statuses := map[string]string{
+		"active":    core.Success,
+		"destroyed": core.Destroyed,
+		"error":     core.Error,
+		"unknown":   "",
+		"paused":    core.Paused,
+	}
+	live := &liveNetworkRules{
+		ipsets:  map[string]bool{"chaos-active": true, "chaos-destroyed": true},
+		chains:  map[string]bool{},
+		devices: map[string]bool{"eth0": true},
+	}
+
+	issues := diagnoseNetworkRules(ipsets, chains, tcs, statuses, live)
+	assert.Equal(t, []*NetworkRuleIssue{
+		{Type: IPSetRuleType, Name: "chaos-destroyed", Experiment: "destroyed", Status: core.Destroyed, Problem: NetworkRuleStale},
+		{Type: IptablesRuleType, Name: "CHAOS-ACTIVE", Experiment: "active", Status: core.Success, Problem: NetworkRuleMissing},
+		{Type: IptablesRuleType, Name: "CHAOS-ERROR", Experiment: "error", Status: core.Error, Problem: NetworkRuleStale},
+		{Type: TCRuleType, Name: "eth1/netem", Experiment: "unknown", Problem: NetworkRuleStale},
+	}, issues)
+
+	// the rules of the paused experiments are kept in the DB, but they are not applied again
+	ipsets, chains, tcs = withoutPausedRules(ipsets, chains, tcs, statuses)
+	assert.Len(t, ipsets, 2)
+	assert.Len(t, chains, 2)
+	assert.Len(t, tcs, 2)
+}
@@ -63,6 +63,44 @@ func (fileAttack) Attack(options core.AttackConfig, env Environment) (err error)
 	return nil
 }
 
+func (fileAttack) Plan(options core.AttackConfig, env Environment) ([]string, error) {
+	attack := options.(*core.FileCommand)
+
+	switch attack.Action {
+	case core.FileCreateAction:
+		if len(attack.DirName) > 0 {
+			return []string{fmt.Sprintf("run: FileTool create --dir-name %s", attack.DirName)}, nil
+		}
+		return []string{fmt.Sprintf("run: FileTool create --file-name %s", attack.FileName)}, nil
+	case core.FileModifyPrivilegeAction:
+		return []string{
+			fmt.Sprintf("run: stat -c %%a %s", attack.FileName),
+			fmt.Sprintf("run: FileTool modify --file-name %s --privilege %d", attack.FileName, attack.Privilege),
+		}, nil
+	case core.FileDeleteAction:
+		source := attack.FileName
+		if len(source) == 0 {
+			source = attack.DirName
+		}
+		// the file is not really deleted, it is renamed to the backup name
+		return []string{fmt.Sprintf("run: FileTool rename --old-name %s --new-name %s", source, getBackupName(source, env.AttackUid))}, nil
+	case core.FileRenameAction:
+		return []string{fmt.Sprintf("run: FileTool rename --old-name %s --new-name %s", attack.SourceFile, attack.DestFile)}, nil
+	case core.FileAppendAction:
+		backupName := getBackupName(attack.FileName, env.AttackUid)
+		return []string{
+			fmt.Sprintf("run: FileTool copy --file-name %s --copy-file-name %s", attack.FileName, backupName),
+			fmt.Sprintf("run: FileTool append --count %d --data %s --file-name %s", attack.Count, attack.Data, attack.FileName),
+		}, nil
+	case core.FileReplaceAction:
+		return []string{
+			fmt.Sprintf("no backup of %s, it is recovered by replacing %s with %s", attack.FileName, attack.DestStr, attack.OriginStr),
+			fmt.Sprintf("run: FileTool replace --file-name %s --origin-string %s --dest-string %s --line %d", attack.FileName, attack.OriginStr, attack.DestStr, attack.Line),
+		}, nil
+	}
+	return nil, nil
+}
+
 func (s *Server) createFile(attack *core.FileCommand, uid string) error {
 	var cmdStr string
 	if len(attack.DirName) > 0 {
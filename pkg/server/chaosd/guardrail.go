@@ -0,0 +1,222 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/pingcap/log"
+	perr "github.com/pkg/errors"
+	"github.com/shirou/gopsutil/cpu"
+	"github.com/shirou/gopsutil/disk"
+	"github.com/shirou/gopsutil/load"
+	"github.com/shirou/gopsutil/mem"
+	"go.uber.org/zap"
+
+	"github.com/chaos-mesh/chaosd/pkg/config"
+	"github.com/chaos-mesh/chaosd/pkg/core"
+	"github.com/chaos-mesh/chaosd/pkg/utils"
+)
+
+const (
+	cpuResource    = "cpu"
+	memoryResource = "memory"
+	diskResource   = "disk"
+)
+
+// hostMetrics is a sample of the host resources watched by the guardrails
+type hostMetrics struct {
+	CPUPercent  float64
+	LoadAverage float64
+	FreeMemory  uint64
+	// FreeDisk is the free space of the mount points
+	FreeDisk map[string]uint64
+}
+
+// guardrailViolation is a threshold of the guardrails crossed by the host
+type guardrailViolation struct {
+	Resource string
+	Reason   string
+}
+
+// WatchGuardrails starts the watchdog which samples the host metrics periodically, and aborts the experiments
+// exhausting a resource once its threshold is crossed. It does nothing if no guardrail is configured.
+func (s *Server) WatchGuardrails() {
+	guardrails := s.conf.Guardrails
+	if !guardrails.Enabled() {
+		return
+	}
+
+	log.Info("watching the host guardrails", zap.Duration("interval", guardrails.Interval),
+		zap.Float64("max-cpu-percent", guardrails.MaxCPUPercent), zap.String("min-free-memory", guardrails.MinFreeMemory),
+		zap.Any("min-free-disk", guardrails.MinFreeDisk), zap.Float64("max-load", guardrails.MaxLoadAverage))
+	go func() {
+		ticker := time.NewTicker(guardrails.Interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			metrics, err := sampleHostMetrics(guardrails)
+			if err != nil {
+				log.Error("failed to sample the host metrics", zap.Error(err))
+				continue
+			}
+			violations, err := checkGuardrails(guardrails, metrics)
+			if err != nil {
+				log.Error("failed to check the guardrails", zap.Error(err))
+				continue
+			}
+			s.enforceGuardrails(violations)
+		}
+	}()
+}
+
+func sampleHostMetrics(guardrails config.Guardrails) (*hostMetrics, error) {
+	metrics := &hostMetrics{FreeDisk: make(map[string]uint64)}
+
+	if guardrails.MaxCPUPercent > 0 {
+		// the usage since the last call
+		percents, err := cpu.Percent(0, false)
+		if err != nil {
+			return nil, perr.WithStack(err)
+		}
+		if len(percents) > 0 {
+			metrics.CPUPercent = percents[0]
+		}
+	}
+
+	if guardrails.MaxLoadAverage > 0 {
+		avg, err := load.Avg()
+		if err != nil {
+			return nil, perr.WithStack(err)
+		}
+		metrics.LoadAverage = avg.Load1
+	}
+
+	if len(guardrails.MinFreeMemory) > 0 {
+		vm, err := mem.VirtualMemory()
+		if err != nil {
+			return nil, perr.WithStack(err)
+		}
+		metrics.FreeMemory = vm.Available
+	}
+
+	for mount := range guardrails.MinFreeDisk {
+		usage, err := disk.Usage(mount)
+		if err != nil {
+			return nil, perr.WithMessagef(err, "failed to get the usage of %s", mount)
+		}
+		metrics.FreeDisk[mount] = usage.Free
+	}
+	return metrics, nil
+}
+
+// checkGuardrails returns the thresholds crossed by the sample of the host metrics.
+func checkGuardrails(guardrails config.Guardrails, metrics *hostMetrics) ([]guardrailViolation, error) {
+	var violations []guardrailViolation
+
+	if guardrails.MaxCPUPercent > 0 && metrics.CPUPercent > guardrails.MaxCPUPercent {
+		violations = append(violations, guardrailViolation{
+			Resource: cpuResource,
+			Reason:   fmt.Sprintf("cpu usage %.1f%% exceeds %v%%", metrics.CPUPercent, guardrails.MaxCPUPercent),
+		})
+	}
+
+	if guardrails.MaxLoadAverage > 0 && metrics.LoadAverage > guardrails.MaxLoadAverage {
+		violations = append(violations, guardrailViolation{
+			Resource: cpuResource,
+			Reason:   fmt.Sprintf("load average %.2f exceeds %v", metrics.LoadAverage, guardrails.MaxLoadAverage),
+		})
+	}
+
+	if len(guardrails.MinFreeMemory) > 0 {
+		minFree, err := utils.ParseUnit(guardrails.MinFreeMemory)
+		if err != nil {
+			return nil, perr.WithStack(err)
+		}
+		if metrics.FreeMemory < minFree {
+			violations = append(violations, guardrailViolation{
+				Resource: memoryResource,
+				Reason: fmt.Sprintf("available memory %s is less than %s",
+					humanize.IBytes(metrics.FreeMemory), humanize.IBytes(minFree)),
+			})
+		}
+	}
+
+	for mount, size := range guardrails.MinFreeDisk {
+		minFree, err := utils.ParseUnit(size)
+		if err != nil {
+			return nil, perr.WithStack(err)
+		}
+		free, ok := metrics.FreeDisk[mount]
+		if ok && free < minFree {
+			violations = append(violations, guardrailViolation{
+				Resource: diskResource,
+				Reason: fmt.Sprintf("free disk %s of %s is less than %s",
+					humanize.IBytes(free), mount, humanize.IBytes(minFree)),
+			})
+		}
+	}
+	return violations, nil
+}
+
+// enforceGuardrails aborts the active experiments which exhaust the resources of the violations.
+func (s *Server) enforceGuardrails(violations []guardrailViolation) {
+	if len(violations) == 0 {
+		return
+	}
+
+	exps, err := s.listActiveExperiments(RecoverFilter{})
+	if err != nil {
+		log.Error("failed to list the active experiments", zap.Error(err))
+		return
+	}
+
+	aborted := make(map[string]bool)
+	for _, violation := range violations {
+		offended := false
+		for _, exp := range exps {
+			// the fault is lifted while the experiment is paused
+			if exp.Status == core.Paused || !exhaustsResource(exp, violation.Resource) {
+				continue
+			}
+			offended = true
+			if aborted[exp.Uid] {
+				continue
+			}
+			aborted[exp.Uid] = true
+			log.Warn("guardrail is crossed, aborting the experiment", zap.String("uid", exp.Uid),
+				zap.String("kind", exp.Kind), zap.String("reason", violation.Reason))
+			s.abortAttack(exp.Uid, "guardrail is crossed, "+violation.Reason)
+		}
+		if !offended {
+			log.Warn("guardrail is crossed, but no experiment exhausts the resource",
+				zap.String("resource", violation.Resource), zap.String("reason", violation.Reason))
+		}
+	}
+}
+
+// exhaustsResource returns true if the experiment consumes the resource of the host.
+func exhaustsResource(exp *core.Experiment, resource string) bool {
+	switch resource {
+	case cpuResource:
+		return exp.Kind == core.StressAttack && exp.Action == core.StressCPUAction
+	case memoryResource:
+		return exp.Kind == core.StressAttack && exp.Action == core.StressMemAction
+	case diskResource:
+		return (exp.Kind == core.DiskAttack || exp.Kind == core.DiskServerAttack) &&
+			(exp.Action == core.DiskFillAction || exp.Action == core.DiskWritePayloadAction)
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chaos-mesh/chaosd/pkg/config"
+	"github.com/chaos-mesh/chaosd/pkg/core"
+)
+
+func TestCheckGuardrails(t *testing.T) {
+	guardrails := config.Guardrails{
+		MaxCPUPercent:  90,
+		MinFreeMemory:  "512MB",
+		MinFreeDisk:    map[string]string{"/": "1GB", "/data": "10GB"},
+		MaxLoadAverage: 8,
+	}
+
+	violations, err := checkGuardrails(guardrails, &hostMetrics{
+		CPUPercent:  50,
+		LoadAverage: 1,
+		FreeMemory:  1 << 30,
+		FreeDisk:    map[string]uint64{"/": 2 << 30, "/data": 20 << 30},
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, violations)
+
+	violations, err = checkGuardrails(guardrails, &hostMetrics{
+		CPUPercent:  95,
+		LoadAverage: 1,
+		FreeMemory:  100 << 20,
+		FreeDisk:    map[string]uint64{"/": 2 << 30, "/data": 1 << 30},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, violations, 3)
+	resources := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		resources = append(resources, violation.Resource)
+	}
+	assert.Equal(t, []string{cpuResource, memoryResource, diskResource}, resources)
+	assert.Equal(t, "cpu usage 95.0% exceeds 90%", violations[0].Reason)
+	assert.Contains(t, violations[2].Reason, "/data")
+
+	_, err = checkGuardrails(config.Guardrails{MinFreeMemory: "lots"}, &hostMetrics{})
+	assert.Error(t, err)
+}
+
+func TestExhaustsResource(t *testing.T) {
+	cases := []struct {
+		kind, action, resource string
+		exhausts               bool
+	}{
+		{core.StressAttack, core.StressCPUAction, cpuResource, true},
+		{core.StressAttack, core.StressMemAction, cpuResource, false},
+		{core.StressAttack, core.StressMemAction, memoryResource, true},
+		{core.DiskAttack, core.DiskFillAction, diskResource, true},
+		{core.DiskServerAttack, core.DiskWritePayloadAction, diskResource, true},
+		{core.DiskAttack, core.DiskReadPayloadAction, diskResource, false},
+		{core.NetworkAttack, "delay", cpuResource, false},
+	}
+	for _, c := range cases {
+		exp := &core.Experiment{Kind: c.kind, Action: c.action}
+		assert.Equal(t, c.exhausts, exhaustsResource(exp, c.resource), "%s %s %s", c.kind, c.action, c.resource)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/log"
+	perr "github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+)
+
+// HaltResult is the halt state of chaosd with the experiments recovered by Halt.
+type HaltResult struct {
+	*core.HaltState
+	Recovered []RecoverResult `json:"recovered,omitempty"`
+}
+
+// Halt is the emergency stop of chaosd. It puts chaosd into the halted state first, so that no new attack
+// can be executed, then removes all the cron jobs and recovers all the active experiments.
+// The halted state is persisted and is kept until Unhalt is called.
+func (s *Server) Halt(reason string) ([]RecoverResult, error) {
+	// the experiments are created under policyLock, so every experiment created after this point sees the halted state,
+	// and the ones being injected are recovered by ExecuteAttack when the injection is done
+	s.policyLock.Lock()
+	err := s.haltState.Set(context.Background(), &core.HaltState{Halted: true, Reason: reason})
+	s.policyLock.Unlock()
+	if err != nil {
+		return nil, perr.WithStack(err)
+	}
+	log.Warn("chaosd is halted", zap.String("reason", reason))
+
+	s.Cron.RemoveAll()
+	return s.RecoverExperiments(RecoverFilter{}, DefaultRecoverWorkers)
+}
+
+// Unhalt allows new attacks to be executed again.
+func (s *Server) Unhalt() error {
+	if err := s.haltState.Set(context.Background(), &core.HaltState{Halted: false}); err != nil {
+		return perr.WithStack(err)
+	}
+	log.Info("chaosd is unhalted")
+	return nil
+}
+
+// GetHaltState returns whether chaosd is halted.
+func (s *Server) GetHaltState() (*core.HaltState, error) {
+	state, err := s.haltState.Get(context.Background())
+	if err != nil {
+		return nil, perr.WithStack(err)
+	}
+	return state, nil
+}
+
+// checkHalted returns an error of core.ErrHalted if chaosd is halted. The state is read from the DB every time,
+// because chaosd may be halted by another process, such as `chaosd halt` when chaosd server is not running.
+func (s *Server) checkHalted() error {
+	state, err := s.GetHaltState()
+	if err != nil {
+		return err
+	}
+	if !state.Halted {
+		return nil
+	}
+	if len(state.Reason) > 0 {
+		return core.ErrHalted.New("chaosd has been halted since %s because %s, run `chaosd unhalt` to allow new attacks",
+			state.UpdatedAt.Format(time.RFC3339), state.Reason)
+	}
+	return core.ErrHalted.New("chaosd has been halted since %s, run `chaosd unhalt` to allow new attacks",
+		state.UpdatedAt.Format(time.RFC3339))
+}
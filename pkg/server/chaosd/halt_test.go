@@ -0,0 +1,122 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+	"github.com/chaos-mesh/chaosd/pkg/scheduler"
+)
+
+type fakeHaltStateStore struct {
+	state core.HaltState
+}
+
+func (s *fakeHaltStateStore) Get(context.Context) (*core.HaltState, error) {
+	state := s.state
+	return &state, nil
+}
+
+func (s *fakeHaltStateStore) Set(_ context.Context, state *core.HaltState) error {
+	s.state = *state
+	s.state.UpdatedAt = time.Now()
+	return nil
+}
+
+func TestServer_Halt(t *testing.T) {
+	attack := &countingAttack{}
+	RegisterAttackKind(AttackKind{
+		Kind:      "halt-test",
+		NewConfig: func() core.AttackConfig { return &core.ProcessCommand{} },
+		Attack:    attack,
+	})
+
+	store := &fakeExpStore{exps: []*core.Experiment{
+		{Uid: "exp-0", Kind: "halt-test", Status: core.Success, RecoverCommand: "{}"},
+		{Uid: "exp-1", Kind: "halt-test", Status: core.Destroyed},
+	}}
+	s := &Server{
+		expStore:       store,
+		Cron:           scheduler.NewScheduler(nil, store),
+		haltState:      &fakeHaltStateStore{},
+		deadlineTimers: make(map[string]*time.Timer),
+	}
+	assert.NoError(t, s.checkHalted())
+
+	results, err := s.Halt("incident")
+	assert.NoError(t, err)
+	assert.Equal(t, []RecoverResult{{UID: "exp-0", Kind: "halt-test"}}, results)
+	assert.Equal(t, core.Destroyed, store.exps[0].Status)
+
+	state, err := s.GetHaltState()
+	assert.NoError(t, err)
+	assert.True(t, state.Halted)
+	assert.Equal(t, "incident", state.Reason)
+
+	options := core.NewProcessCommand()
+	_, err = s.ExecuteAttack(attack, options, core.ServerMode)
+	assert.True(t, errorx.IsOfType(err, core.ErrHalted))
+	assert.Contains(t, err.Error(), "incident")
+	_, err = s.scheduledAttackFunc(attack, store.exps[0], s.newEnvironment("exp-0"))("run-0")
+	assert.Error(t, err)
+
+	assert.NoError(t, s.Unhalt())
+	assert.NoError(t, s.checkHalted())
+	_, err = s.scheduledAttackFunc(attack, store.exps[0], s.newEnvironment("exp-0"))("run-0")
+	assert.NoError(t, err)
+}
+
+// haltingAttack halts chaosd while the attack is being injected
+type haltingAttack struct {
+	countingAttack
+	s *Server
+}
+
+func (a *haltingAttack) Attack(core.AttackConfig, Environment) error {
+	_, err := a.s.Halt("incident")
+	return err
+}
+
+func TestServer_HaltWhileInjecting(t *testing.T) {
+	store := &fakeExpStore{}
+	s := &Server{
+		expStore:       store,
+		Cron:           scheduler.NewScheduler(nil, store),
+		History:        &fakeHistoryStore{},
+		haltState:      &fakeHaltStateStore{},
+		deadlineTimers: make(map[string]*time.Timer),
+	}
+	attack := &haltingAttack{s: s}
+	RegisterAttackKind(AttackKind{
+		Kind:      "halt-injecting-test",
+		NewConfig: func() core.AttackConfig { return &core.ProcessCommand{} },
+		Attack:    attack,
+	})
+
+	options := core.NewProcessCommand()
+	options.Kind = "halt-injecting-test"
+	uid, err := s.ExecuteAttack(attack, options, core.ServerMode)
+	assert.True(t, errorx.IsOfType(err, core.ErrHalted))
+	// Halt skips the experiment being injected, it is recovered when the injection is done
+	assert.Equal(t, []string{uid}, attack.recovered)
+	exp, err := store.FindByUid(context.Background(), uid)
+	assert.NoError(t, err)
+	assert.Equal(t, core.Destroyed, exp.Status)
+}
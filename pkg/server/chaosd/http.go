@@ -64,7 +64,7 @@ func (attackHTTP) Attack(options core.AttackConfig, _ Environment) error {
 	}
 
 	config, err := json.Marshal(&attackConf.Config)
-	attackConf.Logger.Info(string(config))
+	attackConf.GetLogger().Info(string(config))
 	if err != nil {
 		return errors.Wrap(err, "applying HTTP attack")
 	}
@@ -95,7 +95,7 @@ func (attackHTTP) Attack(options core.AttackConfig, _ Environment) error {
 	if err != nil {
 		return errors.Wrapf(err, "cannot read resp body")
 	}
-	attackConf.Logger.Info(string(by))
+	attackConf.GetLogger().Info(string(by))
 
 	attackConf.ProxyPID = cmd.Process.Pid
 	// In linux, a child process will become orphan process when a parent process dies.
@@ -117,6 +117,10 @@ func (attackHTTP) Recover(exp core.Experiment, _ Environment) error {
 	if !ok {
 		return errors.Errorf("AttackConfig -> *HTTPAttackConfig meet error")
 	}
+	// the requests have been sent, there is nothing to recover
+	if attack.Action == core.HTTPRequestAction {
+		return nil
+	}
 
 	proc, err := process.NewProcess(int32(attack.ProxyPID))
 	if err != nil {
@@ -133,12 +137,12 @@ func (attackHTTP) Recover(exp core.Experiment, _ Environment) error {
 	}
 
 	if !strings.Contains(procName, "tproxy") {
-		attack.Logger.Info("the process %s:%d is not chaos-tproxy, please check and clear it manually\n", procName, attack.ProxyPID)
+		attack.GetLogger().Info("the process %s:%d is not chaos-tproxy, please check and clear it manually\n", procName, attack.ProxyPID)
 		return nil
 	}
 
 	if err := proc.Terminate(); err != nil {
-		attack.Logger.Info("the chaos-tproxy process kill failed with error: %s\n", err.Error())
+		attack.GetLogger().Info("the chaos-tproxy process kill failed with error: %s\n", err.Error())
 		return nil
 	}
 	return nil
@@ -174,7 +178,7 @@ func attackHTTPRequest(attackConf *core.HTTPAttackConfig) error {
 			if err != nil {
 				return errors.Wrap(err, "read response body")
 			}
-			attackConf.Logger.Info("response body: " + string(data))
+			attackConf.GetLogger().Info("response body: " + string(data))
 		}
 	} else {
 		for i := 0; i < attackConf.Count; i++ {
@@ -187,7 +191,7 @@ func attackHTTPRequest(attackConf *core.HTTPAttackConfig) error {
 			if err != nil {
 				return errors.Wrap(err, "read response body")
 			}
-			attackConf.Logger.Info("response body: " + string(data))
+			attackConf.GetLogger().Info("response body: " + string(data))
 		}
 	}
 
@@ -0,0 +1,83 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+	"github.com/chaos-mesh/chaosd/pkg/scheduler"
+)
+
+func TestServer_ScheduleConcurrentHTTPRequests(t *testing.T) {
+	var requests int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+	defer target.Close()
+
+	body := fmt.Sprintf(`{"action":"request","url":%q,"count":1,"schedule":"@every 1h","duration":"1s","concurrency_policy":"Allow"}`,
+		target.URL)
+	options, err := attackKinds[core.HTTPAttack].HTTP.Bind(func(obj interface{}) error {
+		return json.Unmarshal([]byte(body), obj)
+	})
+	assert.NoError(t, err)
+
+	store := &fakeExpStore{}
+	runStore := &fakeRunStore{}
+	s := &Server{
+		expStore:       store,
+		ExpRun:         runStore,
+		Cron:           scheduler.NewScheduler(runStore, store),
+		History:        &fakeHistoryStore{},
+		haltState:      &fakeHaltStateStore{},
+		deadlineTimers: make(map[string]*time.Timer),
+	}
+	s.Cron.Start()
+	defer s.Cron.Stop()
+
+	uid, err := s.ExecuteAttack(HTTPAttack, options, core.ServerMode)
+	assert.NoError(t, err)
+
+	// the second run starts before the first one is recovered
+	first, err := s.TriggerSchedule(uid)
+	assert.NoError(t, err)
+	time.Sleep(500 * time.Millisecond)
+	second, err := s.TriggerSchedule(uid)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	schedule, err := s.DescribeSchedule(uid)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{first.UID, second.UID}, schedule.PendingRuns)
+
+	// each run is recovered after its own duration
+	assert.Eventually(t, func() bool {
+		return runStore.status(first.UID) == core.RunRecovered
+	}, 2*time.Second, 50*time.Millisecond)
+	assert.Equal(t, core.RunSuccess, runStore.status(second.UID))
+	assert.Eventually(t, func() bool {
+		return runStore.status(second.UID) == core.RunRecovered
+	}, 2*time.Second, 50*time.Millisecond)
+	schedule, err = s.DescribeSchedule(uid)
+	assert.NoError(t, err)
+	assert.Empty(t, schedule.PendingRuns)
+}
@@ -93,6 +93,31 @@ func (j jvmAttack) Attack(options core.AttackConfig, env Environment) (err error
 	return nil
 }
 
+func (j jvmAttack) Plan(options core.AttackConfig, _ Environment) ([]string, error) {
+	attack := options.(*core.JVMCommand)
+
+	ruleData := attack.RuleData
+	if len(ruleData) == 0 && len(attack.RuleFile) > 0 {
+		data, err := ioutil.ReadFile(attack.RuleFile)
+		if err != nil {
+			return nil, err
+		}
+		ruleData = string(data)
+	} else if len(ruleData) == 0 {
+		var err error
+		ruleData, err = generateRuleData(attack)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return []string{
+		fmt.Sprintf("run: %s", fmt.Sprintf(bmInstallCommand, attack.Port, attack.Pid)),
+		fmt.Sprintf("run: %s", fmt.Sprintf(bmSubmitCommand, attack.Port, "b", fmt.Sprintf("%s/lib/byteman-helper.jar", os.Getenv("BYTEMAN_HOME")))),
+		fmt.Sprintf("submit byteman rule:\n%s", ruleData),
+	}, nil
+}
+
 func (j jvmAttack) generateRuleFile(attack *core.JVMCommand) (string, error) {
 	var err error
 	if len(attack.RuleData) > 0 {
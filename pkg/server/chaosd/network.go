@@ -37,6 +37,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/chaos-mesh/chaosd/pkg/core"
+	"github.com/chaos-mesh/chaosd/pkg/crclient"
 )
 
 type networkAttack struct{}
@@ -67,6 +68,13 @@ func (networkAttack) Attack(options core.AttackConfig, env Environment) (err err
 		return env.Chaos.applyPortOccupied(attack)
 
 	case core.NetworkDelayAction, core.NetworkLossAction, core.NetworkCorruptAction, core.NetworkDuplicateAction, core.NetworkBandwidthAction, core.NetworkPartitionAction:
+		env.Chaos.networkLock.Lock()
+		defer env.Chaos.networkLock.Unlock()
+
+		if err = env.Chaos.checkNetNSTarget(attack); err != nil {
+			return err
+		}
+
 		if attack.NeedApplyIPSet() {
 			ipsetName, err = env.Chaos.applyIPSet(attack, env.AttackUid)
 			if err != nil {
@@ -88,7 +96,7 @@ func (networkAttack) Attack(options core.AttackConfig, env Environment) (err err
 			return perrors.WithStack(err)
 		}
 
-		NICDownCommand := fmt.Sprintf("ifconfig %s down", attack.Device)
+		NICDownCommand := nicDownCommand(attack)
 
 		cmd := exec.Command("bash", "-c", NICDownCommand)
 		_, err := cmd.CombinedOutput()
@@ -107,15 +115,161 @@ func (networkAttack) Attack(options core.AttackConfig, env Environment) (err err
 	return nil
 }
 
+func (networkAttack) Plan(options core.AttackConfig, env Environment) ([]string, error) {
+	attack := options.(*core.NetworkCommand)
+	var steps []string
+
+	switch attack.Action {
+	case core.NetworkDNSAction:
+		if attack.NeedApplyEtcHosts() {
+			steps = append(steps,
+				fmt.Sprintf("back up /etc/hosts to /etc/hosts.chaosd.%s", env.AttackUid),
+				fmt.Sprintf("resolve %s to %s in /etc/hosts", attack.DNSDomainName, attack.DNSIp))
+		}
+		if attack.NeedApplyDNSServer() {
+			steps = append(steps, fmt.Sprintf("set DNS server to %s", attack.DNSServer))
+		}
+
+	case core.NetworkPortOccupiedAction:
+		if len(attack.Port) > 0 {
+			steps = append(steps, fmt.Sprintf("run: PortOccupyTool -p=%s", attack.Port))
+		}
+
+	case core.NetworkDelayAction, core.NetworkLossAction, core.NetworkCorruptAction, core.NetworkDuplicateAction, core.NetworkBandwidthAction, core.NetworkPartitionAction:
+		if target := attack.NetNSTarget(); len(target) > 0 {
+			steps = append(steps, fmt.Sprintf("enter the network namespace of %s, the following rules only impact it", target))
+		}
+
+		var name string
+		if attack.NeedApplyIPSet() {
+			ipset, err := attack.ToIPSet(ipsetName(env.AttackUid))
+			if err != nil {
+				return nil, perrors.WithStack(err)
+			}
+			name = ipset.Name
+			steps = append(steps, fmt.Sprintf("create ipset %s with %s", ipset.Name, strings.Join(ipset.Cidrs, ",")))
+		}
+
+		if attack.NeedAdditionalChains() {
+			chains, err := attack.AdditionalChain(name, attack.Device, env.AttackUid)
+			if err != nil {
+				return nil, perrors.WithStack(err)
+			}
+			for _, chain := range chains {
+				steps = append(steps, fmt.Sprintf("create iptables chain %s: direction %s, ipsets [%s], target %s, protocol %s, tcp flags %s, device %s",
+					chain.Name, chain.Direction, strings.Join(chain.Ipsets, ","), chain.Target, chain.Protocol, chain.TcpFlags, chain.Device))
+			}
+		}
+
+		if attack.NeedApplyTC() {
+			tc, err := attack.ToTC(name)
+			if err != nil {
+				return nil, perrors.WithStack(err)
+			}
+			steps = append(steps, fmt.Sprintf("add tc qdisc on device %s: %s", attack.Device, tc.String()))
+		}
+
+	case core.NetworkNICDownAction:
+		steps = append(steps, fmt.Sprintf("run: %s", nicDownCommand(attack)))
+		if attack.Duration != "-1" {
+			steps = append(steps, fmt.Sprintf("bring device %s up after %s", attack.Device, attack.Duration))
+		}
+
+	case core.NetworkFloodAction:
+		steps = append(steps, fmt.Sprintf("run: %s", floodCommand(attack)))
+	}
+
+	return steps, nil
+}
+
+// Update replaces the tc of the attack on the same device when the traffic it selects is not changed,
+// the ipset and iptables of the attack are kept. Otherwise the attack is recovered and executed again.
+func (networkAttack) Update(exp core.Experiment, options core.AttackConfig, env Environment) error {
+	config, err := exp.GetRequestCommand()
+	if err != nil {
+		return err
+	}
+	old := config.(*core.NetworkCommand)
+	attack := options.(*core.NetworkCommand)
+
+	if !old.NeedApplyTC() || !attack.NeedApplyTC() || !sameNetworkTraffic(old, attack) {
+		if err := NetworkAttack.Recover(exp, env); err != nil {
+			return perrors.WithStack(err)
+		}
+		return NetworkAttack.Attack(options, env)
+	}
+
+	env.Chaos.networkLock.Lock()
+	defer env.Chaos.networkLock.Unlock()
+
+	if err := env.Chaos.checkNetNSTarget(attack); err != nil {
+		return err
+	}
+
+	var ipset string
+	if attack.NeedApplyIPSet() {
+		ipset = ipsetName(env.AttackUid)
+	}
+	if err := env.Chaos.tcRule.DeleteByExperiment(context.Background(), env.AttackUid); err != nil {
+		return perrors.WithStack(err)
+	}
+	return env.Chaos.applyTC(attack, ipset, env.AttackUid)
+}
+
+func sameNetworkTraffic(a, b *core.NetworkCommand) bool {
+	return a.Device == b.Device &&
+		a.IPAddress == b.IPAddress &&
+		a.Hostname == b.Hostname &&
+		a.IPProtocol == b.IPProtocol &&
+		a.SourcePort == b.SourcePort &&
+		a.EgressPort == b.EgressPort &&
+		a.Direction == b.Direction &&
+		a.AcceptTCPFlags == b.AcceptTCPFlags &&
+		a.NetNSTarget() == b.NetNSTarget()
+}
+
+func ipsetName(uid string) string {
+	return fmt.Sprintf("chaos-%.16s", uid)
+}
+
+// checkNetNSTarget checks the network namespace of the container or the process of the attack exists,
+// so that no rule is stored for the target which can not be entered.
+func (s *Server) checkNetNSTarget(attack *core.NetworkCommand) error {
+	target := attack.NetNSTarget()
+	if len(target) == 0 {
+		return nil
+	}
+
+	pid, err := s.crClient.GetPidFromContainerID(context.Background(), target)
+	if err != nil {
+		return perrors.Annotatef(err, "network namespace of %s not found", target)
+	}
+	if err := syscall.Kill(int(pid), 0); errors.Is(err, syscall.ESRCH) {
+		return perrors.Annotatef(crclient.ErrTargetGone, "process %d of %s has exited", pid, target)
+	}
+	log.Info("enter network namespace", zap.String("target", target), zap.Uint32("pid", pid))
+	return nil
+}
+
+func nicDownCommand(attack *core.NetworkCommand) string {
+	return fmt.Sprintf("ifconfig %s down", attack.Device)
+}
+
+func floodCommand(attack *core.NetworkCommand) string {
+	return fmt.Sprintf("iperf -u -c %s -t %s -p %s -P %d -b %s", attack.IPAddress, attack.Duration, attack.Port, attack.Parallel, attack.Rate)
+}
+
 func (s *Server) applyIPSet(attack *core.NetworkCommand, uid string) (string, error) {
-	ipset, err := attack.ToIPSet(fmt.Sprintf("chaos-%.16s", uid))
+	ipset, err := attack.ToIPSet(ipsetName(uid))
 	if err != nil {
 		return "", perrors.WithStack(err)
 	}
 
+	target := attack.NetNSTarget()
 	if _, err := s.svr.FlushIPSets(context.Background(), &pb.IPSetsRequest{
-		Ipsets:  []*pb.IPSet{ipset},
-		EnterNS: false,
+		Ipsets:      []*pb.IPSet{ipset},
+		ContainerId: target,
+		EnterNS:     len(target) > 0,
 	}); err != nil {
 		return "", perrors.WithStack(err)
 	}
@@ -124,6 +278,7 @@ func (s *Server) applyIPSet(attack *core.NetworkCommand, uid string) (string, er
 		Name:       ipset.Name,
 		Cidrs:      strings.Join(ipset.Cidrs, ","),
 		Experiment: uid,
+		Target:     target,
 	}); err != nil {
 		return "", perrors.WithStack(err)
 	}
@@ -136,7 +291,8 @@ func (s *Server) applyIptables(attack *core.NetworkCommand, ipset, uid string) e
 	if err != nil {
 		return perrors.WithStack(err)
 	}
-	chains := core.IptablesRuleList(iptables).ToChains()
+	target := attack.NetNSTarget()
+	chains := core.IptablesRuleList(iptables).OfTarget(target).ToChains()
 
 	var newChains []*pb.Chain
 	// Presently, only partition and delay with `accept-tcp-flags` need to add additional chains
@@ -149,8 +305,9 @@ func (s *Server) applyIptables(attack *core.NetworkCommand, ipset, uid string) e
 	}
 
 	if _, err := s.svr.SetIptablesChains(context.Background(), &pb.IptablesChainsRequest{
-		Chains:  chains,
-		EnterNS: false,
+		Chains:      chains,
+		ContainerId: target,
+		EnterNS:     len(target) > 0,
 	}); err != nil {
 		return perrors.WithStack(err)
 	}
@@ -162,6 +319,7 @@ func (s *Server) applyIptables(attack *core.NetworkCommand, ipset, uid string) e
 			Direction:  pb.Chain_Direction_name[int32(newChain.Direction)],
 			Protocol:   newChain.Protocol,
 			Experiment: uid,
+			Target:     target,
 		}); err != nil {
 			return perrors.WithStack(err)
 		}
@@ -176,7 +334,8 @@ func (s *Server) applyTC(attack *core.NetworkCommand, ipset string, uid string)
 		return perrors.WithStack(err)
 	}
 
-	tcs, err := core.TCRuleList(tcRules).ToTCs()
+	target := attack.NetNSTarget()
+	tcs, err := core.TCRuleList(tcRules).OfTarget(target).ToTCs()
 	if err != nil {
 		return perrors.WithStack(err)
 	}
@@ -188,10 +347,14 @@ func (s *Server) applyTC(attack *core.NetworkCommand, ipset string, uid string)
 			return perrors.WithStack(err)
 		}
 
-		tcs = append(tcs, newTC)
+		tcs = core.MergeTCs(append(tcs, newTC))
 	}
 
-	if _, err := s.svr.SetTcs(context.Background(), &pb.TcsRequest{Tcs: tcs, EnterNS: false}); err != nil {
+	if _, err := s.svr.SetTcs(context.Background(), &pb.TcsRequest{
+		Tcs:         tcs,
+		ContainerId: target,
+		EnterNS:     len(target) > 0,
+	}); err != nil {
 		return perrors.WithStack(err)
 	}
 
@@ -250,6 +413,7 @@ func (s *Server) applyTC(attack *core.NetworkCommand, ipset string, uid string)
 		SourcePort: newTC.SourcePort,
 		EgressPort: newTC.EgressPort,
 		Experiment: uid,
+		Target:     target,
 	}); err != nil {
 		return perrors.WithStack(err)
 	}
@@ -346,7 +510,7 @@ func (s *Server) applyEtcHosts(attack *core.NetworkCommand, uid string, env Envi
 }
 
 func (s *Server) applyFlood(attack *core.NetworkCommand) error {
-	cmd := bpm.DefaultProcessBuilder("bash", "-c", fmt.Sprintf("iperf -u -c %s -t %s -p %s -P %d -b %s", attack.IPAddress, attack.Duration, attack.Port, attack.Parallel, attack.Rate)).
+	cmd := bpm.DefaultProcessBuilder("bash", "-c", floodCommand(attack)).
 		Build(context.Background())
 
 	// Build will set SysProcAttr.Pdeathsig = syscall.SIGTERM, and so iperf will exit while chaosd exit
@@ -388,15 +552,31 @@ func (networkAttack) Recover(exp core.Experiment, env Environment) error {
 	case core.NetworkPortOccupiedAction:
 		return env.Chaos.recoverPortOccupied(attack, env.AttackUid)
 	case core.NetworkDelayAction, core.NetworkLossAction, core.NetworkCorruptAction, core.NetworkDuplicateAction, core.NetworkPartitionAction, core.NetworkBandwidthAction:
+		env.Chaos.networkLock.Lock()
+		defer env.Chaos.networkLock.Unlock()
+
+		if err := env.Chaos.checkNetNSTarget(attack); err != nil {
+			// the other errors, such as the container runtime is not available, are returned,
+			// so that the recovery can be retried
+			if !crclient.IsTargetGone(err) {
+				return err
+			}
+			// the rules are removed with the network namespace of the removed container or the exited process,
+			// so only the rules in the DB are deleted
+			log.Warn("network namespace is gone, delete the network rules of the experiment",
+				zap.String("uid", env.AttackUid), zap.Error(err))
+			return env.Chaos.deleteNetworkRules(env.AttackUid)
+		}
+
 		if err := env.Chaos.recoverIPSet(env.AttackUid); err != nil {
 			return perrors.WithStack(err)
 		}
 
-		if err := env.Chaos.recoverIptables(env.AttackUid); err != nil {
+		if err := env.Chaos.recoverIptables(env.AttackUid, attack.NetNSTarget()); err != nil {
 			return perrors.WithStack(err)
 		}
 
-		if err := env.Chaos.recoverTC(env.AttackUid, attack.Device); err != nil {
+		if err := env.Chaos.recoverTC(env.AttackUid, attack.Device, attack.NetNSTarget()); err != nil {
 			return perrors.WithStack(err)
 		}
 	case core.NetworkNICDownAction:
@@ -407,6 +587,20 @@ func (networkAttack) Recover(exp core.Experiment, env Environment) error {
 	return nil
 }
 
+// deleteNetworkRules deletes the ipset, iptables and tc rules of the experiment from the DB without changing the host.
+func (s *Server) deleteNetworkRules(uid string) error {
+	if err := s.ipsetRule.DeleteByExperiment(context.Background(), uid); err != nil {
+		return perrors.WithStack(err)
+	}
+	if err := s.iptablesRule.DeleteByExperiment(context.Background(), uid); err != nil {
+		return perrors.WithStack(err)
+	}
+	if err := s.tcRule.DeleteByExperiment(context.Background(), uid); err != nil {
+		return perrors.WithStack(err)
+	}
+	return nil
+}
+
 func (s *Server) recoverIPSet(uid string) error {
 	if err := s.ipsetRule.DeleteByExperiment(context.Background(), uid); err != nil {
 		return perrors.WithStack(err)
@@ -415,7 +609,7 @@ func (s *Server) recoverIPSet(uid string) error {
 	return nil
 }
 
-func (s *Server) recoverIptables(uid string) error {
+func (s *Server) recoverIptables(uid, target string) error {
 	if err := s.iptablesRule.DeleteByExperiment(context.Background(), uid); err != nil {
 		return perrors.WithStack(err)
 	}
@@ -425,11 +619,12 @@ func (s *Server) recoverIptables(uid string) error {
 		return perrors.WithStack(err)
 	}
 
-	chains := core.IptablesRuleList(iptables).ToChains()
+	chains := core.IptablesRuleList(iptables).OfTarget(target).ToChains()
 
 	if _, err := s.svr.SetIptablesChains(context.Background(), &pb.IptablesChainsRequest{
-		Chains:  chains,
-		EnterNS: false,
+		Chains:      chains,
+		ContainerId: target,
+		EnterNS:     len(target) > 0,
 	}); err != nil {
 		return perrors.WithStack(err)
 	}
@@ -437,19 +632,23 @@ func (s *Server) recoverIptables(uid string) error {
 	return nil
 }
 
-func (s *Server) recoverTC(uid string, device string) error {
+func (s *Server) recoverTC(uid, device, target string) error {
 	if err := s.tcRule.DeleteByExperiment(context.Background(), uid); err != nil {
 		return perrors.WithStack(err)
 	}
 
 	tcRules, err := s.tcRule.FindByDevice(context.Background(), device)
 
-	tcs, err := core.TCRuleList(tcRules).ToTCs()
+	tcs, err := core.TCRuleList(tcRules).OfTarget(target).ToTCs()
 	if err != nil {
 		return perrors.WithStack(err)
 	}
 
-	if _, err := s.svr.SetTcs(context.Background(), &pb.TcsRequest{Tcs: tcs, EnterNS: false}); err != nil {
+	if _, err := s.svr.SetTcs(context.Background(), &pb.TcsRequest{
+		Tcs:         tcs,
+		ContainerId: target,
+		EnterNS:     len(target) > 0,
+	}); err != nil {
 		return perrors.WithStack(err)
 	}
 
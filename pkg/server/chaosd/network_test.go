@@ -0,0 +1,100 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chaos-mesh/chaos-mesh/pkg/chaosdaemon/crclients"
+	"github.com/pingcap/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+	"github.com/chaos-mesh/chaosd/pkg/crclient"
+)
+
+// fakeNetNSClient resolves the pids of the running containers, the other containers are removed
+// unless the runtime is unavailable.
+type fakeNetNSClient struct {
+	crclients.ContainerRuntimeInfoClient
+	pids        map[string]uint32
+	unavailable bool
+}
+
+func (c fakeNetNSClient) GetPidFromContainerID(_ context.Context, containerID string) (uint32, error) {
+	if pid, ok := c.pids[containerID]; ok {
+		return pid, nil
+	}
+	if c.unavailable {
+		return 0, errors.New("container runtime is unavailable")
+	}
+	return 0, errors.Annotatef(crclient.ErrTargetGone, "container %s not found", containerID)
+}
+
+// the fake rule stores record the experiments whose rules are deleted.
+type fakeIPSetRuleStore struct {
+	core.IPSetRuleStore
+	deleted *[]string
+}
+
+func (s fakeIPSetRuleStore) DeleteByExperiment(_ context.Context, experiment string) error {
+	*s.deleted = append(*s.deleted, "ipset "+experiment)
+	return nil
+}
+
+type fakeIptablesRuleStore struct {
+	core.IptablesRuleStore
+	deleted *[]string
+}
+
+func (s fakeIptablesRuleStore) DeleteByExperiment(_ context.Context, experiment string) error {
+	*s.deleted = append(*s.deleted, "iptables "+experiment)
+	return nil
+}
+
+type fakeTCRuleStore struct {
+	core.TCRuleStore
+	deleted *[]string
+}
+
+func (s fakeTCRuleStore) DeleteByExperiment(_ context.Context, experiment string) error {
+	*s.deleted = append(*s.deleted, "tc "+experiment)
+	return nil
+}
+
+func TestNetworkAttack_RecoverGoneNetNS(t *testing.T) {
+	var deleted []string
+	s := &Server{
+		ipsetRule:    fakeIPSetRuleStore{deleted: &deleted},
+		iptablesRule: fakeIptablesRuleStore{deleted: &deleted},
+		tcRule:       fakeTCRuleStore{deleted: &deleted},
+		crClient:     fakeNetNSClient{pids: map[string]uint32{}},
+	}
+
+	attack := core.NewNetworkCommand()
+	attack.Action, attack.Device, attack.IPAddress, attack.Latency = core.NetworkDelayAction, "eth0", "10.0.0.1", "10ms"
+	attack.ContainerID = "docker://removed"
+	exp := core.Experiment{Kind: core.NetworkAttack, RecoverCommand: attack.RecoverData()}
+
+	// the container is removed, so the rules are only deleted from the DB
+	assert.NoError(t, NetworkAttack.Recover(exp, Environment{Chaos: s, AttackUid: "uid"}))
+	assert.Equal(t, []string{"ipset uid", "iptables uid", "tc uid"}, deleted)
+
+	// the recovery is retried later if the runtime is unavailable
+	deleted = nil
+	s.crClient = fakeNetNSClient{pids: map[string]uint32{}, unavailable: true}
+	assert.Error(t, NetworkAttack.Recover(exp, Environment{Chaos: s, AttackUid: "uid"}))
+	assert.Empty(t, deleted)
+}
@@ -0,0 +1,148 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"context"
+	"time"
+
+	"github.com/joomcode/errorx"
+	"github.com/pingcap/log"
+	perr "github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+)
+
+// PauseAttack lifts the fault of the experiment temporarily. The cron job of a scheduled
+// experiment is suspended without being removed, and the run waiting for recovery is recovered.
+// If the current process is not chaosd server, the runs of the scheduled experiment waiting for recovery
+// are recovered from the DB, and its cron job is paused when chaosd server restores it.
+func (s *Server) PauseAttack(uid string) error {
+	exp, err := s.expStore.FindByUid(context.Background(), uid)
+	if err != nil {
+		return err
+	}
+
+	switch exp.Status {
+	case core.Scheduled:
+		if s.serving {
+			if err := s.Cron.Pause(exp.ID); err != nil {
+				return perr.WithMessage(err, "failed to pause scheduled task")
+			}
+		} else if err := s.recoverStoredRuns(exp); err != nil {
+			// chaosd server is not running, the job is paused when the server restores it
+			return perr.WithMessagef(err, "Pause experiment %s failed", uid)
+		}
+	case core.Success:
+		attackType, err := getAttackType(exp.Kind)
+		if err != nil {
+			return err
+		}
+
+		s.stopDeadlineTimer(uid)
+		if err := attackType.Recover(*exp, s.newEnvironment(uid)); err != nil {
+			if errorx.IsOfType(err, core.ErrNonRecoverableAttack) {
+				return perr.Errorf("can not pause non-recoverable %s experiment", exp.Kind)
+			}
+			return perr.WithMessagef(err, "Pause experiment %s failed", uid)
+		}
+	default:
+		return perr.Errorf("can not pause %s experiment", exp.Status)
+	}
+
+	// the UpdatedAt of the experiment is the time when it is paused
+	if err := s.expStore.Update(context.Background(), uid, core.Paused, "", exp.RecoverCommand); err != nil {
+		return perr.WithStack(err)
+	}
+	return nil
+}
+
+// ResumeAttack injects the fault of the paused experiment again from the stored config,
+// the experiment keeps its uid and the history of its runs. The policy and the conflicts are checked again
+// under policyLock like createExperiment, since they may have changed while the experiment is paused.
+func (s *Server) ResumeAttack(uid string) error {
+	exp, err := s.expStore.FindByUid(context.Background(), uid)
+	if err != nil {
+		return err
+	}
+
+	if exp.Status != core.Paused {
+		return perr.Errorf("can not resume %s experiment", exp.Status)
+	}
+
+	options, err := exp.GetRequestCommand()
+	if err != nil {
+		return err
+	}
+
+	s.policyLock.Lock()
+	defer s.policyLock.Unlock()
+
+	if err := s.checkHalted(); err != nil {
+		return err
+	}
+	if err := s.checkPolicy(options); err != nil {
+		return err
+	}
+	if err := s.checkConflicts(options, uid); err != nil {
+		return err
+	}
+
+	if len(options.Cron()) > 0 {
+		if s.serving {
+			if err := s.Cron.Resume(exp.ID); err != nil {
+				return perr.WithMessage(err, "failed to resume scheduled task")
+			}
+		}
+		if err := s.expStore.Update(context.Background(), uid, core.Scheduled, "", exp.RecoverCommand); err != nil {
+			return perr.WithStack(err)
+		}
+		return nil
+	}
+
+	attackType, err := getAttackType(exp.Kind)
+	if err != nil {
+		return err
+	}
+
+	if err := attackType.Attack(options, s.newEnvironment(uid)); err != nil {
+		if err := s.expStore.Update(context.Background(), uid, core.Error, err.Error(), options.RecoverData()); err != nil {
+			log.Error("failed to update experiment", zap.Error(err))
+		}
+		return perr.WithMessagef(err, "Resume experiment %s failed", uid)
+	}
+
+	// the time while the experiment is paused doesn't count in its duration
+	if exp.Deadline != nil {
+		deadline := exp.Deadline.Add(time.Since(exp.UpdatedAt))
+		exp.Deadline = &deadline
+	}
+	exp.Status = core.Success
+	exp.Message = ""
+	exp.RecoverCommand = options.RecoverData()
+	if err := s.expStore.Set(context.Background(), exp); err != nil {
+		return perr.WithStack(err)
+	}
+
+	if err := s.armDeadline(exp); err != nil {
+		log.Error("failed to arm the deadline of experiment, it will not be recovered automatically",
+			zap.String("uid", uid), zap.Error(err))
+	}
+	if err := s.armProbes(exp, options.GetProbes()); err != nil {
+		log.Error("failed to arm the probes of experiment, they will not be checked while the attack is running",
+			zap.String("uid", uid), zap.Error(err))
+	}
+	return nil
+}
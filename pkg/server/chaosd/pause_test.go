@@ -0,0 +1,105 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+	"github.com/chaos-mesh/chaosd/pkg/scheduler"
+)
+
+func TestServer_PauseScheduledAttackWithoutServer(t *testing.T) {
+	attack := &countingAttack{}
+	RegisterAttackKind(AttackKind{
+		Kind:      "pause-test",
+		NewConfig: func() core.AttackConfig { return &core.ProcessCommand{} },
+		Attack:    attack,
+	})
+
+	store := &fakeExpStore{exps: []*core.Experiment{
+		{ID: 1, Uid: "scheduled", Kind: "pause-test", Status: core.Scheduled,
+			RecoverCommand: `{"schedule":"@every 1h","duration":"1h","kind":"pause-test","process":"sleep"}`},
+	}}
+	runStore := &fakeRunStore{runs: []*core.ExperimentRun{
+		{UID: "run-0", ExperimentID: 1, Status: core.RunRecovered},
+		{UID: "run-1", ExperimentID: 1, Status: core.RunSuccess},
+	}}
+	s := &Server{
+		expStore:       store,
+		ExpRun:         runStore,
+		Cron:           scheduler.NewScheduler(runStore, store),
+		haltState:      &fakeHaltStateStore{},
+		deadlineTimers: make(map[string]*time.Timer),
+	}
+
+	// without chaosd server, only the run waiting for recovery in the DB is recovered
+	assert.NoError(t, s.PauseAttack("scheduled"))
+	assert.Equal(t, core.Paused, store.exps[0].Status)
+	assert.Equal(t, []string{"scheduled"}, attack.recovered)
+	assert.Equal(t, core.RunRecovered, runStore.runs[1].Status)
+
+	assert.NoError(t, s.ResumeAttack("scheduled"))
+	assert.Equal(t, core.Scheduled, store.exps[0].Status)
+
+	// chaosd server fails to pause the experiment which it doesn't schedule
+	s.serving = true
+	assert.Error(t, s.PauseAttack("scheduled"))
+	assert.Equal(t, core.Scheduled, store.exps[0].Status)
+}
+
+func TestServer_ResumeAttackChecksPolicyAndProbes(t *testing.T) {
+	attack := &countingAttack{}
+	RegisterAttackKind(AttackKind{
+		Kind:      "resume-test",
+		NewConfig: func() core.AttackConfig { return &core.ProcessCommand{} },
+		Attack:    attack,
+	})
+
+	unhealthy := filepath.Join(t.TempDir(), "unhealthy")
+	store := &fakeExpStore{exps: []*core.Experiment{
+		{Uid: "protected", Kind: "resume-test", Status: core.Paused, LaunchMode: core.ServerMode,
+			RecoverCommand: `{"kind":"resume-test","process":"sshd"}`},
+		{Uid: "probed", Kind: "resume-test", Status: core.Paused, LaunchMode: core.ServerMode,
+			RecoverCommand: `{"kind":"resume-test","process":"sleep","probes":[` +
+				`{"type":"command","command":"test ! -e ` + unhealthy + `","interval":"10ms"}]}`},
+	}}
+	s := &Server{
+		expStore:       store,
+		haltState:      &fakeHaltStateStore{},
+		deadlineTimers: make(map[string]*time.Timer),
+		policy:         &core.Policy{ProtectedProcesses: []string{"sshd"}},
+		serving:        true,
+	}
+
+	// the policy is checked again, the experiment may touch what is protected after it is paused
+	assert.Error(t, s.ResumeAttack("protected"))
+	exp, err := store.FindByUid(context.Background(), "protected")
+	assert.NoError(t, err)
+	assert.Equal(t, core.Paused, exp.Status)
+
+	// the probes are checked again while the resumed attack is running
+	assert.NoError(t, s.ResumeAttack("probed"))
+	assert.NoError(t, os.WriteFile(unhealthy, nil, 0600))
+	assert.Eventually(t, func() bool {
+		exp, err := store.FindByUid(context.Background(), "probed")
+		return err == nil && exp.Status == core.Aborted
+	}, 5*time.Second, 10*time.Millisecond)
+}
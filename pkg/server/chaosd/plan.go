@@ -0,0 +1,91 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"fmt"
+	"strings"
+
+	perr "github.com/pkg/errors"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+)
+
+// planUIDPlaceholder is used in the plan when the uid of the attack is not specified,
+// because the uid is only generated when the attack is executed.
+const planUIDPlaceholder = "<uid>"
+
+// AttackPlanner is implemented by the attack types which can tell
+// what they will do on the host without touching it.
+type AttackPlanner interface {
+	// Plan returns the steps of the attack in the order they will be executed.
+	Plan(options core.AttackConfig, env Environment) ([]string, error)
+}
+
+// AttackPlan describes what an attack will do on the host.
+type AttackPlan struct {
+	UID      string   `json:"uid"`
+	Kind     string   `json:"kind"`
+	Action   string   `json:"action"`
+	Schedule string   `json:"schedule,omitempty"`
+	Duration string   `json:"duration,omitempty"`
+	Steps    []string `json:"steps"`
+}
+
+func (p *AttackPlan) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Plan of %s attack %s, uid: %s\n", p.Kind, p.Action, p.UID)
+	if len(p.Schedule) > 0 {
+		fmt.Fprintf(&b, "Schedule: %s\n", p.Schedule)
+	}
+	if len(p.Duration) > 0 {
+		fmt.Fprintf(&b, "Duration: %s\n", p.Duration)
+	}
+	for i, step := range p.Steps {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, step)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// PlanAttack returns what the attack will do without executing it, the options should have been validated.
+// Nothing is stored and nothing is changed on the host.
+func (s *Server) PlanAttack(attackType AttackType, options core.AttackConfig) (*AttackPlan, error) {
+	uid := options.GetUID()
+	if len(uid) == 0 {
+		uid = planUIDPlaceholder
+	}
+
+	plan := &AttackPlan{
+		UID:      uid,
+		Kind:     options.AttackKind(),
+		Action:   options.String(),
+		Schedule: options.Cron(),
+	}
+	if duration, err := options.ScheduleDuration(); err == nil && duration != nil {
+		plan.Duration = duration.String()
+	}
+
+	planner, ok := attackType.(AttackPlanner)
+	if !ok {
+		plan.Steps = []string{fmt.Sprintf("execute %s attack with config %s", plan.Kind, options.RecoverData())}
+		return plan, nil
+	}
+
+	steps, err := planner.Plan(options, s.newEnvironment(uid))
+	if err != nil {
+		return nil, perr.WithStack(err)
+	}
+	plan.Steps = steps
+	return plan, nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+)
+
+func TestServer_PlanAttack(t *testing.T) {
+	s := &Server{}
+
+	network := core.NewNetworkCommand()
+	network.Action = core.NetworkDelayAction
+	network.Device = "eth0"
+	network.IPAddress = "10.0.0.1"
+	network.Latency = "10ms"
+	network.UID = "0123456789abcdef-uid"
+	network.CompleteDefaults()
+	plan, err := s.PlanAttack(NetworkAttack, network)
+	assert.NoError(t, err)
+	assert.Equal(t, "0123456789abcdef-uid", plan.UID)
+	assert.Len(t, plan.Steps, 2)
+	assert.Contains(t, plan.Steps[0], "create ipset chaos-0123456789abcdef with 10.0.0.1/32")
+	assert.Contains(t, plan.Steps[1], "add tc qdisc on device eth0")
+
+	file := core.NewFileCommand()
+	file.Action = core.FileAppendAction
+	file.FileName = "/tmp/a"
+	file.Data = "chaos"
+	file.Count = 1
+	plan, err = s.PlanAttack(FileAttack, file)
+	assert.NoError(t, err)
+	assert.Equal(t, planUIDPlaceholder, plan.UID)
+	assert.Equal(t, []string{
+		"run: FileTool copy --file-name /tmp/a --copy-file-name /tmp/a.<uid>",
+		"run: FileTool append --count 1 --data chaos --file-name /tmp/a",
+	}, plan.Steps)
+
+	disk := core.DiskOption{
+		CommonAttackConfig: core.CommonAttackConfig{
+			Action: core.DiskFillAction,
+		},
+		Size:            "10M",
+		Path:            "./plan",
+		FillByFallocate: true,
+	}
+	diskConf, err := disk.PreProcess()
+	assert.NoError(t, err)
+	plan, err = s.PlanAttack(DiskAttack, diskConf)
+	assert.NoError(t, err)
+	assert.Len(t, plan.Steps, 2)
+	assert.Contains(t, plan.Steps[0], "run: fallocate")
+	// nothing is changed on the host
+	_, err = os.Stat("./plan")
+	assert.True(t, os.IsNotExist(err))
+
+	sleep := exec.Command("sleep", "600")
+	assert.NoError(t, sleep.Start())
+	defer sleep.Process.Kill()
+
+	process := core.NewProcessCommand()
+	process.Action = core.ProcessKillAction
+	process.Process = strconv.Itoa(sleep.Process.Pid)
+	plan, err = s.PlanAttack(ProcessAttack, process)
+	assert.NoError(t, err)
+	assert.Len(t, plan.Steps, 1)
+	// the process is not killed by the plan
+	assert.NoError(t, sleep.Process.Signal(syscall.Signal(0)))
+}
@@ -0,0 +1,197 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	perr "github.com/pkg/errors"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+	"github.com/chaos-mesh/chaosd/pkg/utils"
+)
+
+// createExperiment checks the attack against the policy and the active experiments, and records the experiment.
+// The checks and the record are serialized, so that the limits of the policy can not be exceeded
+// and the conflicting attacks can not be created at the same time.
+func (s *Server) createExperiment(exp *core.Experiment, options core.AttackConfig) error {
+	s.policyLock.Lock()
+	defer s.policyLock.Unlock()
+
+	// chaosd may be halted after the check in ExecuteAttack
+	if err := s.checkHalted(); err != nil {
+		return err
+	}
+	if err := s.checkPolicy(options); err != nil {
+		return err
+	}
+	if err := s.checkPolicyLimits(options.AttackKind()); err != nil {
+		return err
+	}
+	if err := s.checkConflicts(options, exp.Uid); err != nil {
+		return err
+	}
+	return perr.WithStack(s.expStore.Set(context.Background(), exp))
+}
+
+// checkPolicy checks the targets of the attack against the policy, it returns an ErrPolicyViolation if
+// the attack touches anything protected by the policy.
+func (s *Server) checkPolicy(options core.AttackConfig) error {
+	if s.policy == nil {
+		return nil
+	}
+
+	switch config := options.(type) {
+	case *core.ProcessCommand:
+		return checkProcessPolicy(s.policy, config)
+	case *core.NetworkCommand:
+		return checkNetworkPolicy(s.policy, config)
+	case *core.FileCommand:
+		return checkPathPolicy(s.policy, config.FileName, config.DirName, config.SourceFile, config.DestFile)
+	case *core.DiskAttackConfig:
+		if config.Action == core.DiskFillAction || config.Action == core.DiskWritePayloadAction {
+			paths := []string{config.Path}
+			if config.DdOptions != nil {
+				for _, opt := range *config.DdOptions {
+					paths = append(paths, opt.WritePath)
+				}
+			}
+			if config.FAllocateOption != nil {
+				paths = append(paths, config.FAllocateOption.FileName)
+			}
+			return checkPathPolicy(s.policy, paths...)
+		}
+	}
+	return nil
+}
+
+// checkPolicyLimits checks the number of the active experiments against the concurrency limits of the policy.
+// The experiments being created are counted as active.
+func (s *Server) checkPolicyLimits(kind string) error {
+	if s.policy == nil || (s.policy.MaxActiveExperiments == 0 && len(s.policy.MaxActiveExperimentsPerKind) == 0) {
+		return nil
+	}
+
+	exps, err := s.listLiveExperiments()
+	if err != nil {
+		return err
+	}
+	total, ofKind := len(exps), 0
+	for _, exp := range exps {
+		if exp.Kind == kind {
+			ofKind++
+		}
+	}
+
+	if max := s.policy.MaxActiveExperiments; max > 0 && total >= max {
+		return policyViolation(core.MaxActiveExperimentsRule, "there are %d active experiments, the max is %d", total, max)
+	}
+	if max := s.policy.MaxActiveExperimentsPerKind[kind]; max > 0 && ofKind >= max {
+		return policyViolation(core.MaxActiveExperimentsPerKindRule,
+			"there are %d active %s experiments, the max is %d", ofKind, kind, max)
+	}
+	return nil
+}
+
+func checkProcessPolicy(policy *core.Policy, attack *core.ProcessCommand) error {
+	for _, name := range policy.ProtectedProcesses {
+		if attack.Process == name {
+			return policyViolation(core.ProtectedProcessesRule, "process %s is protected", attack.Process)
+		}
+	}
+
+	// check all the processes which may be signaled, since the attack chooses them from the matched ones randomly
+	processes, err := listProcesses()
+	if err != nil {
+		return err
+	}
+	matched, err := selectProcesses(attack, processes)
+	if err != nil {
+		return err
+	}
+	if attack.KillChildren {
+		matched = withDescendants(matched, processes)
+	}
+	for _, p := range matched {
+		for _, protected := range policy.ProtectedPIDs {
+			if p.pid == protected {
+				return policyViolation(core.ProtectedPIDsRule, "process %s with pid %d is protected", attack.Selector(), p.pid)
+			}
+		}
+		for _, protected := range policy.ProtectedProcesses {
+			if p.name == protected {
+				return policyViolation(core.ProtectedProcessesRule, "process %s named %s is protected", attack.Selector(), p.name)
+			}
+		}
+	}
+	return nil
+}
+
+func checkNetworkPolicy(policy *core.Policy, config *core.NetworkCommand) error {
+	for _, device := range policy.ProtectedDevices {
+		if config.Device == device {
+			return policyViolation(core.ProtectedDevicesRule, "device %s is protected", device)
+		}
+	}
+
+	if config.Action != core.NetworkPartitionAction || len(policy.ProtectedCIDRs) == 0 {
+		return nil
+	}
+	if len(config.IPAddress) == 0 && len(config.Hostname) == 0 {
+		return policyViolation(core.ProtectedCIDRsRule, "partition without ip address or hostname touches the protected CIDRs")
+	}
+
+	var targets []string
+	for _, s := range []string{config.IPAddress, config.Hostname} {
+		if len(s) > 0 {
+			targets = append(targets, strings.Split(s, ",")...)
+		}
+	}
+	cidrs, err := utils.ResolveCidrs(targets)
+	if err != nil {
+		return perr.WithStack(err)
+	}
+	for _, cidr := range cidrs {
+		protected, overlapped, err := policy.OverlapsProtectedCIDR(cidr)
+		if err != nil {
+			return err
+		}
+		if overlapped {
+			return policyViolation(core.ProtectedCIDRsRule, "%s overlaps with the protected CIDR %s", cidr, protected)
+		}
+	}
+	return nil
+}
+
+func checkPathPolicy(policy *core.Policy, paths ...string) error {
+	for _, path := range paths {
+		if len(path) == 0 {
+			continue
+		}
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return perr.WithStack(err)
+		}
+		if protected, ok := policy.IsProtectedPath(abs); ok {
+			return policyViolation(core.ProtectedPathsRule, "path %s is under the protected path %s", path, protected)
+		}
+	}
+	return nil
+}
+
+func policyViolation(rule string, format string, args ...interface{}) error {
+	return core.ErrPolicyViolation.New("attack violates the policy, "+format, args...).WithProperty(core.PolicyRule, rule)
+}
@@ -0,0 +1,103 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"testing"
+
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+)
+
+func assertPolicyViolation(t *testing.T, err error, rule string) {
+	assert.Error(t, err)
+	assert.True(t, errorx.IsOfType(err, core.ErrAttackConfigValidation), "%v", err)
+	violated, ok := errorx.Cast(err).Property(core.PolicyRule)
+	assert.True(t, ok)
+	assert.Equal(t, rule, violated)
+}
+
+func TestServer_CheckPolicy(t *testing.T) {
+	sleep := exec.Command("sleep", "600")
+	assert.NoError(t, sleep.Start())
+	defer sleep.Process.Kill()
+
+	s := &Server{}
+	assert.NoError(t, s.checkPolicy(&core.ProcessCommand{Process: strconv.Itoa(sleep.Process.Pid)}))
+
+	s.policy = &core.Policy{
+		ProtectedProcesses: []string{"sshd"},
+		ProtectedPIDs:      []int{sleep.Process.Pid},
+		ProtectedDevices:   []string{"eth0"},
+		ProtectedPaths:     []string{"/etc"},
+		ProtectedCIDRs:     []string{"10.0.0.0/8"},
+	}
+
+	assertPolicyViolation(t, s.checkPolicy(&core.ProcessCommand{Process: "sshd"}), core.ProtectedProcessesRule)
+	assertPolicyViolation(t, s.checkPolicy(&core.ProcessCommand{Process: strconv.Itoa(sleep.Process.Pid)}), core.ProtectedPIDsRule)
+	// the protected process may be chosen by any random draw
+	other := exec.Command("sleep", "600")
+	assert.NoError(t, other.Start())
+	defer other.Process.Kill()
+	random := &core.ProcessCommand{Process: "sleep", Mode: core.FixedMode, Value: "1"}
+	for i := 0; i < 10; i++ {
+		assertPolicyViolation(t, s.checkPolicy(random), core.ProtectedPIDsRule)
+	}
+	// chaosd itself is never selected
+	assert.NoError(t, s.checkPolicy(&core.ProcessCommand{Process: strconv.Itoa(os.Getpid())}))
+	assert.NoError(t, s.checkPolicy(&core.ProcessCommand{Process: "no-such-process"}))
+
+	network := &core.NetworkCommand{CommonAttackConfig: core.CommonAttackConfig{Action: core.NetworkDelayAction}, Device: "eth0"}
+	assertPolicyViolation(t, s.checkPolicy(network), core.ProtectedDevicesRule)
+	network.Device = "eth1"
+	network.IPAddress = "10.1.1.1"
+	assert.NoError(t, s.checkPolicy(network))
+	network.Action = core.NetworkPartitionAction
+	assertPolicyViolation(t, s.checkPolicy(network), core.ProtectedCIDRsRule)
+	network.IPAddress = "192.168.1.0/24"
+	assert.NoError(t, s.checkPolicy(network))
+	network.IPAddress = ""
+	assertPolicyViolation(t, s.checkPolicy(network), core.ProtectedCIDRsRule)
+
+	file := &core.FileCommand{CommonAttackConfig: core.CommonAttackConfig{Action: core.FileRenameAction}, SourceFile: "/tmp/a", DestFile: "/etc/hosts"}
+	assertPolicyViolation(t, s.checkPolicy(file), core.ProtectedPathsRule)
+
+	disk := &core.DiskAttackConfig{CommonAttackConfig: core.CommonAttackConfig{Action: core.DiskFillAction}, Path: "/etc/fill"}
+	assertPolicyViolation(t, s.checkPolicy(disk), core.ProtectedPathsRule)
+	disk.Action = core.DiskReadPayloadAction
+	assert.NoError(t, s.checkPolicy(disk))
+}
+
+func TestServer_CheckPolicyLimits(t *testing.T) {
+	store := &fakeExpStore{exps: []*core.Experiment{
+		{Uid: "exp-0", Kind: core.StressAttack, Status: core.Success},
+		{Uid: "exp-1", Kind: core.NetworkAttack, Status: core.Created},
+		{Uid: "exp-2", Kind: core.StressAttack, Status: core.Destroyed},
+	}}
+	s := &Server{expStore: store, policy: &core.Policy{
+		MaxActiveExperiments:        3,
+		MaxActiveExperimentsPerKind: map[string]int{core.StressAttack: 1},
+	}}
+
+	assertPolicyViolation(t, s.checkPolicyLimits(core.StressAttack), core.MaxActiveExperimentsPerKindRule)
+	assert.NoError(t, s.checkPolicyLimits(core.NetworkAttack))
+
+	store.exps[2].Status = core.Paused
+	assertPolicyViolation(t, s.checkPolicyLimits(core.NetworkAttack), core.MaxActiveExperimentsRule)
+}
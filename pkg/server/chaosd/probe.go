@@ -0,0 +1,233 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/log"
+	perr "github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+)
+
+// checkProbes checks every probe once, it returns the result of the first failed probe
+// or nil if all the probes succeed.
+func checkProbes(probes []core.Probe) *core.ProbeResult {
+	for _, probe := range probes {
+		result := runProbe(context.Background(), probe)
+		if !result.Success {
+			return &result
+		}
+	}
+	return nil
+}
+
+func runProbe(ctx context.Context, probe core.Probe) core.ProbeResult {
+	timeout, err := probe.GetTimeout()
+	if err != nil {
+		timeout = core.DefaultProbeTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	switch probe.Type {
+	case core.HTTPProbe:
+		err = runHTTPProbe(ctx, probe)
+	case core.TCPProbe:
+		err = runTCPProbe(ctx, probe)
+	case core.CommandProbe:
+		err = runCommandProbe(ctx, probe)
+	default:
+		err = perr.Errorf("probe type %s not supported", probe.Type)
+	}
+
+	result := core.ProbeResult{Probe: probe.String(), Success: err == nil}
+	if err != nil {
+		result.Output = err.Error()
+	}
+	return result
+}
+
+func runHTTPProbe(ctx context.Context, probe core.Probe) error {
+	method := probe.Method
+	if len(method) == 0 {
+		method = http.MethodGet
+	}
+	req, err := http.NewRequestWithContext(ctx, method, probe.URL, nil)
+	if err != nil {
+		return perr.WithStack(err)
+	}
+
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return perr.WithStack(err)
+	}
+	defer resp.Body.Close()
+	latency := time.Since(start)
+
+	expectedStatus := probe.ExpectedStatus
+	if expectedStatus == 0 {
+		expectedStatus = http.StatusOK
+	}
+	if resp.StatusCode != expectedStatus {
+		return perr.Errorf("status code is %d, expected %d", resp.StatusCode, expectedStatus)
+	}
+
+	if len(probe.MaxLatency) > 0 {
+		maxLatency, err := time.ParseDuration(probe.MaxLatency)
+		if err != nil {
+			return perr.WithStack(err)
+		}
+		if latency > maxLatency {
+			return perr.Errorf("latency %s exceeds %s", latency, maxLatency)
+		}
+	}
+	return nil
+}
+
+func runTCPProbe(ctx context.Context, probe core.Probe) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", probe.Address)
+	if err != nil {
+		return perr.WithStack(err)
+	}
+	return conn.Close()
+}
+
+func runCommandProbe(ctx context.Context, probe core.Probe) error {
+	cmd := exec.CommandContext(ctx, "bash", "-c", probe.Command) // #nosec
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return perr.Errorf("%s, output: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// armProbes makes sure the probes of the experiment are checked while its attack is running.
+// In server mode the probes are checked in the current process. In command mode they are checked by the
+// detached chaosd process which waits for the deadline, it is started here if the experiment has no deadline.
+func (s *Server) armProbes(exp *core.Experiment, probes []core.Probe) error {
+	if len(probes) == 0 {
+		return nil
+	}
+
+	if exp.LaunchMode == core.CommandMode {
+		if exp.Deadline != nil {
+			return nil
+		}
+		return startDeadlineHelper(exp.Uid)
+	}
+
+	s.watchProbes(exp.Uid, probes, 0)
+	return nil
+}
+
+// watchProbes checks the probes periodically until the experiment is not active or the timeout is reached,
+// the timeout is ignored if it is 0. The experiment is aborted once any of the probes fails more times
+// in a row than its tolerance. The returned channel is closed when the watch is over.
+func (s *Server) watchProbes(uid string, probes []core.Probe, timeout time.Duration) <-chan struct{} {
+	return s.watchProbesWith(uid, probes, timeout, func(reason string) {
+		s.abortAttack(uid, reason)
+	})
+}
+
+// watchProbesWith is watchProbes calling abort once instead of aborting the experiment,
+// such as to abort only a run of a scheduled experiment.
+func (s *Server) watchProbesWith(uid string, probes []core.Probe, timeout time.Duration, abort func(reason string)) <-chan struct{} {
+	ctx, cancel := context.WithCancel(context.Background())
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+	}
+
+	var wg sync.WaitGroup
+	var abortOnce sync.Once
+	for _, probe := range probes {
+		wg.Add(1)
+		go func(probe core.Probe) {
+			defer wg.Done()
+			s.watchProbe(ctx, uid, probe, func(result core.ProbeResult) {
+				abortOnce.Do(func() {
+					cancel()
+					abort(result.String())
+				})
+			})
+		}(probe)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		cancel()
+		close(done)
+	}()
+	return done
+}
+
+func (s *Server) watchProbe(ctx context.Context, uid string, probe core.Probe, abort func(core.ProbeResult)) {
+	interval, err := probe.GetInterval()
+	if err != nil {
+		interval = core.DefaultProbeInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	failures := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		exp, err := s.expStore.FindByUid(context.Background(), uid)
+		if err != nil {
+			log.Error("failed to find experiment, stop checking its probe", zap.String("uid", uid), zap.Error(err))
+			return
+		}
+		if !isActiveExperiment(exp) {
+			return
+		}
+		// the fault is lifted while the experiment is paused
+		if exp.Status == core.Paused {
+			failures = 0
+			continue
+		}
+
+		result := runProbe(ctx, probe)
+		if ctx.Err() != nil {
+			return
+		}
+		if result.Success {
+			failures = 0
+			continue
+		}
+
+		failures++
+		log.Warn("probe of experiment failed", zap.String("uid", uid), zap.String("probe", result.Probe),
+			zap.String("output", result.Output), zap.Int("failures", failures), zap.Int("tolerance", probe.Tolerance))
+		if failures > probe.Tolerance {
+			abort(result)
+			return
+		}
+	}
+}
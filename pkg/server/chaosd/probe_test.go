@@ -0,0 +1,119 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+)
+
+func TestRunProbe(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(50 * time.Millisecond)
+		}
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer svr.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	closedAddress := listener.Addr().String()
+	assert.NoError(t, listener.Close())
+	listener, err = net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	for _, c := range []struct {
+		probe   core.Probe
+		success bool
+	}{
+		{core.Probe{Type: core.HTTPProbe, URL: svr.URL}, true},
+		{core.Probe{Type: core.HTTPProbe, URL: svr.URL + "/missing"}, false},
+		{core.Probe{Type: core.HTTPProbe, URL: svr.URL + "/missing", ExpectedStatus: http.StatusNotFound}, true},
+		{core.Probe{Type: core.HTTPProbe, URL: svr.URL + "/slow", MaxLatency: "10ms"}, false},
+		{core.Probe{Type: core.HTTPProbe, URL: svr.URL + "/slow", Timeout: "10ms"}, false},
+		{core.Probe{Type: core.TCPProbe, Address: listener.Addr().String()}, true},
+		{core.Probe{Type: core.TCPProbe, Address: closedAddress}, false},
+		{core.Probe{Type: core.CommandProbe, Command: "true"}, true},
+		{core.Probe{Type: core.CommandProbe, Command: "echo unhealthy; false"}, false},
+	} {
+		result := runProbe(context.Background(), c.probe)
+		assert.Equal(t, c.success, result.Success, c.probe.String())
+	}
+
+	result := checkProbes([]core.Probe{
+		{Type: core.CommandProbe, Command: "true"},
+		{Name: "broken", Type: core.CommandProbe, Command: "echo unhealthy; false"},
+	})
+	assert.NotNil(t, result)
+	assert.Equal(t, "broken", result.Probe)
+	assert.Contains(t, result.Output, "unhealthy")
+}
+
+func TestServer_WatchProbes(t *testing.T) {
+	attack := &countingAttack{failed: "unrecoverable"}
+	RegisterAttackKind(AttackKind{
+		Kind:      "probe-test",
+		NewConfig: func() core.AttackConfig { return &core.ProcessCommand{} },
+		Attack:    attack,
+	})
+
+	store := &fakeExpStore{exps: []*core.Experiment{
+		{Uid: "healthy", Kind: "probe-test", Status: core.Success, LaunchMode: core.ServerMode},
+		{Uid: "unhealthy", Kind: "probe-test", Status: core.Success, LaunchMode: core.ServerMode},
+		{Uid: "unrecoverable", Kind: "probe-test", Status: core.Success, LaunchMode: core.ServerMode},
+	}}
+	s := &Server{expStore: store, deadlineTimers: make(map[string]*time.Timer)}
+
+	// the failure is tolerated once, so the experiment is aborted after the second failure
+	probes := []core.Probe{
+		{Type: core.CommandProbe, Command: "true", Interval: "10ms"},
+		{Name: "flaky", Type: core.CommandProbe, Command: "false", Interval: "10ms", Tolerance: 1},
+	}
+	select {
+	case <-s.watchProbes("unhealthy", probes, 0):
+	case <-time.After(5 * time.Second):
+		t.Fatal("probes are not over after the experiment is aborted")
+	}
+
+	exp, err := store.FindByUid(context.Background(), "unhealthy")
+	assert.NoError(t, err)
+	assert.Equal(t, core.Aborted, exp.Status)
+	assert.Contains(t, exp.Message, "probe flaky failed")
+	assert.Equal(t, []string{"unhealthy"}, attack.recovered)
+
+	// the experiment failing to recover is not aborted, since its fault may be still there
+	<-s.watchProbes("unrecoverable", probes, 0)
+	exp, err = store.FindByUid(context.Background(), "unrecoverable")
+	assert.NoError(t, err)
+	assert.Equal(t, core.Error, exp.Status)
+	assert.Contains(t, exp.Message, "but failed to recover: Recover experiment unrecoverable failed")
+
+	// the watch ends at the timeout if the probes keep succeeding
+	<-s.watchProbes("healthy", probes[:1], 50*time.Millisecond)
+	exp, err = store.FindByUid(context.Background(), "healthy")
+	assert.NoError(t, err)
+	assert.Equal(t, core.Success, exp.Status)
+}
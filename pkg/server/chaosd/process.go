@@ -15,7 +15,12 @@ package chaosd
 
 import (
 	"fmt"
+	"math/rand"
+	"os"
 	"os/exec"
+	"os/user"
+	"regexp"
+	"sort"
 	"strconv"
 	"syscall"
 
@@ -32,67 +37,351 @@ type processAttack struct{}
 
 var ProcessAttack AttackType = processAttack{}
 
-func (processAttack) Attack(options core.AttackConfig, _ Environment) error {
+func (processAttack) Attack(options core.AttackConfig, env Environment) error {
 	attack := options.(*core.ProcessCommand)
 
+	processes, err := findProcesses(attack)
+	if err != nil {
+		return err
+	}
+	if len(processes) == 0 {
+		return errors.Errorf("process %s not found", attack.Selector())
+	}
+
+	if err := signalProcesses(attack, processes); err != nil {
+		return err
+	}
+
+	if attack.Action == core.ProcessCrashLoopAction {
+		killed := make([]processInfo, 0, len(attack.PIDs))
+		for _, p := range processes {
+			for _, pid := range attack.PIDs {
+				if p.pid == pid {
+					killed = append(killed, p)
+				}
+			}
+		}
+		return env.Chaos.startCrashLoop(env.AttackUid, attack, killed, nil)
+	}
+	return nil
+}
+
+// terminatingSignals are the signals which make the processes exit, the processes handling the other signals,
+// such as SIGHUP, SIGUSR1 and SIGSTOP, keep running, so they are not restarted on recovery.
+var terminatingSignals = map[syscall.Signal]bool{
+	syscall.SIGKILL: true,
+	syscall.SIGTERM: true,
+	syscall.SIGINT:  true,
+	syscall.SIGQUIT: true,
+	syscall.SIGABRT: true,
+}
+
+// signalProcesses sends the signal of the attack to the processes, and records the signaled ones in the attack.
+func signalProcesses(attack *core.ProcessCommand, processes []processInfo) error {
+	// the killed processes are restarted with their snapshots on recovery, unless the recover command is provided
+	capture := terminatingSignals[syscall.Signal(attack.Signal)] && len(attack.RecoverCmd) == 0
+	for _, p := range processes {
+		// the snapshot is captured before the process exits, and it is kept only if the process is signaled
+		var (
+			snapshot core.ProcessSnapshot
+			captured bool
+		)
+		if capture {
+			var err error
+			if snapshot, err = captureProcess(p.pid); err != nil {
+				log.Warn("process can not be restarted on recovery", zap.Int("pid", p.pid), zap.Error(err))
+			} else {
+				captured = true
+			}
+		}
+
+		err := syscall.Kill(p.pid, syscall.Signal(attack.Signal))
+		if err == syscall.ESRCH {
+			// the process has exited
+			continue
+		}
+		if err != nil {
+			err = errors.Annotate(err, fmt.Sprintf("kill process with signal %d", attack.Signal))
+			return errors.WithStack(err)
+		}
+		attack.PIDs = append(attack.PIDs, p.pid)
+		if captured {
+			attack.Snapshots = append(attack.Snapshots, snapshot)
+		}
+	}
+
+	log.Info("signal processes", zap.String("selector", attack.Selector()), zap.Int("signal", attack.Signal),
+		zap.Ints("pids", attack.PIDs))
+	return nil
+}
+
+func (processAttack) Plan(options core.AttackConfig, _ Environment) ([]string, error) {
+	attack := options.(*core.ProcessCommand)
+
+	processes, err := listProcesses()
+	if err != nil {
+		return nil, err
+	}
+	matched, err := selectProcesses(attack, processes)
+	if err != nil {
+		return nil, err
+	}
+	if len(matched) == 0 {
+		return nil, errors.Errorf("process %s not found", attack.Selector())
+	}
+
+	chosen := chooseProcesses(attack, matched, processes)
+	steps := make([]string, 0, len(chosen)+1)
+	if attack.GetMode() != core.AllMode {
+		steps = append(steps, fmt.Sprintf("choose %d of %d matched processes randomly with mode %s, "+
+			"the processes chosen by the attack may differ from the following ones",
+			attack.ChooseCount(len(matched), rand.Intn), len(matched), attack.GetMode()))
+	}
+	for _, p := range chosen {
+		steps = append(steps, fmt.Sprintf("send signal %d to process %d (%s)", attack.Signal, p.pid, p.name))
+	}
+	if attack.Action == core.ProcessCrashLoopAction {
+		step := fmt.Sprintf("send signal %d to the matched processes every %s until recovered", attack.Signal, attack.Interval)
+		if attack.WaitRestart {
+			step += ", only when they have come back up"
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// processInfo is what the process attack knows about a process to select it.
+type processInfo struct {
+	pid        int
+	ppid       int
+	name       string
+	cmdline    string
+	uid        string
+	createTime int64
+}
+
+// findProcesses returns the processes chosen by the attack in the order to be signaled.
+func findProcesses(attack *core.ProcessCommand) ([]processInfo, error) {
+	processes, err := listProcesses()
+	if err != nil {
+		return nil, err
+	}
+	matched, err := selectProcesses(attack, processes)
+	if err != nil {
+		return nil, err
+	}
+	return chooseProcesses(attack, matched, processes), nil
+}
+
+func listProcesses() ([]processInfo, error) {
 	processes, err := process.Processes()
 	if err != nil {
-		return errors.WithStack(err)
+		return nil, errors.WithStack(err)
 	}
 
-	notFound := true
+	infos := make([]processInfo, 0, len(processes))
 	for _, p := range processes {
-		pid := int(p.Pid)
+		// the process may exit at any time, so it is skipped if any of its info is missing
 		name, err := p.Name()
 		if err != nil {
-			return errors.WithStack(err)
+			continue
+		}
+		ppid, err := p.Ppid()
+		if err != nil {
+			continue
+		}
+		cmdline, err := p.Cmdline()
+		if err != nil {
+			continue
+		}
+		uids, err := p.Uids()
+		if err != nil || len(uids) == 0 {
+			continue
+		}
+		createTime, err := p.CreateTime()
+		if err != nil {
+			continue
 		}
 
-		if attack.Process == strconv.Itoa(pid) || attack.Process == name {
-			notFound = false
+		infos = append(infos, processInfo{
+			pid:        int(p.Pid),
+			ppid:       int(ppid),
+			name:       name,
+			cmdline:    cmdline,
+			uid:        strconv.Itoa(int(uids[0])),
+			createTime: createTime,
+		})
+	}
+	return infos, nil
+}
 
-			err = syscall.Kill(pid, syscall.Signal(attack.Signal))
-			if err != nil {
-				err = errors.Annotate(err, fmt.Sprintf("kill process with signal %d", attack.Signal))
-				return errors.WithStack(err)
+// selectProcesses returns the processes matching all the selectors of the attack.
+func selectProcesses(attack *core.ProcessCommand, processes []processInfo) ([]processInfo, error) {
+	var cmdline *regexp.Regexp
+	if len(attack.Cmdline) > 0 {
+		var err error
+		if cmdline, err = attack.CmdlineRegexp(); err != nil {
+			return nil, errors.Annotatef(err, "invalid cmdline %s", attack.Cmdline)
+		}
+	}
+	uid := ""
+	if len(attack.User) > 0 {
+		var err error
+		if uid, err = lookupUID(attack.User); err != nil {
+			return nil, err
+		}
+	}
+
+	excluded := chaosdAndAncestors(processes)
+	var selected []processInfo
+	for _, p := range processes {
+		if excluded[p.pid] {
+			continue
+		}
+		if len(attack.Process) > 0 && attack.Process != strconv.Itoa(p.pid) && attack.Process != p.name {
+			continue
+		}
+		if cmdline != nil && !cmdline.MatchString(p.cmdline) {
+			continue
+		}
+		if attack.ParentPID > 0 && p.ppid != attack.ParentPID {
+			continue
+		}
+		if len(uid) > 0 && p.uid != uid {
+			continue
+		}
+		selected = append(selected, p)
+	}
+
+	if (attack.Newest || attack.Oldest) && len(selected) > 1 {
+		sort.SliceStable(selected, func(i, j int) bool {
+			if selected[i].createTime != selected[j].createTime {
+				return selected[i].createTime < selected[j].createTime
 			}
-			attack.PIDs = append(attack.PIDs, pid)
+			return selected[i].pid < selected[j].pid
+		})
+		if attack.Newest {
+			selected = selected[len(selected)-1:]
+		} else {
+			selected = selected[:1]
 		}
 	}
+	return selected, nil
+}
+
+// chaosdPID returns the pid of chaosd, it is replaced in the tests.
+var chaosdPID = os.Getpid
 
-	if notFound {
-		err = errors.Errorf("process %s not found", attack.Process)
-		return errors.WithStack(err)
+// chaosdAndAncestors returns chaosd and its ancestors in the processes. They are never selected, like pgrep does,
+// since the command line of chaosd contains the selectors, and killing the shell running chaosd kills chaosd too.
+func chaosdAndAncestors(processes []processInfo) map[int]bool {
+	parents := make(map[int]int, len(processes))
+	for _, p := range processes {
+		parents[p.pid] = p.ppid
 	}
 
-	return nil
+	excluded := make(map[int]bool)
+	for pid := chaosdPID(); pid > 0 && !excluded[pid]; pid = parents[pid] {
+		excluded[pid] = true
+	}
+	return excluded
 }
 
-func (processAttack) Recover(exp core.Experiment, _ Environment) error {
-	config, err := exp.GetRequestCommand()
-	if err != nil {
-		return err
+// chooseProcesses chooses the processes from the matched ones randomly with the mode of the attack. If KillChildren
+// is set, the descendants of the chosen processes follow them, so that the parents are signaled before their children.
+func chooseProcesses(attack *core.ProcessCommand, matched []processInfo, processes []processInfo) []processInfo {
+	chosen := matched
+	if attack.GetMode() != core.AllMode {
+		chosen = make([]processInfo, len(matched))
+		copy(chosen, matched)
+		rand.Shuffle(len(chosen), func(i, j int) {
+			chosen[i], chosen[j] = chosen[j], chosen[i]
+		})
+		chosen = chosen[:attack.ChooseCount(len(chosen), rand.Intn)]
+		// signal the chosen processes in the order of pid
+		sort.SliceStable(chosen, func(i, j int) bool {
+			return chosen[i].pid < chosen[j].pid
+		})
 	}
-	pcmd := config.(*core.ProcessCommand)
-	if pcmd.Signal != int(syscall.SIGSTOP) {
-		if pcmd.RecoverCmd == "" {
-			return core.ErrNonRecoverableAttack.New("only SIGSTOP process attack and process attack with the recover-cmd are supported to recover")
+
+	if attack.KillChildren {
+		chosen = withDescendants(chosen, processes)
+	}
+	return chosen
+}
+
+// withDescendants appends the descendants of the selected processes level by level.
+func withDescendants(selected []processInfo, processes []processInfo) []processInfo {
+	children := make(map[int][]processInfo)
+	for _, p := range processes {
+		if p.ppid != p.pid {
+			children[p.ppid] = append(children[p.ppid], p)
 		}
+	}
 
-		rcmd := exec.Command("bash", "-c", pcmd.RecoverCmd)
-		if err := rcmd.Start(); err != nil {
-			return errors.WithStack(err)
+	seen := make(map[int]bool)
+	for _, p := range selected {
+		seen[p.pid] = true
+	}
+	tree := selected
+	for i := 0; i < len(tree); i++ {
+		for _, child := range children[tree[i].pid] {
+			if !seen[child.pid] {
+				seen[child.pid] = true
+				tree = append(tree, child)
+			}
 		}
+	}
+	return tree
+}
 
-		log.Info("Execute recover-cmd successfully", zap.String("recover-cmd", pcmd.RecoverCmd))
+// lookupUID returns the user ID of the user name, the user ID is returned as it is.
+func lookupUID(name string) (string, error) {
+	if _, err := strconv.Atoi(name); err == nil {
+		return name, nil
+	}
+	u, err := user.Lookup(name)
+	if err != nil {
+		return "", errors.Annotatef(err, "user %s not found", name)
+	}
+	return u.Uid, nil
+}
 
-	} else {
+func (processAttack) Recover(exp core.Experiment, env Environment) error {
+	config, err := exp.GetRequestCommand()
+	if err != nil {
+		return err
+	}
+	pcmd := config.(*core.ProcessCommand)
+	if pcmd.Action == core.ProcessCrashLoopAction {
+		env.Chaos.stopCrashLoop(exp.Uid)
+		// the supervisor of the processes may have brought them back
+		if len(pcmd.RecoverCmd) == 0 && len(pcmd.Snapshots) == 0 {
+			return nil
+		}
+	}
+	if pcmd.Signal == int(syscall.SIGSTOP) {
 		for _, pid := range pcmd.PIDs {
 			if err := syscall.Kill(pid, syscall.SIGCONT); err != nil {
 				return errors.WithStack(err)
 			}
 		}
+		return nil
 	}
 
-	return nil
+	if len(pcmd.RecoverCmd) > 0 {
+		// the recover command may start the process in foreground, so it is not waited to exit
+		pid, err := startAndConfirm(exec.Command("bash", "-c", pcmd.RecoverCmd))
+		if err != nil {
+			return errors.Annotatef(err, "execute recover-cmd %s", pcmd.RecoverCmd)
+		}
+		log.Info("Execute recover-cmd successfully", zap.String("recover-cmd", pcmd.RecoverCmd), zap.Int("pid", pid))
+		return nil
+	}
+
+	if len(pcmd.Snapshots) == 0 {
+		return core.ErrNonRecoverableAttack.New("process attack without the recover-cmd is not supported to recover, " +
+			"since none of the killed processes is captured")
+	}
+	return restartProcesses(pcmd.Snapshots)
 }
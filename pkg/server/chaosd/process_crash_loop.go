@@ -0,0 +1,228 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pingcap/log"
+	perr "github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+)
+
+// crashLoop is the background task of a crash-loop attack, it kills the processes until it is canceled.
+type crashLoop struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// startCrashLoop starts the crash-loop of the experiment, the processes killed by the attack itself are the first round.
+// The loop ends when the experiment is recovered, or at the deadline. If the deadline is nil, the loop ends when
+// the duration of the attack is reached from now.
+func (s *Server) startCrashLoop(uid string, attack *core.ProcessCommand, killed []processInfo, deadline *time.Time) error {
+	interval, err := attack.GetInterval()
+	if err != nil {
+		return err
+	}
+	for _, p := range killed {
+		s.recordProcessKill(uid, p, attack.Signal)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if deadline != nil {
+		ctx, cancel = context.WithDeadline(context.Background(), *deadline)
+	} else if duration, err := attack.ScheduleDuration(); err == nil && duration != nil && *duration > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), *duration)
+	}
+	loop := &crashLoop{cancel: cancel, done: make(chan struct{})}
+
+	// the previous loop of the experiment is replaced, such as the one of the last run of a schedule
+	s.stopCrashLoop(uid)
+	s.crashLoopLock.Lock()
+	if s.crashLoops == nil {
+		s.crashLoops = make(map[string]*crashLoop)
+	}
+	s.crashLoops[uid] = loop
+	s.crashLoopLock.Unlock()
+
+	go func() {
+		defer close(loop.done)
+		defer cancel()
+		s.runCrashLoop(ctx, uid, *attack, interval, killed)
+	}()
+
+	log.Info("start crash-loop of experiment", zap.String("uid", uid), zap.Duration("interval", interval))
+	return nil
+}
+
+// stopCrashLoop stops the crash-loop of the experiment and waits for it, it does nothing if there is no loop.
+func (s *Server) stopCrashLoop(uid string) {
+	s.crashLoopLock.Lock()
+	loop, ok := s.crashLoops[uid]
+	delete(s.crashLoops, uid)
+	s.crashLoopLock.Unlock()
+	if !ok {
+		return
+	}
+
+	loop.cancel()
+	<-loop.done
+	log.Info("stop crash-loop of experiment", zap.String("uid", uid))
+}
+
+// WaitCrashLoop waits until the crash-loop of the experiment ends, it returns at once if there is no loop.
+// The loop runs in the process executing the attack, so the attack command waits for it in command mode.
+func (s *Server) WaitCrashLoop(uid string) {
+	s.crashLoopLock.Lock()
+	loop, ok := s.crashLoops[uid]
+	s.crashLoopLock.Unlock()
+	if ok {
+		<-loop.done
+	}
+}
+
+func (s *Server) runCrashLoop(ctx context.Context, uid string, attack core.ProcessCommand, interval time.Duration, killed []processInfo) {
+	// the processes killed by the attack are also known from its recover data, when the loop is restored
+	killedPIDs := make(map[int]bool)
+	cmdlines := make(map[string]bool)
+	for _, pid := range attack.PIDs {
+		killedPIDs[pid] = true
+	}
+	for _, snapshot := range attack.Snapshots {
+		cmdlines[strings.Join(snapshot.Args, " ")] = true
+	}
+	for _, p := range killed {
+		killedPIDs[p.pid] = true
+		cmdlines[p.cmdline] = true
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// the experiment may be recovered by another process, such as chaosd recover in command mode
+		exp, err := s.expStore.FindByUid(context.Background(), uid)
+		if err != nil {
+			log.Error("failed to find experiment, stop its crash-loop", zap.String("uid", uid), zap.Error(err))
+			return
+		}
+		if !isActiveExperiment(exp) {
+			return
+		}
+		if exp.Status == core.Paused || s.checkHalted() != nil {
+			continue
+		}
+
+		processes, err := findProcesses(&attack)
+		if err != nil {
+			log.Warn("failed to find processes of crash-loop", zap.String("uid", uid), zap.Error(err))
+			continue
+		}
+		for _, p := range processes {
+			if attack.WaitRestart && (killedPIDs[p.pid] || !cmdlines[p.cmdline]) {
+				continue
+			}
+			if ctx.Err() != nil {
+				return
+			}
+
+			err := syscall.Kill(p.pid, syscall.Signal(attack.Signal))
+			if err == syscall.ESRCH {
+				continue
+			}
+			if err != nil {
+				log.Warn("failed to kill process of crash-loop", zap.String("uid", uid), zap.Int("pid", p.pid), zap.Error(err))
+				continue
+			}
+			killedPIDs[p.pid] = true
+			cmdlines[p.cmdline] = true
+			s.recordProcessKill(uid, p, attack.Signal)
+		}
+	}
+}
+
+// recordProcessKill records the kill as an event in the history of the experiment.
+func (s *Server) recordProcessKill(uid string, p processInfo, signal int) {
+	log.Info("crash-loop killed process", zap.String("uid", uid), zap.Int("pid", p.pid), zap.String("cmdline", p.cmdline))
+	if s.History == nil {
+		return
+	}
+
+	event, _ := json.Marshal(core.ProcessKillEvent{PID: p.pid, Name: p.name, Cmdline: p.cmdline, Signal: signal})
+	if err := s.History.Add(context.Background(), &core.ExperimentHistory{
+		ExperimentUID: uid,
+		Event:         core.HistoryProcessKilled,
+		After:         string(event),
+	}); err != nil {
+		log.Error("failed to record the history of experiment", zap.String("uid", uid), zap.Error(perr.WithStack(err)))
+	}
+}
+
+// RestoreCrashLoops starts the crash-loops of the running experiments launched by chaosd server again after it restarts.
+// The time while chaosd is down counts in the duration of the loops, the loops past their deadlines are not started,
+// since their experiments are recovered by the restored deadlines.
+func (s *Server) RestoreCrashLoops() error {
+	exps, err := s.expStore.ListByStatus(context.Background(), core.Success)
+	if err != nil {
+		return perr.WithStack(err)
+	}
+
+	for _, exp := range exps {
+		if exp.Kind != core.ProcessAttack || exp.LaunchMode != core.ServerMode {
+			continue
+		}
+		options, err := exp.GetRequestCommand()
+		if err != nil {
+			log.Error("failed to restore the crash-loop of experiment", zap.String("uid", exp.Uid), zap.Error(err))
+			continue
+		}
+		attack := options.(*core.ProcessCommand)
+		if attack.Action != core.ProcessCrashLoopAction || len(attack.Cron()) > 0 {
+			continue
+		}
+		deadline := crashLoopDeadline(exp, attack)
+		if deadline != nil && !deadline.After(time.Now()) {
+			continue
+		}
+		if err := s.startCrashLoop(exp.Uid, attack, nil, deadline); err != nil {
+			log.Error("failed to restore the crash-loop of experiment", zap.String("uid", exp.Uid), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// crashLoopDeadline returns the deadline of the experiment, or the end of its duration from its creation
+// if it has no deadline. It returns nil if the attack has no duration.
+func crashLoopDeadline(exp *core.Experiment, attack *core.ProcessCommand) *time.Time {
+	if exp.Deadline != nil {
+		return exp.Deadline
+	}
+	duration, err := attack.ScheduleDuration()
+	if err != nil || duration == nil || *duration <= 0 {
+		return nil
+	}
+	deadline := exp.CreatedAt.Add(*duration)
+	return &deadline
+}
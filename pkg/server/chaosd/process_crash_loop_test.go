@@ -0,0 +1,164 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"context"
+	"encoding/json"
+	"os/exec"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+)
+
+type fakeHistoryStore struct {
+	core.ExperimentHistoryStore
+	sync.Mutex
+	histories []*core.ExperimentHistory
+}
+
+func (s *fakeHistoryStore) Add(_ context.Context, history *core.ExperimentHistory) error {
+	s.Lock()
+	defer s.Unlock()
+	s.histories = append(s.histories, history)
+	return nil
+}
+
+func (s *fakeHistoryStore) killed() []int {
+	s.Lock()
+	defer s.Unlock()
+	var pids []int
+	for _, history := range s.histories {
+		var event core.ProcessKillEvent
+		if history.Event == core.HistoryProcessKilled && json.Unmarshal([]byte(history.After), &event) == nil {
+			pids = append(pids, event.PID)
+		}
+	}
+	return pids
+}
+
+// startSleep starts a process which is killed by the crash-loop, the returned channel is closed when it exits.
+func startSleep(t *testing.T) (int, <-chan struct{}) {
+	cmd := exec.Command("sleep", "613")
+	assert.NoError(t, cmd.Start())
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+	return cmd.Process.Pid, exited
+}
+
+func waitExited(exited <-chan struct{}, timeout time.Duration) bool {
+	select {
+	case <-exited:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+func TestProcessAttack_CrashLoop(t *testing.T) {
+	history := &fakeHistoryStore{}
+	s := &Server{
+		expStore:  &fakeExpStore{exps: []*core.Experiment{{Uid: "crash-loop", Status: core.Success}}},
+		History:   history,
+		haltState: &fakeHaltStateStore{},
+	}
+	env := s.newEnvironment("crash-loop")
+
+	options := &core.ProcessCommand{
+		CommonAttackConfig: core.CommonAttackConfig{Kind: core.ProcessAttack, Action: core.ProcessCrashLoopAction},
+		Cmdline:            "sleep 613",
+		Exact:              true,
+		Interval:           "50ms",
+		WaitRestart:        true,
+		// the processes are not restarted by the test
+		RecoverCmd: "true",
+	}
+	options.CompleteDefaults()
+	assert.NoError(t, options.Validate())
+
+	first, exited := startSleep(t)
+	assert.NoError(t, ProcessAttack.Attack(options, env))
+	assert.True(t, waitExited(exited, time.Second))
+	assert.Equal(t, []int{first}, options.PIDs)
+
+	// the process is killed again when it comes back up
+	second, exited := startSleep(t)
+	assert.True(t, waitExited(exited, time.Second))
+	// the kill is recorded after the process is signaled
+	assert.Eventually(t, func() bool { return len(history.killed()) == 2 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []int{first, second}, history.killed())
+
+	exp := core.Experiment{Uid: "crash-loop", Kind: core.ProcessAttack, RecoverCommand: options.RecoverData()}
+	assert.NoError(t, ProcessAttack.Recover(exp, env))
+
+	// the loop is stopped on recovery
+	third, exited := startSleep(t)
+	assert.False(t, waitExited(exited, 200*time.Millisecond))
+	assert.Equal(t, []int{first, second}, history.killed())
+	assert.NoError(t, syscall.Kill(third, syscall.SIGKILL))
+}
+
+func TestServer_RestoreCrashLoops(t *testing.T) {
+	recoverCommand := func(duration string) string {
+		options := &core.ProcessCommand{
+			CommonAttackConfig: core.CommonAttackConfig{Kind: core.ProcessAttack, Action: core.ProcessCrashLoopAction},
+			Process:            "chaosd-no-such-process",
+			Interval:           "1h",
+		}
+		options.Duration = duration
+		return options.RecoverData()
+	}
+	now := time.Now()
+	future, past := now.Add(time.Hour), now.Add(-time.Minute)
+	store := &fakeExpStore{exps: []*core.Experiment{
+		{Uid: "deadline", Kind: core.ProcessAttack, Status: core.Success, LaunchMode: core.ServerMode,
+			CreatedAt: now.Add(-time.Hour), Deadline: &future, RecoverCommand: recoverCommand("1m")},
+		{Uid: "expired", Kind: core.ProcessAttack, Status: core.Success, LaunchMode: core.ServerMode,
+			CreatedAt: now.Add(-time.Hour), Deadline: &past, RecoverCommand: recoverCommand("59m")},
+		{Uid: "created", Kind: core.ProcessAttack, Status: core.Success, LaunchMode: core.ServerMode,
+			CreatedAt: now.Add(-time.Hour), RecoverCommand: recoverCommand("30m")},
+		{Uid: "endless", Kind: core.ProcessAttack, Status: core.Success, LaunchMode: core.ServerMode,
+			CreatedAt: now.Add(-time.Hour), RecoverCommand: recoverCommand("")},
+	}}
+	s := &Server{expStore: store, haltState: &fakeHaltStateStore{}}
+	defer func() {
+		for _, exp := range store.exps {
+			s.stopCrashLoop(exp.Uid)
+		}
+	}()
+
+	// the loops are restored with the rest of their durations, instead of a full duration from now
+	assert.NoError(t, s.RestoreCrashLoops())
+	s.crashLoopLock.Lock()
+	defer s.crashLoopLock.Unlock()
+	assert.Contains(t, s.crashLoops, "deadline")
+	assert.Contains(t, s.crashLoops, "endless")
+	assert.NotContains(t, s.crashLoops, "expired")
+	assert.NotContains(t, s.crashLoops, "created")
+
+	attack := &core.ProcessCommand{}
+	attack.Duration = "30m"
+	assert.Equal(t, now.Add(-30*time.Minute), *crashLoopDeadline(store.exps[2], attack))
+	assert.Equal(t, &future, crashLoopDeadline(store.exps[0], attack))
+	attack.Duration = ""
+	assert.Nil(t, crashLoopDeadline(store.exps[3], attack))
+}
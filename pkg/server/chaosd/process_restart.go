@@ -0,0 +1,296 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/shirou/gopsutil/process"
+	"go.uber.org/zap"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+	"github.com/chaos-mesh/chaosd/pkg/utils"
+)
+
+// defaultPath is the PATH of the restarted processes.
+const defaultPath = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+// processStartCheckPeriod is how long a restarted process should keep running to be considered started.
+var processStartCheckPeriod = time.Second
+
+// processEnvDir returns the directory of the environment files of the killed processes, it is replaced in the tests.
+var processEnvDir = func() string {
+	return filepath.Join(utils.GetProgramPath(), "process-env")
+}
+
+// captureProcess returns the snapshot of the process, it should be captured before the process is killed.
+func captureProcess(pid int) (core.ProcessSnapshot, error) {
+	p, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return core.ProcessSnapshot{}, errors.WithStack(err)
+	}
+
+	snapshot := core.ProcessSnapshot{PID: pid}
+	ppid, err := p.Ppid()
+	if err != nil {
+		return snapshot, errors.WithStack(err)
+	}
+	snapshot.PPID = int(ppid)
+	if snapshot.CreateTime, err = p.CreateTime(); err != nil {
+		return snapshot, errors.WithStack(err)
+	}
+	if snapshot.Exe, err = p.Exe(); err != nil {
+		return snapshot, errors.WithStack(err)
+	}
+	if snapshot.Args, err = p.CmdlineSlice(); err != nil {
+		return snapshot, errors.WithStack(err)
+	}
+	if len(snapshot.Args) == 0 {
+		// kernel threads and zombies have no command line
+		return snapshot, errors.Errorf("process %d has no command line", pid)
+	}
+	if snapshot.Cwd, err = p.Cwd(); err != nil {
+		return snapshot, errors.WithStack(err)
+	}
+	uids, err := p.Uids()
+	if err != nil || len(uids) == 0 {
+		return snapshot, errors.Errorf("get uid of process %d: %v", pid, err)
+	}
+	gids, err := p.Gids()
+	if err != nil || len(gids) == 0 {
+		return snapshot, errors.Errorf("get gid of process %d: %v", pid, err)
+	}
+	snapshot.UID, snapshot.GID = uint32(uids[0]), uint32(gids[0])
+
+	// the unit is optional, since the process may be not managed by systemd
+	if cgroup, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid)); err == nil {
+		snapshot.Unit = systemdUnitOf(string(cgroup))
+	}
+	// the environment is optional, the process is restarted with the minimal environment without it
+	if environ, err := p.Environ(); err != nil {
+		log.Warn("environment of process is not captured", zap.Int("pid", pid), zap.Error(err))
+	} else if snapshot.EnvFile, err = saveProcessEnv(snapshot, environ); err != nil {
+		log.Warn("environment of process is not saved", zap.Int("pid", pid), zap.Error(err))
+	}
+	return snapshot, nil
+}
+
+// saveProcessEnv saves the environment of the process in a file which only root can read, since the environment
+// may contain secrets. It returns the path of the file.
+func saveProcessEnv(snapshot core.ProcessSnapshot, environ []string) (string, error) {
+	dir := processEnvDir()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", errors.WithStack(err)
+	}
+	// the environ of /proc ends with an empty entry
+	vars := make([]string, 0, len(environ))
+	for _, v := range environ {
+		if len(v) > 0 {
+			vars = append(vars, v)
+		}
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%d-%d.env", snapshot.PID, snapshot.CreateTime))
+	if err := os.WriteFile(path, []byte(strings.Join(vars, "\x00")), 0600); err != nil {
+		return "", errors.WithStack(err)
+	}
+	return path, nil
+}
+
+// loadProcessEnv returns the environment saved in the snapshot, or the minimal environment for the user
+// if it is not saved.
+func loadProcessEnv(snapshot core.ProcessSnapshot) []string {
+	if len(snapshot.EnvFile) > 0 {
+		content, err := os.ReadFile(snapshot.EnvFile)
+		if err == nil {
+			return strings.Split(string(content), "\x00")
+		}
+		log.Warn("environment of process is not loaded, restart it with the minimal environment",
+			zap.Int("pid", snapshot.PID), zap.Error(err))
+	}
+	return processEnv(snapshot.UID)
+}
+
+// systemdUnitOf returns the systemd service in the content of /proc/<pid>/cgroup, such as nginx.service of
+// "0::/system.slice/nginx.service". The services of the user managers are ignored, they can not be started
+// by the system manager.
+func systemdUnitOf(cgroup string) string {
+	for _, line := range strings.Split(cgroup, "\n") {
+		fields := strings.SplitN(line, ":", 3)
+		if len(fields) != 3 || (fields[1] != "" && fields[1] != "name=systemd") {
+			continue
+		}
+		if !strings.HasPrefix(fields[2], "/system.slice/") {
+			continue
+		}
+		for _, name := range strings.Split(fields[2], "/") {
+			if strings.HasSuffix(name, ".service") {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// restartProcesses restarts the killed processes with their snapshots and confirms they are running again.
+// The children killed with their parents are left to be started by the parents, and each systemd service
+// is started only once. All the processes are tried even if some of them fail.
+func restartProcesses(snapshots []core.ProcessSnapshot) error {
+	killed := make(map[int]bool)
+	for _, snapshot := range snapshots {
+		killed[snapshot.PID] = true
+	}
+
+	processes, err := listProcesses()
+	if err != nil {
+		return err
+	}
+
+	var errs []string
+	units := make(map[string]bool)
+	for _, snapshot := range snapshots {
+		if killed[snapshot.PPID] {
+			continue
+		}
+		if len(snapshot.Unit) > 0 {
+			if units[snapshot.Unit] {
+				continue
+			}
+			units[snapshot.Unit] = true
+			if err := startUnit(snapshot.Unit); err != nil {
+				errs = append(errs, err.Error())
+			}
+			continue
+		}
+
+		if p, ok := findRestarted(snapshot, processes); ok {
+			log.Info("process is already running", zap.Int("pid", snapshot.PID), zap.Int("new pid", p.pid))
+			continue
+		}
+		if err := startProcess(snapshot); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Errorf("restart processes: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// findRestarted returns the running process started in the same way as the snapshot, such as the process
+// itself if it survived the signal, or the process restarted by its supervisor or a previous recovery.
+func findRestarted(snapshot core.ProcessSnapshot, processes []processInfo) (processInfo, bool) {
+	cmdline := strings.Join(snapshot.Args, " ")
+	uid := fmt.Sprint(snapshot.UID)
+	for _, p := range processes {
+		if p.cmdline == cmdline && p.uid == uid {
+			return p, true
+		}
+	}
+	return processInfo{}, false
+}
+
+func startUnit(unit string) error {
+	if output, err := exec.Command("systemctl", "start", unit).CombinedOutput(); err != nil {
+		return errors.Annotatef(err, "start %s: %s", unit, strings.TrimSpace(string(output)))
+	}
+	if output, err := exec.Command("systemctl", "is-active", unit).CombinedOutput(); err != nil {
+		return errors.Errorf("%s is %s after started", unit, strings.TrimSpace(string(output)))
+	}
+
+	log.Info("start systemd service successfully", zap.String("unit", unit))
+	return nil
+}
+
+// startProcess starts the process as it was started before, in a new session so that it is not killed with chaosd.
+func startProcess(snapshot core.ProcessSnapshot) error {
+	cmd := exec.Command(snapshot.Exe) // #nosec
+	cmd.Args = snapshot.Args
+	cmd.Dir = snapshot.Cwd
+	cmd.Env = loadProcessEnv(snapshot)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if int(snapshot.UID) != os.Getuid() || int(snapshot.GID) != os.Getgid() {
+		cmd.SysProcAttr.Credential = &syscall.Credential{Uid: snapshot.UID, Gid: snapshot.GID}
+	}
+
+	pid, err := startAndConfirm(cmd)
+	if err != nil {
+		return errors.Annotatef(err, "restart process %d (%s)", snapshot.PID, snapshot.Exe)
+	}
+	if pid == 0 {
+		// the process exited successfully, it should be a daemon which forks itself
+		processes, err := listProcesses()
+		if err != nil {
+			return err
+		}
+		p, ok := findRestarted(snapshot, processes)
+		if !ok {
+			return errors.Errorf("restart process %d (%s): it exited and is not running", snapshot.PID, snapshot.Exe)
+		}
+		pid = p.pid
+	}
+
+	log.Info("restart process successfully", zap.Int("pid", snapshot.PID), zap.Int("new pid", pid),
+		zap.String("exe", snapshot.Exe))
+	if len(snapshot.EnvFile) > 0 {
+		if err := os.Remove(snapshot.EnvFile); err != nil && !os.IsNotExist(err) {
+			log.Warn("failed to remove the environment file of process", zap.String("file", snapshot.EnvFile), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// processEnv returns the minimal environment of the process restarted for the user, it is used when the environment
+// of the killed process is not captured.
+func processEnv(uid uint32) []string {
+	env := []string{"PATH=" + defaultPath}
+	if u, err := user.LookupId(strconv.Itoa(int(uid))); err == nil {
+		env = append(env, "HOME="+u.HomeDir, "USER="+u.Username, "LOGNAME="+u.Username)
+	}
+	return env
+}
+
+// startAndConfirm starts the command and waits processStartCheckPeriod for it. It returns the pid of the command
+// if it is still running, or 0 if it exited successfully in the period. The running command is waited in background,
+// so that it does not become a zombie when it exits.
+func startAndConfirm(cmd *exec.Cmd) (int, error) {
+	if err := cmd.Start(); err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-exited:
+		if err != nil {
+			return 0, errors.Annotate(err, "exited")
+		}
+		return 0, nil
+	case <-time.After(processStartCheckPeriod):
+		return cmd.Process.Pid, nil
+	}
+}
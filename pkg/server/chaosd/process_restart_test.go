@@ -0,0 +1,124 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+)
+
+func TestSystemdUnitOf(t *testing.T) {
+	assert.Equal(t, "nginx.service", systemdUnitOf("0::/system.slice/nginx.service\n"))
+	assert.Equal(t, "nginx.service", systemdUnitOf("12:cpu,cpuacct:/\n1:name=systemd:/system.slice/nginx.service\n"))
+	assert.Equal(t, "", systemdUnitOf("0::/user.slice/user-1000.slice/user@1000.service/app.slice/app.service\n"))
+	assert.Equal(t, "", systemdUnitOf("0::/user.slice/user-1000.slice/session-2.scope\n"))
+	assert.Equal(t, "", systemdUnitOf("0::/\n"))
+}
+
+func TestProcessAttack_RestartKilled(t *testing.T) {
+	period := processStartCheckPeriod
+	processStartCheckPeriod = 100 * time.Millisecond
+	defer func() { processStartCheckPeriod = period }()
+	envDir := processEnvDir
+	dir := t.TempDir()
+	processEnvDir = func() string { return dir }
+	defer func() { processEnvDir = envDir }()
+
+	cmd := exec.Command("sleep", "600")
+	assert.NoError(t, cmd.Start())
+	go cmd.Wait()
+
+	snapshot, err := captureProcess(cmd.Process.Pid)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sleep", "600"}, snapshot.Args)
+	assert.Equal(t, os.Getpid(), snapshot.PPID)
+	wd, _ := os.Getwd()
+	assert.Equal(t, wd, snapshot.Cwd)
+	// the environment may contain secrets, so it is saved in a file only root can read instead of the experiment
+	command := core.ProcessCommand{Snapshots: []core.ProcessSnapshot{snapshot}}
+	assert.NotContains(t, command.RecoverData(), "PATH=")
+	info, err := os.Stat(snapshot.EnvFile)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	assert.ElementsMatch(t, os.Environ(), loadProcessEnv(snapshot))
+	assert.Contains(t, loadProcessEnv(core.ProcessSnapshot{UID: uint32(os.Getuid())}), "PATH="+defaultPath)
+	// the test may run in a systemd service, which should not be started
+	snapshot.Unit = ""
+
+	// the process is still running, so it is not started again
+	processes, err := listProcesses()
+	assert.NoError(t, err)
+	restarted, ok := findRestarted(snapshot, processes)
+	assert.True(t, ok)
+	assert.Equal(t, cmd.Process.Pid, restarted.pid)
+
+	assert.NoError(t, syscall.Kill(cmd.Process.Pid, syscall.SIGKILL))
+	time.Sleep(50 * time.Millisecond)
+	snapshot.Args = []string{"sleep", "601"}
+	assert.NoError(t, restartProcesses([]core.ProcessSnapshot{snapshot}))
+	// the environment file is removed once the process is restarted
+	assert.NoFileExists(t, snapshot.EnvFile)
+
+	processes, err = listProcesses()
+	assert.NoError(t, err)
+	restarted, ok = findRestarted(snapshot, processes)
+	assert.True(t, ok)
+	assert.NoError(t, syscall.Kill(restarted.pid, syscall.SIGKILL))
+
+	// the children killed with their parents are left to the parents
+	child := snapshot
+	child.PID, child.PPID, child.Args = 2, 1, []string{"sleep", "602"}
+	parent := snapshot
+	parent.PID, parent.Exe, parent.Args = 1, "/bin/false", []string{"false"}
+	err = restartProcesses([]core.ProcessSnapshot{parent, child})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "restart process 1")
+	assert.NotContains(t, err.Error(), "restart process 2")
+}
+
+func TestSignalProcesses_Snapshots(t *testing.T) {
+	envDir := processEnvDir
+	dir := t.TempDir()
+	processEnvDir = func() string { return dir }
+	defer func() { processEnvDir = envDir }()
+
+	start := func() processInfo {
+		cmd := exec.Command("sleep", "600")
+		assert.NoError(t, cmd.Start())
+		go cmd.Wait()
+		return processInfo{pid: cmd.Process.Pid}
+	}
+
+	// the processes keep running with SIGSTOP, so they are not restarted on recovery
+	stopped := start()
+	attack := &core.ProcessCommand{Signal: int(syscall.SIGSTOP)}
+	assert.NoError(t, signalProcesses(attack, []processInfo{stopped}))
+	assert.Equal(t, []int{stopped.pid}, attack.PIDs)
+	assert.Empty(t, attack.Snapshots)
+	assert.NoError(t, syscall.Kill(stopped.pid, syscall.SIGKILL))
+
+	killed := start()
+	attack = &core.ProcessCommand{Signal: int(syscall.SIGKILL)}
+	assert.NoError(t, signalProcesses(attack, []processInfo{killed}))
+	assert.Equal(t, []int{killed.pid}, attack.PIDs)
+	assert.Len(t, attack.Snapshots, 1)
+	assert.Equal(t, killed.pid, attack.Snapshots[0].PID)
+}
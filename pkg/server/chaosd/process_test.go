@@ -0,0 +1,148 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+)
+
+func TestSelectProcesses(t *testing.T) {
+	pid := chaosdPID
+	chaosdPID = func() int { return 99 }
+	defer func() { chaosdPID = pid }()
+
+	processes := []processInfo{
+		{pid: 1, ppid: 0, name: "systemd", cmdline: "/sbin/init", uid: "0", createTime: 100},
+		{pid: 10, ppid: 1, name: "java", cmdline: "java -jar /opt/order-service.jar", uid: "1000", createTime: 200},
+		{pid: 11, ppid: 10, name: "java", cmdline: "java -cp worker.jar Worker", uid: "1000", createTime: 300},
+		{pid: 12, ppid: 11, name: "sh", cmdline: "sh -c sleep 100", uid: "1000", createTime: 400},
+		{pid: 20, ppid: 1, name: "java", cmdline: "java -jar /opt/payment-service.jar", uid: "0", createTime: 250},
+		{pid: 30, ppid: 1, name: "python3", cmdline: "python3 app.py", uid: "1000", createTime: 150},
+	}
+
+	for _, test := range []struct {
+		name    string
+		attack  core.ProcessCommand
+		pids    []int
+		invalid bool
+	}{
+		{name: "name", attack: core.ProcessCommand{Process: "java"}, pids: []int{10, 11, 20}},
+		{name: "pid", attack: core.ProcessCommand{Process: "30"}, pids: []int{30}},
+		{name: "cmdline", attack: core.ProcessCommand{Cmdline: "order-service"}, pids: []int{10}},
+		{name: "exact cmdline", attack: core.ProcessCommand{Cmdline: "java -jar", Exact: true}, pids: nil},
+		{name: "exact cmdline matched", attack: core.ProcessCommand{Cmdline: "java -jar .*", Exact: true}, pids: []int{10, 20}},
+		{name: "parent pid", attack: core.ProcessCommand{ParentPID: 1, Process: "java"}, pids: []int{10, 20}},
+		{name: "user", attack: core.ProcessCommand{Cmdline: "^java ", User: "1000"}, pids: []int{10, 11}},
+		{name: "user name", attack: core.ProcessCommand{Process: "java", User: "root"}, pids: []int{20}},
+		{name: "newest", attack: core.ProcessCommand{Process: "java", Newest: true}, pids: []int{11}},
+		{name: "oldest", attack: core.ProcessCommand{Process: "java", Oldest: true}, pids: []int{10}},
+		{name: "kill children", attack: core.ProcessCommand{Cmdline: "order-service", KillChildren: true}, pids: []int{10, 11, 12}},
+		{name: "invalid cmdline", attack: core.ProcessCommand{Cmdline: "java ("}, invalid: true},
+		{name: "unknown user", attack: core.ProcessCommand{User: "no-such-user"}, invalid: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			matched, err := selectProcesses(&test.attack, processes)
+			if test.invalid {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			var pids []int
+			for _, p := range chooseProcesses(&test.attack, matched, processes) {
+				pids = append(pids, p.pid)
+			}
+			assert.Equal(t, test.pids, pids)
+		})
+	}
+}
+
+func TestSelectProcesses_SkipChaosd(t *testing.T) {
+	pid := chaosdPID
+	chaosdPID = func() int { return 102 }
+	defer func() { chaosdPID = pid }()
+
+	processes := []processInfo{
+		{pid: 1, ppid: 0, name: "systemd", cmdline: "/sbin/init", uid: "0"},
+		{pid: 10, ppid: 1, name: "java", cmdline: "java -jar /opt/order-service.jar", uid: "0"},
+		{pid: 100, ppid: 1, name: "sshd", cmdline: "sshd: root@pts/0", uid: "0"},
+		{pid: 101, ppid: 100, name: "bash", cmdline: "-bash", uid: "0"},
+		{pid: 102, ppid: 101, name: "chaosd", cmdline: "chaosd attack process kill --cmdline java -jar .*order-service.jar", uid: "0"},
+		{pid: 103, ppid: 101, name: "bash", cmdline: "bash", uid: "0"},
+	}
+
+	for _, test := range []struct {
+		attack core.ProcessCommand
+		pids   []int
+	}{
+		{attack: core.ProcessCommand{Cmdline: "java -jar .*order-service.jar"}, pids: []int{10}},
+		{attack: core.ProcessCommand{Process: "bash"}, pids: []int{103}},
+		{attack: core.ProcessCommand{Process: "102"}, pids: nil},
+		{attack: core.ProcessCommand{User: "0", KillChildren: true}, pids: []int{10, 103}},
+	} {
+		matched, err := selectProcesses(&test.attack, processes)
+		assert.NoError(t, err)
+		var pids []int
+		for _, p := range chooseProcesses(&test.attack, matched, processes) {
+			pids = append(pids, p.pid)
+		}
+		assert.Equal(t, test.pids, pids, test.attack.Selector())
+	}
+}
+
+func TestChooseProcesses(t *testing.T) {
+	var processes []processInfo
+	for pid := 1; pid <= 10; pid++ {
+		processes = append(processes, processInfo{pid: pid, ppid: 0, name: "nginx"})
+	}
+	processes = append(processes, processInfo{pid: 11, ppid: 1, name: "sh"})
+
+	for _, test := range []struct {
+		mode  string
+		value string
+		count int
+	}{
+		{mode: "", count: 10},
+		{mode: core.OneMode, count: 1},
+		{mode: core.FixedMode, value: "3", count: 3},
+		{mode: core.FixedMode, value: "20", count: 10},
+		{mode: core.FixedPercentMode, value: "30", count: 3},
+		{mode: core.FixedPercentMode, value: "5", count: 1},
+	} {
+		attack := &core.ProcessCommand{Process: "nginx", Mode: test.mode, Value: test.value}
+		matched, err := selectProcesses(attack, processes)
+		assert.NoError(t, err)
+		assert.Len(t, matched, 10)
+
+		chosen := chooseProcesses(attack, matched, processes)
+		assert.Len(t, chosen, test.count, "mode %s %s", test.mode, test.value)
+		for i := 1; i < len(chosen); i++ {
+			assert.Less(t, chosen[i-1].pid, chosen[i].pid)
+		}
+	}
+
+	attack := &core.ProcessCommand{Process: "nginx", Mode: core.RandomMaxPercentMode, Value: "50"}
+	for i := 0; i < 20; i++ {
+		chosen := chooseProcesses(attack, processes[:10], processes)
+		assert.True(t, len(chosen) >= 1 && len(chosen) <= 5)
+	}
+
+	// the children follow the chosen process
+	attack = &core.ProcessCommand{Process: "1", Mode: core.OneMode, KillChildren: true}
+	chosen := chooseProcesses(attack, processes[:1], processes)
+	assert.Equal(t, []int{1, 11}, []int{chosen[0].pid, chosen[1].pid})
+}
@@ -15,11 +15,13 @@ package chaosd
 
 import (
 	"context"
+	"sync"
 
 	"github.com/joomcode/errorx"
 	"github.com/pingcap/log"
 	perr "github.com/pkg/errors"
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/chaos-mesh/chaosd/pkg/core"
 )
@@ -34,53 +36,35 @@ func (s *Server) RecoverAttack(uid string) error {
 		return perr.Errorf("experiment %s not found", uid)
 	}
 
-	if exp.Status != core.Success && exp.Status != core.Scheduled {
+	if !isActiveExperiment(exp) {
 		return perr.Errorf("can not recover %s experiment", exp.Status)
 	}
 
+	s.stopDeadlineTimer(uid)
+
 	attemptRecovery := true
+	if exp.Status == core.Paused {
+		// the fault has been lifted when the experiment is paused
+		attemptRecovery = false
+		if options, err := exp.GetRequestCommand(); err == nil && len(options.Cron()) > 0 {
+			if err = s.removeScheduledAttack(exp); err != nil {
+				return err
+			}
+		}
+	}
 	if exp.Status == core.Scheduled {
-		if err = s.Cron.Remove(exp.ID); err != nil {
-			return perr.WithMessage(err, "failed to remove scheduled task")
+		// the faults of the scheduled attacks are injected by the runs,
+		// so only the run waiting for recovery is recovered
+		if err = s.removeScheduledAttack(exp); err != nil {
+			return err
 		}
-		// it makes sense to not execute recovery for scheduled attacks
-		// by their nature, each run would recover on its own after the given duration
 		attemptRecovery = false
 	}
 
 	if attemptRecovery {
-		var attackType AttackType
-		switch exp.Kind {
-		case core.ProcessAttack:
-			attackType = ProcessAttack
-		case core.NetworkAttack:
-			attackType = NetworkAttack
-		case core.HostAttack:
-			attackType = HostAttack
-		case core.StressAttack:
-			attackType = StressAttack
-		case core.DiskAttack:
-			attackType = DiskAttack
-		case core.DiskServerAttack:
-			attackType = DiskServerAttack
-		case core.JVMAttack:
-			attackType = JVMAttack
-		case core.ClockAttack:
-			attackType = ClockAttack
-		case core.KafkaAttack:
-			attackType = KafkaAttack
-		case core.RedisAttack:
-			attackType = RedisAttack
-		case core.FileAttack:
-			attackType = FileAttack
-		case core.HTTPAttack:
-			attackType = HTTPAttack
-		case core.VMAttack:
-			attackType = VMAttack
-		case core.UserDefinedAttack:
-			attackType = UserDefinedAttack
-		default:
-			return perr.Errorf("chaos experiment kind %s not found", exp.Kind)
+		attackType, err := getAttackType(exp.Kind)
+		if err != nil {
+			return err
 		}
 
 		env := s.newEnvironment(uid)
@@ -93,8 +77,184 @@ func (s *Server) RecoverAttack(uid string) error {
 		}
 	}
 
-	if err := s.expStore.Update(context.Background(), uid, core.Destroyed, "", exp.RecoverCommand); err != nil {
+	message := ""
+	if options, err := exp.GetRequestCommand(); err == nil {
+		if result := checkProbes(options.GetProbes()); result != nil {
+			log.Warn("steady state is not restored after recovery", zap.String("uid", uid), zap.String("probe", result.Probe))
+			message = "steady state is not restored after recovery, " + result.String()
+		}
+	}
+	if err := s.expStore.Update(context.Background(), uid, core.Destroyed, message, exp.RecoverCommand); err != nil {
 		return perr.WithStack(err)
 	}
 	return nil
 }
+
+// removeScheduledAttack removes the cron job of the experiment and recovers its run waiting for recovery at once.
+// The runs which are not recovered by the scheduler of this process, such as the ones of a chaosd server
+// which has exited, are recovered from the records in the DB.
+func (s *Server) removeScheduledAttack(exp *core.Experiment) error {
+	if err := s.Cron.Unschedule(exp.ID); err != nil {
+		return perr.WithMessagef(err, "Recover experiment %s failed", exp.Uid)
+	}
+	return s.recoverStoredRuns(exp)
+}
+
+// recoverStoredRuns recovers the runs of the scheduled experiment which are waiting for recovery in the DB.
+func (s *Server) recoverStoredRuns(exp *core.Experiment) error {
+	options, err := exp.GetRequestCommand()
+	if err != nil {
+		return err
+	}
+	if duration, err := options.ScheduleDuration(); err != nil || duration == nil {
+		// the run without duration is never recovered
+		return nil
+	}
+
+	runs, err := s.ExpRun.ListByExperimentID(context.Background(), exp.ID)
+	if err != nil {
+		return perr.WithStack(err)
+	}
+	for _, run := range runs {
+		if run.Status != core.RunStarted && run.Status != core.RunSuccess {
+			continue
+		}
+
+		attackType, err := getAttackType(exp.Kind)
+		if err != nil {
+			return err
+		}
+		log.Info("recovering attack on exp run", zap.String("uid", exp.Uid), zap.String("expRunUID", run.UID))
+		if err := scheduledRecoverFunc(attackType, exp, s.newEnvironment(exp.Uid))(run.RecoverCommand); err != nil {
+			return perr.WithMessagef(err, "Recover experiment %s failed, exp run %s is not recovered", exp.Uid, run.UID)
+		}
+		if err := s.ExpRun.Update(context.Background(), run.UID, core.RunRecovered, ""); err != nil {
+			return perr.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// DefaultRecoverWorkers is the default number of experiments recovered at the same time by RecoverExperiments.
+const DefaultRecoverWorkers = 8
+
+// RecoverFilter selects the active experiments to recover, the empty fields match all the experiments.
+type RecoverFilter struct {
+	Kind       string
+	LaunchMode string
+	// Selector is a label selector, such as "team=payments".
+	Selector string
+}
+
+// RecoverResult is the result of recovering one of the selected experiments,
+// Error is empty if the experiment is recovered successfully.
+type RecoverResult struct {
+	UID   string `json:"uid"`
+	Kind  string `json:"kind"`
+	Error string `json:"error,omitempty"`
+}
+
+// RecoverExperiments recovers all the active experiments matching the filter with at most workers
+// experiments recovered at the same time. It goes on when some of them fail to recover,
+// the results are in the order of the creation time of the experiments.
+func (s *Server) RecoverExperiments(filter RecoverFilter, workers int) ([]RecoverResult, error) {
+	exps, err := s.listActiveExperiments(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if workers <= 0 {
+		workers = DefaultRecoverWorkers
+	}
+
+	results := make([]RecoverResult, len(exps))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers && i < len(exps); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = RecoverResult{UID: exps[i].Uid, Kind: exps[i].Kind}
+				if err := s.RecoverAttack(exps[i].Uid); err != nil {
+					results[i].Error = err.Error()
+				}
+			}
+		}()
+	}
+	for i := range exps {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results, nil
+}
+
+func (s *Server) listActiveExperiments(filter RecoverFilter) ([]*core.Experiment, error) {
+	var selector labels.Selector
+	if len(filter.Selector) > 0 {
+		var err error
+		if selector, err = core.ParseSelector(filter.Selector); err != nil {
+			return nil, core.ErrAttackConfigValidation.Wrap(err, "invalid selector")
+		}
+	}
+
+	exps, err := s.expStore.ListByConditions(context.Background(), &core.SearchCommand{All: true, Asc: true})
+	if err != nil {
+		return nil, perr.WithStack(err)
+	}
+
+	active := make([]*core.Experiment, 0, len(exps))
+	for _, exp := range exps {
+		if !isActiveExperiment(exp) ||
+			(len(filter.Kind) > 0 && exp.Kind != filter.Kind) ||
+			(len(filter.LaunchMode) > 0 && exp.LaunchMode != filter.LaunchMode) ||
+			(selector != nil && !exp.Labels.Matches(selector)) {
+			continue
+		}
+		active = append(active, exp)
+	}
+	return active, nil
+}
+
+// listLiveExperiments returns the active experiments and the experiments being created.
+func (s *Server) listLiveExperiments() ([]*core.Experiment, error) {
+	exps, err := s.expStore.ListByConditions(context.Background(), &core.SearchCommand{All: true, Asc: true})
+	if err != nil {
+		return nil, perr.WithStack(err)
+	}
+
+	live := make([]*core.Experiment, 0, len(exps))
+	for _, exp := range exps {
+		if isActiveExperiment(exp) || exp.Status == core.Created {
+			live = append(live, exp)
+		}
+	}
+	return live, nil
+}
+
+// isActiveExperiment returns true if the experiment is running, scheduled or paused, which can be recovered.
+func isActiveExperiment(exp *core.Experiment) bool {
+	return exp.Status == core.Success || exp.Status == core.Scheduled || exp.Status == core.Paused
+}
+
+// abortAttack recovers the experiment before its end, such as when its probe fails,
+// and marks it as aborted with the reason. The experiment is marked as error if it fails to recover.
+func (s *Server) abortAttack(uid string, reason string) {
+	exp, err := s.expStore.FindByUid(context.Background(), uid)
+	if err != nil {
+		log.Error("failed to find experiment", zap.String("uid", uid), zap.Error(err))
+		return
+	}
+
+	log.Warn("aborting experiment", zap.String("uid", uid), zap.String("reason", reason))
+	status, message := core.Aborted, "aborted, "+reason
+	if err := s.RecoverAttack(uid); err != nil {
+		log.Error("failed to recover experiment on abort", zap.String("uid", uid), zap.Error(err))
+		status, message = core.Error, message+", but failed to recover: "+err.Error()
+	}
+	if err := s.expStore.Update(context.Background(), uid, status, message, exp.RecoverCommand); err != nil {
+		log.Error("failed to update experiment", zap.String("uid", uid), zap.Error(err))
+	}
+}
@@ -0,0 +1,295 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+	"github.com/chaos-mesh/chaosd/pkg/scheduler"
+)
+
+type fakeExpStore struct {
+	core.ExperimentStore
+	sync.Mutex
+	exps []*core.Experiment
+}
+
+func (s *fakeExpStore) ListByConditions(_ context.Context, _ *core.SearchCommand) ([]*core.Experiment, error) {
+	s.Lock()
+	defer s.Unlock()
+	exps := make([]*core.Experiment, 0, len(s.exps))
+	for _, exp := range s.exps {
+		copied := *exp
+		exps = append(exps, &copied)
+	}
+	return exps, nil
+}
+
+func (s *fakeExpStore) ListByStatus(_ context.Context, status string) ([]*core.Experiment, error) {
+	s.Lock()
+	defer s.Unlock()
+	var exps []*core.Experiment
+	for _, exp := range s.exps {
+		if exp.Status == status {
+			copied := *exp
+			exps = append(exps, &copied)
+		}
+	}
+	return exps, nil
+}
+
+func (s *fakeExpStore) FindByUid(_ context.Context, uid string) (*core.Experiment, error) {
+	s.Lock()
+	defer s.Unlock()
+	for _, exp := range s.exps {
+		if exp.Uid == uid {
+			copied := *exp
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (s *fakeExpStore) Set(_ context.Context, exp *core.Experiment) error {
+	s.Lock()
+	defer s.Unlock()
+	for i := range s.exps {
+		if s.exps[i].Uid == exp.Uid {
+			copied := *exp
+			s.exps[i] = &copied
+			return nil
+		}
+	}
+	exp.ID = uint(len(s.exps) + 1)
+	copied := *exp
+	s.exps = append(s.exps, &copied)
+	return nil
+}
+
+func (s *fakeExpStore) Update(_ context.Context, uid, status, msg string, command string) error {
+	s.Lock()
+	defer s.Unlock()
+	for _, exp := range s.exps {
+		if exp.Uid == uid {
+			// the empty status and command are not written like the zero fields in the DB
+			if len(status) > 0 {
+				exp.Status = status
+			}
+			exp.Message = msg
+			if len(command) > 0 {
+				exp.RecoverCommand = command
+			}
+		}
+	}
+	return nil
+}
+
+// countingAttack records the recovered experiments and the max number of concurrent recoveries
+type countingAttack struct {
+	sync.Mutex
+	running    int
+	maxRunning int
+	recovered  []string
+	failed     string
+}
+
+func (a *countingAttack) Attack(core.AttackConfig, Environment) error { return nil }
+
+func (a *countingAttack) Recover(exp core.Experiment, _ Environment) error {
+	a.Lock()
+	a.running++
+	if a.running > a.maxRunning {
+		a.maxRunning = a.running
+	}
+	a.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	a.Lock()
+	defer a.Unlock()
+	a.running--
+	if exp.Uid == a.failed {
+		return errors.New("recover failed")
+	}
+	a.recovered = append(a.recovered, exp.Uid)
+	return nil
+}
+
+func TestServer_RecoverExperiments(t *testing.T) {
+	attack := &countingAttack{failed: "exp-3"}
+	RegisterAttackKind(AttackKind{
+		Kind:      "recover-test",
+		NewConfig: func() core.AttackConfig { return &core.ProcessCommand{} },
+		Attack:    attack,
+	})
+
+	store := &fakeExpStore{}
+	for i, status := range []string{core.Success, core.Success, core.Destroyed, core.Success, core.Success, core.Success} {
+		exp := &core.Experiment{
+			Uid:        "exp-" + string(rune('0'+i)),
+			Kind:       "recover-test",
+			Status:     status,
+			LaunchMode: core.ServerMode,
+		}
+		if i%2 == 0 {
+			exp.Labels = core.Labels{"team": "payments"}
+		}
+		store.exps = append(store.exps, exp)
+	}
+	store.exps[5].LaunchMode = core.CommandMode
+	s := &Server{expStore: store, deadlineTimers: make(map[string]*time.Timer)}
+
+	results, err := s.RecoverExperiments(RecoverFilter{Selector: "team=payments"}, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []RecoverResult{{UID: "exp-0", Kind: "recover-test"}, {UID: "exp-4", Kind: "recover-test"}}, results)
+
+	results, err = s.RecoverExperiments(RecoverFilter{Kind: "recover-test", LaunchMode: core.ServerMode}, 2)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, "exp-1", results[0].UID)
+	assert.Empty(t, results[0].Error)
+	assert.Equal(t, "exp-3", results[1].UID)
+	assert.Contains(t, results[1].Error, "recover failed")
+	assert.LessOrEqual(t, attack.maxRunning, 2)
+
+	results, err = s.RecoverExperiments(RecoverFilter{}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"exp-3", "exp-5"}, []string{results[0].UID, results[1].UID})
+	assert.ElementsMatch(t, []string{"exp-0", "exp-4", "exp-1", "exp-5"}, attack.recovered)
+
+	_, err = s.RecoverExperiments(RecoverFilter{Selector: "team in (payments"}, 1)
+	assert.Error(t, err)
+}
+
+type fakeRunStore struct {
+	core.ExperimentRunStore
+	sync.Mutex
+	runs []*core.ExperimentRun
+}
+
+func (s *fakeRunStore) status(runUid string) string {
+	s.Lock()
+	defer s.Unlock()
+	for _, run := range s.runs {
+		if run.UID == runUid {
+			return run.Status
+		}
+	}
+	return ""
+}
+
+func (s *fakeRunStore) ListByExperimentID(_ context.Context, id uint) ([]*core.ExperimentRun, error) {
+	s.Lock()
+	defer s.Unlock()
+	var runs []*core.ExperimentRun
+	for _, run := range s.runs {
+		if run.ExperimentID == id {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
+func (s *fakeRunStore) LatestRun(_ context.Context, id uint) (*core.ExperimentRun, error) {
+	s.Lock()
+	defer s.Unlock()
+	var latest *core.ExperimentRun
+	for _, run := range s.runs {
+		if run.ExperimentID == id {
+			latest = run
+		}
+	}
+	return latest, nil
+}
+
+func (s *fakeRunStore) NewRun(_ context.Context, run *core.ExperimentRun) error {
+	s.Lock()
+	defer s.Unlock()
+	s.runs = append(s.runs, run)
+	return nil
+}
+
+func (s *fakeRunStore) SetRecoverCommand(_ context.Context, runUid string, recoverCommand string) error {
+	s.Lock()
+	defer s.Unlock()
+	for _, run := range s.runs {
+		if run.UID == runUid {
+			run.RecoverCommand = recoverCommand
+		}
+	}
+	return nil
+}
+
+func (s *fakeRunStore) Update(_ context.Context, runUid string, status string, message string) error {
+	s.Lock()
+	defer s.Unlock()
+	for _, run := range s.runs {
+		if run.UID == runUid {
+			run.Status = status
+			run.Message = message
+		}
+	}
+	return nil
+}
+
+func TestServer_RecoverScheduledAttack(t *testing.T) {
+	attack := &countingAttack{failed: "exp-1"}
+	RegisterAttackKind(AttackKind{
+		Kind:      "schedule-recover-test",
+		NewConfig: func() core.AttackConfig { return &core.ProcessCommand{} },
+		Attack:    attack,
+	})
+
+	store := &fakeExpStore{}
+	runStore := &fakeRunStore{}
+	for i := 0; i < 2; i++ {
+		uid := "exp-" + string(rune('0'+i))
+		store.exps = append(store.exps, &core.Experiment{
+			ID:             uint(i + 1),
+			Uid:            uid,
+			Kind:           "schedule-recover-test",
+			Status:         core.Scheduled,
+			RecoverCommand: `{"schedule":"@every 1h","duration":"1h","kind":"schedule-recover-test","process":"sleep"}`,
+		})
+		runStore.runs = append(runStore.runs,
+			&core.ExperimentRun{UID: uid + "-run-0", ExperimentID: uint(i + 1), Status: core.RunRecovered},
+			&core.ExperimentRun{UID: uid + "-run-1", ExperimentID: uint(i + 1), Status: core.RunSuccess})
+	}
+	s := &Server{
+		expStore:       store,
+		ExpRun:         runStore,
+		Cron:           scheduler.NewScheduler(runStore, store),
+		deadlineTimers: make(map[string]*time.Timer),
+	}
+
+	// the run of the experiment scheduled by a chaosd server which has exited is recovered
+	assert.NoError(t, s.RecoverAttack("exp-0"))
+	assert.Equal(t, []string{"exp-0"}, attack.recovered)
+	assert.Equal(t, core.RunRecovered, runStore.runs[1].Status)
+	exp, _ := store.FindByUid(context.Background(), "exp-0")
+	assert.Equal(t, core.Destroyed, exp.Status)
+
+	// the experiment is not destroyed if its run fails to recover
+	assert.Error(t, s.RecoverAttack("exp-1"))
+	assert.Equal(t, core.RunSuccess, runStore.runs[3].Status)
+	exp, _ = store.FindByUid(context.Background(), "exp-1")
+	assert.Equal(t, core.Scheduled, exp.Status)
+}
@@ -0,0 +1,117 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	perr "github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+)
+
+// AttackKind describes everything chaosd needs to know about a kind of attack.
+// Each kind registers itself with RegisterAttackKind, so a new kind can be
+// added by compiling in an extra package which registers it in init.
+type AttackKind struct {
+	// Kind is the kind stored in the experiments, such as "process".
+	Kind string
+	// NewConfig creates an empty config of this kind, it is used to decode the stored configs.
+	NewConfig func() core.AttackConfig
+	// Attack executes and recovers the attacks of this kind.
+	Attack AttackType
+	// Command builds the subcommand of `chaosd attack`, the kind has no subcommand if it is nil.
+	// It can be attached later with AttachAttackCommand.
+	Command func(uid *string) *cobra.Command
+	// HTTP binds the kind to the API of chaosd server, the kind can't be created by API if it is nil.
+	HTTP *HTTPBinding
+}
+
+// HTTPBinding describes how an attack is created through the API of chaosd server.
+type HTTPBinding struct {
+	// Path is the path under /api/attack, such as "process".
+	Path string
+	// Bind decodes the request body with decode, completes the defaults
+	// and returns the validated config of the attack.
+	Bind func(decode func(obj interface{}) error) (core.AttackConfig, error)
+}
+
+var (
+	attackKinds     = make(map[string]*AttackKind)
+	attackKindOrder []string
+)
+
+// RegisterAttackKind registers a kind of attack. It should be called in init,
+// it panics if the kind is invalid or has been registered.
+func RegisterAttackKind(kind AttackKind) {
+	if len(kind.Kind) == 0 || kind.NewConfig == nil || kind.Attack == nil {
+		panic("kind, config factory and attack type of an attack kind are required")
+	}
+	if _, ok := attackKinds[kind.Kind]; ok {
+		panic("attack kind " + kind.Kind + " has been registered")
+	}
+
+	core.RegisterAttackConfig(kind.Kind, kind.NewConfig)
+	attackKinds[kind.Kind] = &kind
+	attackKindOrder = append(attackKindOrder, kind.Kind)
+}
+
+// AttachAttackCommand attaches the builder of the subcommand of `chaosd attack` to a registered kind.
+// It should be called in init, it panics if the kind is not registered or has a subcommand.
+func AttachAttackCommand(kind string, command func(uid *string) *cobra.Command) {
+	attackKind, ok := attackKinds[kind]
+	if !ok {
+		panic("attack kind " + kind + " is not registered")
+	}
+	if attackKind.Command != nil {
+		panic("command of attack kind " + kind + " has been attached")
+	}
+	attackKind.Command = command
+}
+
+// GetAttackKinds returns all the registered attack kinds in the order of registration.
+func GetAttackKinds() []*AttackKind {
+	kinds := make([]*AttackKind, 0, len(attackKindOrder))
+	for _, kind := range attackKindOrder {
+		kinds = append(kinds, attackKinds[kind])
+	}
+	return kinds
+}
+
+func getAttackType(kind string) (AttackType, error) {
+	attackKind, ok := attackKinds[kind]
+	if !ok {
+		return nil, perr.Errorf("chaos experiment kind %s not found", kind)
+	}
+	return attackKind.Attack, nil
+}
+
+// NewHTTPBinding returns the binding which decodes the request body into the config created by newConfig,
+// then completes its defaults and validates it. It fits the most kinds of attacks.
+func NewHTTPBinding(path string, newConfig func() core.AttackConfig) *HTTPBinding {
+	return &HTTPBinding{
+		Path: path,
+		Bind: func(decode func(obj interface{}) error) (core.AttackConfig, error) {
+			options := newConfig()
+			if err := decode(options); err != nil {
+				return nil, err
+			}
+
+			options.CompleteDefaults()
+			if err := options.Validate(); err != nil {
+				return nil, err
+			}
+			return options, nil
+		},
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+)
+
+func TestRegisterAttackKind(t *testing.T) {
+	kind := AttackKind{
+		Kind:      "registry-test",
+		NewConfig: func() core.AttackConfig { return &core.ProcessCommand{} },
+		Attack:    ProcessAttack,
+	}
+	RegisterAttackKind(kind)
+
+	attackType, err := getAttackType("registry-test")
+	assert.NoError(t, err)
+	assert.Equal(t, ProcessAttack, attackType)
+	assert.NotNil(t, core.GetAttackByKind("registry-test"))
+	kinds := GetAttackKinds()
+	assert.Equal(t, "registry-test", kinds[len(kinds)-1].Kind)
+
+	_, err = getAttackType("not-registered")
+	assert.Error(t, err)
+
+	assert.Panics(t, func() { RegisterAttackKind(kind) })
+	assert.Panics(t, func() { RegisterAttackKind(AttackKind{Kind: "no-attack-type"}) })
+	assert.Panics(t, func() { RegisterAttackKind(AttackKind{Kind: "no-config", Attack: ProcessAttack}) })
+
+	command := func(*string) *cobra.Command { return &cobra.Command{Use: "registry-test"} }
+	AttachAttackCommand("registry-test", command)
+	assert.NotNil(t, attackKinds["registry-test"].Command)
+	assert.Panics(t, func() { AttachAttackCommand("registry-test", command) })
+	assert.Panics(t, func() { AttachAttackCommand("not-registered", command) })
+}
+
+func TestBuiltinAttackKinds(t *testing.T) {
+	// the configs of the built-in kinds can be decoded without the cmd packages
+	for _, kind := range []string{
+		core.ProcessAttack, core.NetworkAttack, core.HostAttack, core.StressAttack, core.DiskAttack,
+		core.DiskServerAttack, core.JVMAttack, core.ClockAttack, core.KafkaAttack, core.RedisAttack,
+		core.FileAttack, core.HTTPAttack, core.VMAttack, core.UserDefinedAttack, core.SystemdAttack,
+		core.ContainerAttack,
+	} {
+		_, err := getAttackType(kind)
+		assert.NoError(t, err, kind)
+		assert.NotNil(t, core.GetAttackByKind(kind), kind)
+	}
+}
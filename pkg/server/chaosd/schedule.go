@@ -0,0 +1,248 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/joomcode/errorx"
+	"github.com/pingcap/log"
+	perr "github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+	"github.com/chaos-mesh/chaosd/pkg/scheduler"
+)
+
+// RestoreScheduledAttacks registers the scheduled experiments stored in the DB to the scheduler again,
+// it is called when chaosd server starts.
+func (s *Server) RestoreScheduledAttacks() error {
+	if err := s.checkHalted(); err != nil {
+		log.Warn("scheduled experiments are not restored", zap.Error(err))
+		return nil
+	}
+	return s.Cron.Restore(s.buildCronJobFuncs)
+}
+
+func (s *Server) buildCronJobFuncs(exp *core.Experiment) (scheduler.AttackFunc, scheduler.RecoverFunc, error) {
+	attackType, err := getAttackType(exp.Kind)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	env := s.newEnvironment(exp.Uid)
+	return s.scheduledAttackFunc(attackType, exp, env), scheduledRecoverFunc(attackType, exp, env), nil
+}
+
+// scheduledAttackFunc returns the func executed by every run of a scheduled attack. The run fails if chaosd is halted
+// or the probes of the attack fail before it is injected, and the probes are checked while the run is in progress.
+// A probe failing during the run aborts only this run, the experiment stays scheduled for its next runs.
+// Each run attacks with its own copy of the config of the experiment, so that the runtime state of the runs,
+// such as the pid of the stress process, is kept apart, and the run is recovered with its own state.
+func (s *Server) scheduledAttackFunc(attackType AttackType, exp *core.Experiment, env Environment) scheduler.AttackFunc {
+	return func(runUid string) (string, error) {
+		if err := s.checkHalted(); err != nil {
+			return "", err
+		}
+
+		runExp := exp.WithRecoverCommand(exp.RecoverCommand)
+		options, err := runExp.GetRequestCommand()
+		if err != nil {
+			return "", err
+		}
+		if result := checkProbes(options.GetProbes()); result != nil {
+			return "", perr.Errorf("steady state is not met before injection, %s", result)
+		}
+
+		if err := attackType.Attack(options, env); err != nil {
+			return "", err
+		}
+
+		// a run without duration is never recovered by the scheduler, so its probes are not checked
+		if duration, err := options.ScheduleDuration(); err == nil && duration != nil && *duration > 0 && len(options.GetProbes()) > 0 {
+			s.watchProbesWith(env.AttackUid, options.GetProbes(), *duration, func(reason string) {
+				if err := s.Cron.AbortRun(exp.ID, runUid, reason); err != nil {
+					log.Error("failed to abort the run", zap.String("uid", exp.Uid), zap.String("run", runUid), zap.Error(err))
+				}
+			})
+		}
+		return options.RecoverData(), nil
+	}
+}
+
+// scheduledRecoverFunc returns the func recovering a run of a scheduled attack with the config of the run,
+// the runs without their own config are recovered with the config of the experiment.
+func scheduledRecoverFunc(attackType AttackType, exp *core.Experiment, env Environment) scheduler.RecoverFunc {
+	return func(recoverData string) error {
+		if len(recoverData) == 0 {
+			recoverData = exp.RecoverCommand
+		}
+		return attackType.Recover(exp.WithRecoverCommand(recoverData), env)
+	}
+}
+
+// ScheduleInfo describes a scheduled experiment and the state of its cron job.
+type ScheduleInfo struct {
+	UID               string `json:"uid"`
+	Kind              string `json:"kind"`
+	Action            string `json:"action"`
+	Status            string `json:"status"`
+	Schedule          string `json:"schedule"`
+	TimeZone          string `json:"time_zone,omitempty"`
+	Duration          string `json:"duration,omitempty"`
+	ConcurrencyPolicy string `json:"concurrency_policy"`
+	Suspended         bool   `json:"suspended"`
+	// Registered is false if the experiment is not in the scheduler, such as when chaosd is halted.
+	Registered bool       `json:"registered"`
+	NextRun    *time.Time `json:"next_run,omitempty"`
+	PrevRun    *time.Time `json:"prev_run,omitempty"`
+	// LastRun is the latest run of the experiment, including the skipped ones.
+	LastRun *core.ExperimentRun `json:"last_run,omitempty"`
+	// PendingRecovery is true if some runs are waiting for recovery, PendingRuns are their uids.
+	PendingRecovery bool     `json:"pending_recovery"`
+	PendingRuns     []string `json:"pending_runs,omitempty"`
+}
+
+// ListSchedules returns the scheduled and paused experiments which have schedules, in the order of creation time.
+func (s *Server) ListSchedules() ([]*ScheduleInfo, error) {
+	exps, err := s.expStore.ListByConditions(context.Background(), &core.SearchCommand{All: true, Asc: true})
+	if err != nil {
+		return nil, perr.WithStack(err)
+	}
+
+	schedules := make([]*ScheduleInfo, 0, len(exps))
+	for _, exp := range exps {
+		if exp.Status != core.Scheduled && exp.Status != core.Paused {
+			continue
+		}
+		info, err := s.describeSchedule(exp)
+		if err != nil {
+			if errorx.IsOfType(err, core.ErrAttackConfigValidation) {
+				// the paused experiment without schedule
+				continue
+			}
+			return nil, err
+		}
+		schedules = append(schedules, info)
+	}
+	return schedules, nil
+}
+
+// DescribeSchedule returns the schedule of the experiment.
+func (s *Server) DescribeSchedule(uid string) (*ScheduleInfo, error) {
+	exp, err := s.expStore.FindByUid(context.Background(), uid)
+	if err != nil {
+		return nil, err
+	}
+	return s.describeSchedule(exp)
+}
+
+// SuspendSchedule suspends or unsuspends the schedule of the experiment. The suspended schedule starts no new run,
+// but the run in progress is still recovered at the end of its duration. It is recorded in the config of the experiment,
+// so the schedule is kept suspended after chaosd server restarts.
+func (s *Server) SuspendSchedule(uid string, suspend bool) (*ScheduleInfo, error) {
+	exp, err := s.expStore.FindByUid(context.Background(), uid)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.describeSchedule(exp); err != nil {
+		return nil, err
+	}
+
+	options, err := mergeAttackConfig(exp, []byte(fmt.Sprintf(`{"suspend":%t}`, suspend)))
+	if err != nil {
+		return nil, err
+	}
+	before := exp.RecoverCommand
+	updated := exp.WithRecoverCommand(options.RecoverData())
+	exp = &updated
+	// only the config is written back, the empty status and message are not written by Update, since the experiment
+	// may be changed meanwhile, such as paused or aborted by a probe
+	if err := s.expStore.Update(context.Background(), uid, "", "", exp.RecoverCommand); err != nil {
+		return nil, perr.WithStack(err)
+	}
+	// the cron job is changed in place, so that its runs in progress are kept. It is not registered
+	// when chaosd is halted, and the stored config is loaded when it is registered again.
+	_ = s.Cron.Suspend(exp.ID, suspend)
+
+	if err := s.History.Add(context.Background(), &core.ExperimentHistory{
+		ExperimentUID: uid,
+		Event:         core.HistoryUpdated,
+		Before:        before,
+		After:         exp.RecoverCommand,
+	}); err != nil {
+		log.Error("failed to record the history of experiment", zap.String("uid", uid), zap.Error(err))
+	}
+	return s.describeSchedule(exp)
+}
+
+// TriggerSchedule starts an out-of-band run of the scheduled experiment at once, regardless of the windows,
+// the jitter and the suspension of its schedule. The concurrency policy of the schedule is still applied.
+func (s *Server) TriggerSchedule(uid string) (*core.ExperimentRun, error) {
+	if err := s.checkHalted(); err != nil {
+		return nil, err
+	}
+	exp, err := s.expStore.FindByUid(context.Background(), uid)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.describeSchedule(exp); err != nil {
+		return nil, err
+	}
+	if exp.Status != core.Scheduled {
+		return nil, perr.Errorf("can not trigger %s experiment", exp.Status)
+	}
+	return s.Cron.Trigger(exp.ID)
+}
+
+func (s *Server) describeSchedule(exp *core.Experiment) (*ScheduleInfo, error) {
+	options, err := exp.GetRequestCommand()
+	if err != nil {
+		return nil, err
+	}
+	config := options.GetSchedulerConfig()
+	if len(config.Schedule) == 0 || (exp.Status != core.Scheduled && exp.Status != core.Paused) {
+		return nil, core.ErrAttackConfigValidation.New("%s experiment %s is not scheduled", exp.Status, exp.Uid)
+	}
+
+	info := &ScheduleInfo{
+		UID:               exp.Uid,
+		Kind:              exp.Kind,
+		Action:            exp.Action,
+		Status:            exp.Status,
+		Schedule:          config.Schedule,
+		TimeZone:          config.TimeZone,
+		Duration:          config.Duration,
+		ConcurrencyPolicy: config.GetConcurrencyPolicy(),
+		Suspended:         config.Suspend,
+	}
+	if state, err := s.Cron.GetJobState(exp.ID); err == nil {
+		info.Registered = true
+		if !state.Next.IsZero() {
+			info.NextRun = &state.Next
+		}
+		if !state.Prev.IsZero() {
+			info.PrevRun = &state.Prev
+		}
+		info.PendingRecovery = len(state.PendingRuns) > 0
+		info.PendingRuns = state.PendingRuns
+	}
+
+	if info.LastRun, err = s.ExpRun.LatestRun(context.Background(), exp.ID); err != nil {
+		return nil, perr.WithStack(err)
+	}
+	return info, nil
+}
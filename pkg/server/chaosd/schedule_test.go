@@ -0,0 +1,101 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+	"github.com/chaos-mesh/chaosd/pkg/scheduler"
+)
+
+func TestServer_Schedules(t *testing.T) {
+	RegisterAttackKind(AttackKind{
+		Kind:      "schedule-test",
+		NewConfig: func() core.AttackConfig { return &core.ProcessCommand{} },
+		Attack:    &countingAttack{},
+	})
+
+	store := &fakeExpStore{exps: []*core.Experiment{
+		{ID: 1, Uid: "scheduled", Kind: "schedule-test", Status: core.Scheduled,
+			RecoverCommand: `{"schedule":"@every 1h","duration":"1h","time_zone":"Asia/Shanghai","kind":"schedule-test","process":"sleep"}`},
+		{ID: 2, Uid: "running", Kind: "schedule-test", Status: core.Success,
+			RecoverCommand: `{"kind":"schedule-test","process":"sleep"}`},
+		{ID: 3, Uid: "unregistered", Kind: "schedule-test", Status: core.Paused,
+			RecoverCommand: `{"schedule":"@every 1h","duration":"1h","kind":"schedule-test","process":"sleep"}`},
+	}}
+	runStore := &fakeRunStore{}
+	s := &Server{
+		expStore:       store,
+		ExpRun:         runStore,
+		Cron:           scheduler.NewScheduler(runStore, store),
+		History:        &fakeHistoryStore{},
+		haltState:      &fakeHaltStateStore{},
+		deadlineTimers: make(map[string]*time.Timer),
+	}
+	attackFunc, recoverFunc, err := s.buildCronJobFuncs(store.exps[0])
+	assert.NoError(t, err)
+	assert.NoError(t, s.Cron.Schedule(store.exps[0], "@every 1h", attackFunc, recoverFunc))
+	s.Cron.Start()
+	defer s.Cron.Stop()
+
+	schedules, err := s.ListSchedules()
+	assert.NoError(t, err)
+	assert.Len(t, schedules, 2)
+	assert.Equal(t, "scheduled", schedules[0].UID)
+	assert.Equal(t, "Asia/Shanghai", schedules[0].TimeZone)
+	assert.Equal(t, core.ForbidConcurrent, schedules[0].ConcurrencyPolicy)
+	assert.True(t, schedules[0].Registered)
+	assert.NotNil(t, schedules[0].NextRun)
+	assert.Nil(t, schedules[0].LastRun)
+	assert.False(t, schedules[1].Registered)
+
+	_, err = s.DescribeSchedule("running")
+	assert.True(t, errorx.IsOfType(err, core.ErrAttackConfigValidation))
+
+	run, err := s.TriggerSchedule("scheduled")
+	assert.NoError(t, err)
+	assert.Equal(t, core.RunSuccess, run.Status)
+	schedule, err := s.DescribeSchedule("scheduled")
+	assert.NoError(t, err)
+	assert.Equal(t, run.UID, schedule.LastRun.UID)
+	assert.True(t, schedule.PendingRecovery)
+	assert.Equal(t, []string{run.UID}, schedule.PendingRuns)
+
+	// the previous run is not recovered yet
+	_, err = s.TriggerSchedule("scheduled")
+	assert.True(t, errorx.IsOfType(err, core.ErrAttackConflict))
+
+	// the cron job is suspended in place, so the run in progress is kept waiting for recovery
+	schedule, err = s.SuspendSchedule("scheduled", true)
+	assert.NoError(t, err)
+	assert.True(t, schedule.Suspended)
+	assert.Equal(t, []string{run.UID}, schedule.PendingRuns)
+	assert.Contains(t, store.exps[0].RecoverCommand, `"suspend":true`)
+	assert.Equal(t, core.Scheduled, store.exps[0].Status)
+	schedule, err = s.SuspendSchedule("scheduled", false)
+	assert.NoError(t, err)
+	assert.False(t, schedule.Suspended)
+	assert.Equal(t, []string{run.UID}, schedule.PendingRuns)
+
+	_, err = s.SuspendSchedule("running", true)
+	assert.Error(t, err)
+
+	_, err = s.TriggerSchedule("unregistered")
+	assert.Error(t, err)
+}
@@ -14,7 +14,11 @@
 package chaosd
 
 import (
+	"sync"
+	"time"
+
 	"github.com/chaos-mesh/chaos-mesh/pkg/chaosdaemon"
+	"github.com/chaos-mesh/chaos-mesh/pkg/chaosdaemon/crclients"
 
 	"github.com/chaos-mesh/chaosd/pkg/config"
 	"github.com/chaos-mesh/chaosd/pkg/core"
@@ -25,24 +29,48 @@ import (
 type Server struct {
 	expStore     core.ExperimentStore
 	ExpRun       core.ExperimentRunStore
+	History      core.ExperimentHistoryStore
 	Cron         scheduler.Scheduler
 	ipsetRule    core.IPSetRuleStore
 	iptablesRule core.IptablesRuleStore
 	tcRule       core.TCRuleStore
+	haltState    core.HaltStateStore
+	policy       *core.Policy
 	conf         *config.Config
 	svr          *chaosdaemon.DaemonServer
+	// crClient resolves the pid of the network namespace target, it is the client used by svr.
+	crClient crclients.ContainerRuntimeInfoClient
 
 	CmdPools map[string]*utils.CommandPools
+
+	deadlineTimers map[string]*time.Timer
+	deadlineLock   sync.Mutex
+	// serving is true if the current process is chaosd server, which keeps the cron jobs and the deadline timers.
+	serving bool
+
+	// crashLoops are the background tasks of the running crash-loop attacks, the key is the uid of the experiment.
+	crashLoops    map[string]*crashLoop
+	crashLoopLock sync.Mutex
+
+	// networkLock serializes the changes of ipset, iptables and tc rules,
+	// because they are applied with all the rules stored in the DB.
+	networkLock sync.Mutex
+	// policyLock serializes the creation of experiments, so that the limits of the policy can not be exceeded.
+	policyLock sync.Mutex
 }
 
 func NewServer(
 	conf *config.Config,
 	exp core.ExperimentStore,
 	expRun core.ExperimentRunStore,
+	history core.ExperimentHistoryStore,
 	ipset core.IPSetRuleStore,
 	iptables core.IptablesRuleStore,
 	tc core.TCRuleStore,
+	haltState core.HaltStateStore,
+	policy *core.Policy,
 	svr *chaosdaemon.DaemonServer,
+	crClient crclients.ContainerRuntimeInfoClient,
 	cron scheduler.Scheduler,
 ) *Server {
 	return &Server{
@@ -50,10 +78,24 @@ func NewServer(
 		expStore:     exp,
 		Cron:         cron,
 		ExpRun:       expRun,
+		History:      history,
 		ipsetRule:    ipset,
 		iptablesRule: iptables,
 		tcRule:       tc,
+		haltState:    haltState,
+		policy:       policy,
 		svr:          svr,
+		crClient:     crClient,
 		CmdPools:     make(map[string]*utils.CommandPools),
+
+		deadlineTimers: make(map[string]*time.Timer),
+		crashLoops:     make(map[string]*crashLoop),
 	}
 }
+
+// StartServing marks the current process as chaosd server, it should be called before the server
+// accepts any request. The other processes, such as the attack commands, leave the cron jobs and
+// the deadline timers to chaosd server.
+func (s *Server) StartServing() {
+	s.serving = true
+}
@@ -44,8 +44,50 @@ const (
 
 func (stressAttack) Attack(options core.AttackConfig, _ Environment) (err error) {
 	attack := options.(*core.StressCommand)
+	stressorTool, stressorsStr, err := getStressorArgs(attack)
+	if err != nil {
+		return
+	}
+
+	log.Info("stressors normalize", zap.String("arguments", stressorsStr))
+
+	cmd := bpm.DefaultProcessBuilder(stressorTool, strings.Fields(stressorsStr)...).
+		Build(context.Background())
+
+	// Build will set SysProcAttr.Pdeathsig = syscall.SIGTERM, and so stress-ng will exit while chaosd exit
+	// so reset it here
+	cmd.Cmd.SysProcAttr = &syscall.SysProcAttr{}
+
+	zapLogger, err := zap.NewDevelopment()
+	if err != nil {
+		return err
+	}
+	logger := zapr.NewLogger(zapLogger)
+	backgroundProcessManager := bpm.StartBackgroundProcessManager(nil, logger)
+	_, err = backgroundProcessManager.StartProcess(context.Background(), cmd)
+	if err != nil {
+		return
+	}
+
+	attack.StressngPid = int32(cmd.Process.Pid)
+	log.Info(fmt.Sprintf("Start %s process successfully", stressorTool), zap.String("command", cmd.String()), zap.Int32("Pid", attack.StressngPid))
+
+	return nil
+}
+
+func (stressAttack) Plan(options core.AttackConfig, _ Environment) ([]string, error) {
+	attack := options.(*core.StressCommand)
+	stressorTool, stressorsStr, err := getStressorArgs(attack)
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{fmt.Sprintf("run: %s %s", stressorTool, stressorsStr)}, nil
+}
+
+// getStressorArgs returns the stressor tool and its arguments of the attack.
+func getStressorArgs(attack *core.StressCommand) (stressorTool string, stressorsStr string, err error) {
 	stressors := &v1alpha1.Stressors{}
-	var stressorTool string
 
 	if attack.Action == core.StressCPUAction {
 		stressorTool = CPUSTRESSORTOOL
@@ -67,7 +109,6 @@ func (stressAttack) Attack(options core.AttackConfig, _ Environment) (err error)
 		}
 	}
 
-	var stressorsStr string
 	if attack.Action == core.StressCPUAction {
 		stressorsStr, _, err = stressors.Normalize()
 		if err != nil {
@@ -82,33 +123,10 @@ func (stressAttack) Attack(options core.AttackConfig, _ Environment) (err error)
 
 	errs := stressors.Validate(nil, field.NewPath("stressors"))
 	if len(errs) > 0 {
-		return errors.New(errs.ToAggregate().Error())
-	}
-
-	log.Info("stressors normalize", zap.String("arguments", stressorsStr))
-
-	cmd := bpm.DefaultProcessBuilder(stressorTool, strings.Fields(stressorsStr)...).
-		Build(context.Background())
-
-	// Build will set SysProcAttr.Pdeathsig = syscall.SIGTERM, and so stress-ng will exit while chaosd exit
-	// so reset it here
-	cmd.Cmd.SysProcAttr = &syscall.SysProcAttr{}
-
-	zapLogger, err := zap.NewDevelopment()
-	if err != nil {
-		return err
-	}
-	logger := zapr.NewLogger(zapLogger)
-	backgroundProcessManager := bpm.StartBackgroundProcessManager(nil, logger)
-	_, err = backgroundProcessManager.StartProcess(context.Background(), cmd)
-	if err != nil {
+		err = errors.New(errs.ToAggregate().Error())
 		return
 	}
-
-	attack.StressngPid = int32(cmd.Process.Pid)
-	log.Info(fmt.Sprintf("Start %s process successfully", stressorTool), zap.String("command", cmd.String()), zap.Int32("Pid", attack.StressngPid))
-
-	return nil
+	return
 }
 
 func (stressAttack) Recover(exp core.Experiment, _ Environment) error {
@@ -0,0 +1,305 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sddbus "github.com/coreos/go-systemd/v22/dbus"
+	"github.com/godbus/dbus/v5"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"go.uber.org/zap"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+)
+
+// systemdTimeout is how long chaosd waits for systemd to change a unit, such as to stop it.
+const systemdTimeout = 2 * time.Minute
+
+type systemdAttack struct{}
+
+var SystemdAttack AttackType = systemdAttack{}
+
+// unitState is the state of a systemd unit, such as {"loaded", "active", "enabled"}.
+type unitState struct {
+	LoadState     string
+	ActiveState   string
+	UnitFileState string
+}
+
+// systemdManager is what the systemd attack needs from systemd, it is implemented over D-Bus.
+type systemdManager interface {
+	UnitState(ctx context.Context, unit string) (unitState, error)
+	StartUnit(ctx context.Context, unit string) error
+	StopUnit(ctx context.Context, unit string) error
+	RestartUnit(ctx context.Context, unit string) error
+	KillUnit(ctx context.Context, unit string, signal int) error
+	FreezeUnit(ctx context.Context, unit string) error
+	ThawUnit(ctx context.Context, unit string) error
+	MaskUnit(ctx context.Context, unit string, runtime bool) error
+	UnmaskUnit(ctx context.Context, unit string, runtime bool) error
+	EnableUnit(ctx context.Context, unit string) error
+	DisableUnit(ctx context.Context, unit string) error
+	Close()
+}
+
+// newSystemdManager connects to systemd, it is replaced in the tests.
+var newSystemdManager = func(ctx context.Context) (systemdManager, error) {
+	conn, err := sddbus.NewWithContext(ctx)
+	if err != nil {
+		return nil, errors.Annotate(err, "connect to systemd")
+	}
+	return &dbusSystemdManager{conn: conn}, nil
+}
+
+func (systemdAttack) Attack(options core.AttackConfig, _ Environment) error {
+	attack := options.(*core.SystemdCommand)
+
+	ctx, cancel := context.WithTimeout(context.Background(), systemdTimeout)
+	defer cancel()
+	manager, err := newSystemdManager(ctx)
+	if err != nil {
+		return err
+	}
+	defer manager.Close()
+
+	state, err := manager.UnitState(ctx, attack.Unit)
+	if err != nil {
+		return err
+	}
+	if state.LoadState == "not-found" {
+		return errors.Errorf("unit %s not found", attack.Unit)
+	}
+	attack.ActiveState, attack.UnitFileState = state.ActiveState, state.UnitFileState
+
+	switch attack.Action {
+	case core.SystemdStopAction:
+		err = manager.StopUnit(ctx, attack.Unit)
+	case core.SystemdRestartAction:
+		err = manager.RestartUnit(ctx, attack.Unit)
+	case core.SystemdKillAction:
+		err = manager.KillUnit(ctx, attack.Unit, attack.Signal)
+	case core.SystemdFreezeAction:
+		err = manager.FreezeUnit(ctx, attack.Unit)
+	case core.SystemdMaskAction:
+		if err = manager.MaskUnit(ctx, attack.Unit, attack.Runtime); err == nil {
+			err = manager.StopUnit(ctx, attack.Unit)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Info("attack systemd unit successfully", zap.String("unit", attack.Unit), zap.String("action", attack.Action),
+		zap.String("active state", state.ActiveState), zap.String("unit file state", state.UnitFileState))
+	return nil
+}
+
+func (systemdAttack) Plan(options core.AttackConfig, _ Environment) ([]string, error) {
+	attack := options.(*core.SystemdCommand)
+
+	var steps []string
+	switch attack.Action {
+	case core.SystemdStopAction, core.SystemdRestartAction:
+		steps = append(steps, fmt.Sprintf("%s unit %s", attack.Action, attack.Unit))
+	case core.SystemdKillAction:
+		steps = append(steps, fmt.Sprintf("send signal %d to all the processes of unit %s", attack.Signal, attack.Unit))
+	case core.SystemdFreezeAction:
+		steps = append(steps, fmt.Sprintf("freeze unit %s", attack.Unit))
+	case core.SystemdMaskAction:
+		steps = append(steps, fmt.Sprintf("mask unit %s", attack.Unit), fmt.Sprintf("stop unit %s", attack.Unit))
+	}
+	return append(steps, fmt.Sprintf("restore the active and enabled state of unit %s when recovering", attack.Unit)), nil
+}
+
+// Recover restores the unit to its active and enabled state before the attack.
+func (systemdAttack) Recover(exp core.Experiment, _ Environment) error {
+	config, err := exp.GetRequestCommand()
+	if err != nil {
+		return err
+	}
+	attack := config.(*core.SystemdCommand)
+
+	ctx, cancel := context.WithTimeout(context.Background(), systemdTimeout)
+	defer cancel()
+	manager, err := newSystemdManager(ctx)
+	if err != nil {
+		return err
+	}
+	defer manager.Close()
+
+	switch attack.Action {
+	case core.SystemdFreezeAction:
+		if err := manager.ThawUnit(ctx, attack.Unit); err != nil {
+			return err
+		}
+	case core.SystemdMaskAction:
+		// the unit masked before the attack is left masked
+		if attack.UnitFileState != "masked" && attack.UnitFileState != "masked-runtime" {
+			if err := manager.UnmaskUnit(ctx, attack.Unit, attack.Runtime); err != nil {
+				return err
+			}
+		}
+	}
+
+	state, err := manager.UnitState(ctx, attack.Unit)
+	if err != nil {
+		return err
+	}
+	switch {
+	case attack.UnitFileState == "enabled" && state.UnitFileState == "disabled":
+		err = manager.EnableUnit(ctx, attack.Unit)
+	case attack.UnitFileState == "disabled" && state.UnitFileState == "enabled":
+		err = manager.DisableUnit(ctx, attack.Unit)
+	}
+	if err != nil {
+		return err
+	}
+
+	// the unit restarted by systemd, such as with Restart=always, is left as it is
+	switch {
+	case attack.WasActive() && state.ActiveState != "active":
+		err = manager.StartUnit(ctx, attack.Unit)
+	case !attack.WasActive() && state.ActiveState == "active":
+		err = manager.StopUnit(ctx, attack.Unit)
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Info("recover systemd unit successfully", zap.String("unit", attack.Unit),
+		zap.String("active state", attack.ActiveState), zap.String("unit file state", attack.UnitFileState))
+	return nil
+}
+
+// dbusSystemdManager talks to systemd over D-Bus.
+type dbusSystemdManager struct {
+	conn *sddbus.Conn
+}
+
+func (m *dbusSystemdManager) UnitState(ctx context.Context, unit string) (unitState, error) {
+	props, err := m.conn.GetUnitPropertiesContext(ctx, unit)
+	if err != nil {
+		return unitState{}, errors.Annotatef(err, "get state of unit %s", unit)
+	}
+	state := unitState{}
+	state.LoadState, _ = props["LoadState"].(string)
+	state.ActiveState, _ = props["ActiveState"].(string)
+	state.UnitFileState, _ = props["UnitFileState"].(string)
+	return state, nil
+}
+
+func (m *dbusSystemdManager) StartUnit(ctx context.Context, unit string) error {
+	return waitJob(ctx, "start", unit, func(ch chan<- string) (int, error) {
+		return m.conn.StartUnitContext(ctx, unit, "replace", ch)
+	})
+}
+
+func (m *dbusSystemdManager) StopUnit(ctx context.Context, unit string) error {
+	return waitJob(ctx, "stop", unit, func(ch chan<- string) (int, error) {
+		return m.conn.StopUnitContext(ctx, unit, "replace", ch)
+	})
+}
+
+func (m *dbusSystemdManager) RestartUnit(ctx context.Context, unit string) error {
+	return waitJob(ctx, "restart", unit, func(ch chan<- string) (int, error) {
+		return m.conn.RestartUnitContext(ctx, unit, "replace", ch)
+	})
+}
+
+func (m *dbusSystemdManager) KillUnit(ctx context.Context, unit string, signal int) error {
+	if err := m.conn.KillUnitWithTarget(ctx, unit, sddbus.All, int32(signal)); err != nil {
+		return errors.Annotatef(err, "kill unit %s with signal %d", unit, signal)
+	}
+	return nil
+}
+
+func (m *dbusSystemdManager) FreezeUnit(ctx context.Context, unit string) error {
+	return callManager(ctx, "FreezeUnit", unit)
+}
+
+func (m *dbusSystemdManager) ThawUnit(ctx context.Context, unit string) error {
+	return callManager(ctx, "ThawUnit", unit)
+}
+
+func (m *dbusSystemdManager) MaskUnit(ctx context.Context, unit string, runtime bool) error {
+	if _, err := m.conn.MaskUnitFilesContext(ctx, []string{unit}, runtime, false); err != nil {
+		return errors.Annotatef(err, "mask unit %s", unit)
+	}
+	return m.reload(ctx)
+}
+
+func (m *dbusSystemdManager) UnmaskUnit(ctx context.Context, unit string, runtime bool) error {
+	if _, err := m.conn.UnmaskUnitFilesContext(ctx, []string{unit}, runtime); err != nil {
+		return errors.Annotatef(err, "unmask unit %s", unit)
+	}
+	return m.reload(ctx)
+}
+
+func (m *dbusSystemdManager) EnableUnit(ctx context.Context, unit string) error {
+	if _, _, err := m.conn.EnableUnitFilesContext(ctx, []string{unit}, false, false); err != nil {
+		return errors.Annotatef(err, "enable unit %s", unit)
+	}
+	return m.reload(ctx)
+}
+
+func (m *dbusSystemdManager) DisableUnit(ctx context.Context, unit string) error {
+	if _, err := m.conn.DisableUnitFilesContext(ctx, []string{unit}, false); err != nil {
+		return errors.Annotatef(err, "disable unit %s", unit)
+	}
+	return m.reload(ctx)
+}
+
+func (m *dbusSystemdManager) reload(ctx context.Context) error {
+	return errors.Annotate(m.conn.ReloadContext(ctx), "reload systemd")
+}
+
+func (m *dbusSystemdManager) Close() {
+	m.conn.Close()
+}
+
+// waitJob starts the job of the unit and waits for its result.
+func waitJob(ctx context.Context, name, unit string, start func(ch chan<- string) (int, error)) error {
+	ch := make(chan string, 1)
+	if _, err := start(ch); err != nil {
+		return errors.Annotatef(err, "%s unit %s", name, unit)
+	}
+	select {
+	case result := <-ch:
+		if result != "done" {
+			return errors.Errorf("%s unit %s: job is %s", name, unit, result)
+		}
+		return nil
+	case <-ctx.Done():
+		return errors.Errorf("%s unit %s: %v", name, unit, ctx.Err())
+	}
+}
+
+// callManager calls the method of the systemd manager which is not supported by go-systemd, such as FreezeUnit.
+func callManager(ctx context.Context, method, unit string) error {
+	conn, err := dbus.ConnectSystemBus()
+	if err != nil {
+		return errors.Annotate(err, "connect to system bus")
+	}
+	defer conn.Close()
+
+	obj := conn.Object("org.freedesktop.systemd1", "/org/freedesktop/systemd1")
+	if err := obj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager."+method, 0, unit).Err; err != nil {
+		return errors.Annotatef(err, "%s %s", method, unit)
+	}
+	return nil
+}
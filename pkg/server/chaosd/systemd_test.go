@@ -0,0 +1,158 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+)
+
+// fakeSystemdManager changes the states of the units like systemd, and records the calls.
+type fakeSystemdManager struct {
+	units map[string]*unitState
+	calls []string
+}
+
+func (m *fakeSystemdManager) UnitState(_ context.Context, unit string) (unitState, error) {
+	if state, ok := m.units[unit]; ok {
+		return *state, nil
+	}
+	return unitState{LoadState: "not-found", ActiveState: "inactive"}, nil
+}
+
+func (m *fakeSystemdManager) set(call, unit string, change func(state *unitState)) error {
+	m.calls = append(m.calls, call+" "+unit)
+	change(m.units[unit])
+	return nil
+}
+
+func (m *fakeSystemdManager) StartUnit(_ context.Context, unit string) error {
+	return m.set("start", unit, func(state *unitState) {
+		if state.UnitFileState != "masked" {
+			state.ActiveState = "active"
+		}
+	})
+}
+
+func (m *fakeSystemdManager) StopUnit(_ context.Context, unit string) error {
+	return m.set("stop", unit, func(state *unitState) { state.ActiveState = "inactive" })
+}
+
+func (m *fakeSystemdManager) RestartUnit(_ context.Context, unit string) error {
+	return m.set("restart", unit, func(state *unitState) { state.ActiveState = "active" })
+}
+
+func (m *fakeSystemdManager) KillUnit(_ context.Context, unit string, signal int) error {
+	return m.set(fmt.Sprintf("kill %d", signal), unit, func(state *unitState) { state.ActiveState = "failed" })
+}
+
+func (m *fakeSystemdManager) FreezeUnit(_ context.Context, unit string) error {
+	return m.set("freeze", unit, func(*unitState) {})
+}
+
+func (m *fakeSystemdManager) ThawUnit(_ context.Context, unit string) error {
+	return m.set("thaw", unit, func(*unitState) {})
+}
+
+func (m *fakeSystemdManager) MaskUnit(_ context.Context, unit string, _ bool) error {
+	return m.set("mask", unit, func(state *unitState) { state.UnitFileState = "masked" })
+}
+
+func (m *fakeSystemdManager) UnmaskUnit(_ context.Context, unit string, _ bool) error {
+	return m.set("unmask", unit, func(state *unitState) { state.UnitFileState = "disabled" })
+}
+
+func (m *fakeSystemdManager) EnableUnit(_ context.Context, unit string) error {
+	return m.set("enable", unit, func(state *unitState) { state.UnitFileState = "enabled" })
+}
+
+func (m *fakeSystemdManager) DisableUnit(_ context.Context, unit string) error {
+	return m.set("disable", unit, func(state *unitState) { state.UnitFileState = "disabled" })
+}
+
+func (m *fakeSystemdManager) Close() {}
+
+func TestSystemdAttack(t *testing.T) {
+	manager := &fakeSystemdManager{}
+	newManager := newSystemdManager
+	newSystemdManager = func(context.Context) (systemdManager, error) { return manager, nil }
+	defer func() { newSystemdManager = newManager }()
+
+	for _, test := range []struct {
+		action  string
+		state   unitState
+		attack  []string
+		recover []string
+	}{
+		{
+			action:  core.SystemdStopAction,
+			state:   unitState{"loaded", "active", "enabled"},
+			attack:  []string{"stop nginx.service"},
+			recover: []string{"start nginx.service"},
+		},
+		{
+			// the unit started by the attack is stopped again
+			action:  core.SystemdRestartAction,
+			state:   unitState{"loaded", "inactive", "disabled"},
+			attack:  []string{"restart nginx.service"},
+			recover: []string{"stop nginx.service"},
+		},
+		{
+			action:  core.SystemdKillAction,
+			state:   unitState{"loaded", "active", "enabled"},
+			attack:  []string{"kill 9 nginx.service"},
+			recover: []string{"start nginx.service"},
+		},
+		{
+			action:  core.SystemdFreezeAction,
+			state:   unitState{"loaded", "active", "static"},
+			attack:  []string{"freeze nginx.service"},
+			recover: []string{"thaw nginx.service"},
+		},
+		{
+			// the enabled state is restored after the unit is unmasked
+			action:  core.SystemdMaskAction,
+			state:   unitState{"loaded", "active", "enabled"},
+			attack:  []string{"mask nginx.service", "stop nginx.service"},
+			recover: []string{"unmask nginx.service", "enable nginx.service", "start nginx.service"},
+		},
+	} {
+		state := test.state
+		manager.units = map[string]*unitState{"nginx.service": &state}
+		manager.calls = nil
+
+		options := core.NewSystemdCommand()
+		options.Action, options.Unit = test.action, "nginx"
+		options.CompleteDefaults()
+		assert.NoError(t, options.Validate())
+		assert.NoError(t, SystemdAttack.Attack(options, Environment{}))
+		assert.Equal(t, test.attack, manager.calls, test.action)
+		assert.Equal(t, test.state.ActiveState, options.ActiveState)
+
+		manager.calls = nil
+		exp := core.Experiment{Kind: core.SystemdAttack, RecoverCommand: options.RecoverData()}
+		assert.NoError(t, SystemdAttack.Recover(exp, Environment{}))
+		assert.Equal(t, test.recover, manager.calls, test.action)
+		assert.Equal(t, test.state, state, test.action)
+	}
+
+	options := core.NewSystemdCommand()
+	options.Action, options.Unit = core.SystemdStopAction, "missing.service"
+	assert.Error(t, SystemdAttack.Attack(options, Environment{}))
+}
@@ -0,0 +1,266 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/joomcode/errorx"
+	"github.com/pingcap/log"
+	perr "github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+)
+
+// AttackUpdater is implemented by the attack types which can change a running attack in place.
+// The other attack types are updated by recovering the attack and executing it again.
+type AttackUpdater interface {
+	// Update changes the running attack of the experiment to the new options.
+	Update(exp core.Experiment, options core.AttackConfig, env Environment) error
+}
+
+// UpdateAttack changes the parameters of a live experiment. The patch is a JSON object which
+// is merged into the stored config of the experiment, such as {"latency": "100ms"}.
+// A running attack is changed at once, a scheduled experiment uses the new config from its next run
+// and a paused experiment uses it when it is resumed. The change is recorded in the history of the experiment.
+func (s *Server) UpdateAttack(uid string, patch []byte) (*core.Experiment, error) {
+	exp, err := s.expStore.FindByUid(context.Background(), uid)
+	if err != nil {
+		return nil, err
+	}
+
+	switch exp.Status {
+	case core.Success, core.Scheduled, core.Paused:
+	default:
+		return nil, perr.Errorf("can not update %s experiment", exp.Status)
+	}
+	if err := s.checkHalted(); err != nil {
+		return nil, err
+	}
+
+	oldOptions, err := exp.GetRequestCommand()
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := mergeAttackConfig(exp, patch)
+	if err != nil {
+		return nil, err
+	}
+	if (len(oldOptions.Cron()) > 0) != (len(options.Cron()) > 0) {
+		return nil, core.ErrAttackConfigValidation.New("the schedule of an experiment can not be added or removed")
+	}
+	if err := core.ValidateConcurrencyPolicy(options); err != nil {
+		return nil, core.ErrAttackConfigValidation.Wrap(err, "attack config validation failed")
+	}
+
+	attackType, err := getAttackType(exp.Kind)
+	if err != nil {
+		return nil, err
+	}
+
+	before := exp.RecoverCommand
+	deadline := getUpdatedDeadline(exp.Deadline, oldOptions, options)
+	deadlineChanged := !equalDeadline(exp.Deadline, deadline)
+	if err := s.updateExperiment(attackType, exp, options, deadline); err != nil {
+		return nil, err
+	}
+
+	if len(options.Cron()) > 0 {
+		if err := s.reschedule(exp); err != nil {
+			return nil, err
+		}
+	} else if exp.Status == core.Success && deadlineChanged {
+		s.stopDeadlineTimer(uid)
+		if err := s.armDeadline(exp); err != nil {
+			log.Error("failed to arm the deadline of experiment, it will not be recovered automatically",
+				zap.String("uid", uid), zap.Error(err))
+		}
+	}
+
+	if err := s.History.Add(context.Background(), &core.ExperimentHistory{
+		ExperimentUID: uid,
+		Event:         core.HistoryUpdated,
+		Before:        before,
+		After:         exp.RecoverCommand,
+	}); err != nil {
+		log.Error("failed to record the history of experiment", zap.String("uid", uid), zap.Error(err))
+	}
+	return exp, nil
+}
+
+// updateExperiment checks the new config against the policy and the other experiments, then changes the running attack
+// and stores the new config. It holds policyLock like createExperiment, so that no conflicting attack is created meanwhile.
+func (s *Server) updateExperiment(attackType AttackType, exp *core.Experiment, options core.AttackConfig, deadline *time.Time) error {
+	s.policyLock.Lock()
+	defer s.policyLock.Unlock()
+
+	if err := s.checkPolicy(options); err != nil {
+		return err
+	}
+	if err := s.checkConflicts(options, exp.Uid); err != nil {
+		return err
+	}
+
+	if exp.Status == core.Success && len(options.Cron()) == 0 {
+		if err := s.updateRunningAttack(attackType, exp, options); err != nil {
+			return err
+		}
+	}
+
+	exp.Action = options.String()
+	exp.RecoverCommand = options.RecoverData()
+	exp.Deadline = deadline
+	exp.Labels = options.GetLabels()
+	exp.Annotations = options.GetAnnotations()
+	return perr.WithStack(s.expStore.Set(context.Background(), exp))
+}
+
+func (s *Server) updateRunningAttack(attackType AttackType, exp *core.Experiment, options core.AttackConfig) error {
+	env := s.newEnvironment(exp.Uid)
+
+	var err error
+	if updater, ok := attackType.(AttackUpdater); ok {
+		err = updater.Update(*exp, options, env)
+	} else {
+		if err := attackType.Recover(*exp, env); err != nil {
+			if errorx.IsOfType(err, core.ErrNonRecoverableAttack) {
+				return perr.Errorf("can not update non-recoverable %s experiment", exp.Kind)
+			}
+			return perr.WithMessagef(err, "Update experiment %s failed", exp.Uid)
+		}
+		err = attackType.Attack(options, env)
+	}
+
+	if err != nil {
+		s.stopDeadlineTimer(exp.Uid)
+		if err := s.expStore.Update(context.Background(), exp.Uid, core.Error, err.Error(), options.RecoverData()); err != nil {
+			log.Error("failed to update experiment", zap.Error(err))
+		}
+		return perr.WithMessagef(err, "Update experiment %s failed", exp.Uid)
+	}
+	return nil
+}
+
+// reschedule replaces the cron job of the experiment with a new one built from its stored config,
+// the runs waiting for recovery are moved to the new cron job. Only chaosd server keeps the cron jobs,
+// the other processes leave the new config to be loaded when chaosd server restores the experiment.
+func (s *Server) reschedule(exp *core.Experiment) error {
+	if !s.serving {
+		return nil
+	}
+
+	// read the experiment again, so that the funcs are built from the new config
+	exp, err := s.expStore.FindByUid(context.Background(), exp.Uid)
+	if err != nil {
+		return perr.WithStack(err)
+	}
+
+	options, err := exp.GetRequestCommand()
+	if err != nil {
+		return err
+	}
+	attackFunc, recoverFunc, err := s.buildCronJobFuncs(exp)
+	if err != nil {
+		return err
+	}
+
+	if err := s.Cron.Reschedule(exp, options.GetSchedulerConfig().CronSpec(), attackFunc, recoverFunc); err != nil {
+		return perr.WithMessage(err, "failed to reschedule task")
+	}
+	if exp.Status == core.Paused {
+		if err := s.Cron.Pause(exp.ID); err != nil {
+			return perr.WithMessage(err, "failed to pause scheduled task")
+		}
+	}
+	return nil
+}
+
+// mergeAttackConfig merges the patch into the stored config of the experiment
+// and returns the validated config.
+func mergeAttackConfig(exp *core.Experiment, patch []byte) (core.AttackConfig, error) {
+	attackConfig := core.GetAttackByKind(exp.Kind)
+	if attackConfig == nil {
+		return nil, perr.Errorf("chaos experiment kind %s not found", exp.Kind)
+	}
+	options := *attackConfig
+	if err := json.Unmarshal([]byte(exp.RecoverCommand), options); err != nil {
+		return nil, perr.WithStack(err)
+	}
+
+	// the fields which are not stored, such as the size of a disk attack, are rejected,
+	// the values derived from them can't be computed again
+	uid := options.GetUID()
+	decoder := json.NewDecoder(bytes.NewReader(patch))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(options); err != nil {
+		return nil, core.ErrAttackConfigValidation.Wrap(err, "invalid patch of attack config")
+	}
+	if options.GetUID() != uid {
+		return nil, core.ErrAttackConfigValidation.New("the uid of an experiment can not be changed")
+	}
+	if options.IsDryRun() {
+		return nil, core.ErrAttackConfigValidation.New("dry run is not supported when updating an experiment")
+	}
+
+	options.CompleteDefaults()
+	if preProcessor, ok := options.(attackPreProcessor); ok {
+		if err := preProcessor.PreProcess(); err != nil {
+			return nil, core.ErrAttackConfigValidation.Wrap(err, "attack config validation failed")
+		}
+	}
+	if err := options.Validate(); err != nil {
+		return nil, core.ErrAttackConfigValidation.Wrap(err, "attack config validation failed")
+	}
+	return options, nil
+}
+
+// attackPreProcessor is implemented by the configs which derive some values from the other fields,
+// such as the deltas of the clock attack, the values are computed again after the config is patched.
+type attackPreProcessor interface {
+	PreProcess() error
+}
+
+// getUpdatedDeadline moves the deadline of the experiment by the change of its duration,
+// so that the time the experiment has been running still counts.
+func getUpdatedDeadline(deadline *time.Time, oldOptions, options core.AttackConfig) *time.Time {
+	if deadline == nil {
+		return getExperimentDeadline(options)
+	}
+
+	newDeadline := getExperimentDeadline(options)
+	if newDeadline == nil {
+		return nil
+	}
+
+	duration, _ := options.ScheduleDuration()
+	oldDuration, err := oldOptions.ScheduleDuration()
+	if err != nil || oldDuration == nil {
+		return newDeadline
+	}
+
+	moved := deadline.Add(*duration - *oldDuration)
+	return &moved
+}
+
+func equalDeadline(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
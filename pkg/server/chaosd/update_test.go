@@ -0,0 +1,113 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+)
+
+func Test_mergeAttackConfig(t *testing.T) {
+	network := core.NewNetworkCommand()
+	network.Action = core.NetworkDelayAction
+	network.Device = "eth0"
+	network.IPAddress = "10.0.0.1"
+	network.Latency = "10ms"
+	network.UID = "uid"
+	network.CompleteDefaults()
+	exp := &core.Experiment{
+		Uid:            "uid",
+		Kind:           core.NetworkAttack,
+		RecoverCommand: network.RecoverData(),
+	}
+
+	options, err := mergeAttackConfig(exp, []byte(`{"latency": "100ms"}`))
+	assert.NoError(t, err)
+	attack := options.(*core.NetworkCommand)
+	assert.Equal(t, "100ms", attack.Latency)
+	assert.Equal(t, "eth0", attack.Device)
+	assert.True(t, sameNetworkTraffic(network, attack))
+
+	options, err = mergeAttackConfig(exp, []byte(`{"ip-address": "10.0.0.2"}`))
+	assert.NoError(t, err)
+	assert.False(t, sameNetworkTraffic(network, options.(*core.NetworkCommand)))
+
+	for _, patch := range []string{
+		`{"uid": "another"}`,
+		`{"latency": "ten"}`,
+		`{"dry_run": true}`,
+		`not json`,
+		`{"unknown": 1}`,
+	} {
+		_, err = mergeAttackConfig(exp, []byte(patch))
+		assert.Error(t, err, patch)
+		assert.True(t, errorx.IsOfType(err, core.ErrAttackConfigValidation), patch)
+	}
+}
+
+func Test_mergeAttackConfigDerivedValues(t *testing.T) {
+	clock := core.NewClockOption()
+	clock.Pid = os.Getpid()
+	clock.TimeOffset = "10s"
+	clock.CompleteDefaults()
+	assert.NoError(t, clock.PreProcess())
+	exp := &core.Experiment{Uid: "clock", Kind: core.ClockAttack, RecoverCommand: clock.RecoverData()}
+
+	options, err := mergeAttackConfig(exp, []byte(`{"time-offset": "-1.5s"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-1), options.(*core.ClockOption).SecDelta)
+	assert.Equal(t, int64(-500*time.Millisecond), options.(*core.ClockOption).NsecDelta)
+
+	_, err = mergeAttackConfig(exp, []byte(`{"time-offset": "ten"}`))
+	assert.True(t, errorx.IsOfType(err, core.ErrAttackConfigValidation))
+
+	// the size and the percent of a disk attack are not stored, the dd options derived from them can't be updated
+	disk := core.NewDiskOptionForServer()
+	disk.Action = core.DiskFillAction
+	disk.Size = "1M"
+	disk.Path = filepath.Join(t.TempDir(), "fill")
+	disk.CompleteDefaults()
+	diskConfig, err := disk.PreProcess()
+	assert.NoError(t, err)
+	exp = &core.Experiment{Uid: "disk", Kind: core.DiskServerAttack, RecoverCommand: diskConfig.RecoverData()}
+	for _, patch := range []string{`{"size": "2M"}`, `{"percent": "10"}`} {
+		_, err = mergeAttackConfig(exp, []byte(patch))
+		assert.True(t, errorx.IsOfType(err, core.ErrAttackConfigValidation), patch)
+	}
+}
+
+func Test_getUpdatedDeadline(t *testing.T) {
+	newOptions := func(duration string) core.AttackConfig {
+		options := core.NewProcessCommand()
+		options.Duration = duration
+		return options
+	}
+
+	deadline := time.Now().Add(time.Minute)
+	moved := getUpdatedDeadline(&deadline, newOptions("2m"), newOptions("5m"))
+	assert.Equal(t, deadline.Add(3*time.Minute), *moved)
+
+	assert.Nil(t, getUpdatedDeadline(&deadline, newOptions("2m"), newOptions("")))
+
+	added := getUpdatedDeadline(nil, newOptions(""), newOptions("1m"))
+	assert.NotNil(t, added)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), *added, time.Second)
+}
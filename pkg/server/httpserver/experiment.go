@@ -20,6 +20,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/chaos-mesh/chaosd/pkg/core"
+	"github.com/chaos-mesh/chaosd/pkg/server/utils"
 )
 
 func (s *HttpServer) listExperiments(c *gin.Context) {
@@ -35,6 +36,21 @@ func (s *HttpServer) listExperiments(c *gin.Context) {
 		_ = c.AbortWithError(http.StatusInternalServerError, err)
 		return
 	}
+
+	if selector, ok := c.GetQuery("selector"); ok {
+		labelSelector, err := core.ParseSelector(selector)
+		if err != nil {
+			_ = c.AbortWithError(http.StatusBadRequest, utils.ErrInvalidRequest.WrapWithNoMessage(err))
+			return
+		}
+		selected := make([]*core.Experiment, 0, len(chaosList))
+		for _, exp := range chaosList {
+			if exp.Labels.Matches(labelSelector) {
+				selected = append(selected, exp)
+			}
+		}
+		chaosList = selected
+	}
 	c.JSON(http.StatusOK, chaosList)
 }
 
@@ -47,3 +63,39 @@ func (s *HttpServer) listExperimentRuns(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, runsList)
 }
+
+func (s *HttpServer) listExperimentHistory(c *gin.Context) {
+	uid := c.Param("uid")
+	histories, err := s.chaos.History.ListByExperimentUID(context.Background(), uid)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, histories)
+}
+
+// @Summary Update experiment.
+// @Description Update the parameters of a live experiment, the request body is merged into the config of the experiment.
+// @Tags experiments
+// @Produce json
+// @Param uid path string true "uid"
+// @Param request body object true "the changed fields of the attack config"
+// @Success 200 {object} core.Experiment
+// @Failure 400 {object} utils.APIError
+// @Failure 404 {object} utils.APIError
+// @Failure 500 {object} utils.APIError
+// @Router /api/experiments/{uid} [patch]
+func (s *HttpServer) updateExperiment(c *gin.Context) {
+	patch, err := c.GetRawData()
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, utils.ErrInvalidRequest.WrapWithNoMessage(err))
+		return
+	}
+
+	exp, err := s.chaos.UpdateAttack(c.Param("uid"), patch)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, exp)
+}
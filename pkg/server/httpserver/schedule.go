@@ -0,0 +1,114 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary List schedules.
+// @Description List the scheduled experiments with the next and previous fire times and the last runs.
+// @Tags schedules
+// @Produce json
+// @Success 200 {array} chaosd.ScheduleInfo
+// @Failure 500 {object} utils.APIError
+// @Router /api/schedules [get]
+func (s *HttpServer) listSchedules(c *gin.Context) {
+	schedules, err := s.chaos.ListSchedules()
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, schedules)
+}
+
+// @Summary Describe schedule.
+// @Description Describe the schedule of an experiment.
+// @Tags schedules
+// @Produce json
+// @Param uid path string true "uid"
+// @Success 200 {object} chaosd.ScheduleInfo
+// @Failure 400 {object} utils.APIError
+// @Failure 404 {object} utils.APIError
+// @Failure 500 {object} utils.APIError
+// @Router /api/schedules/{uid} [get]
+func (s *HttpServer) describeSchedule(c *gin.Context) {
+	schedule, err := s.chaos.DescribeSchedule(c.Param("uid"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, schedule)
+}
+
+// @Summary Suspend schedule.
+// @Description Suspend the schedule of an experiment, no new run starts until it is unsuspended.
+// @Description The run in progress is still recovered at the end of its duration.
+// @Tags schedules
+// @Produce json
+// @Param uid path string true "uid"
+// @Success 200 {object} chaosd.ScheduleInfo
+// @Failure 400 {object} utils.APIError
+// @Failure 404 {object} utils.APIError
+// @Failure 500 {object} utils.APIError
+// @Router /api/schedules/{uid}/suspend [post]
+func (s *HttpServer) suspendSchedule(c *gin.Context) {
+	s.setScheduleSuspended(c, true)
+}
+
+// @Summary Unsuspend schedule.
+// @Description Unsuspend the schedule of an experiment, the runs start on the schedule again.
+// @Tags schedules
+// @Produce json
+// @Param uid path string true "uid"
+// @Success 200 {object} chaosd.ScheduleInfo
+// @Failure 400 {object} utils.APIError
+// @Failure 404 {object} utils.APIError
+// @Failure 500 {object} utils.APIError
+// @Router /api/schedules/{uid}/unsuspend [post]
+func (s *HttpServer) unsuspendSchedule(c *gin.Context) {
+	s.setScheduleSuspended(c, false)
+}
+
+func (s *HttpServer) setScheduleSuspended(c *gin.Context, suspend bool) {
+	schedule, err := s.chaos.SuspendSchedule(c.Param("uid"), suspend)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, schedule)
+}
+
+// @Summary Trigger schedule.
+// @Description Start an out-of-band run of a scheduled experiment at once, regardless of its windows, jitter and suspension.
+// @Description The concurrency policy of the schedule is still applied.
+// @Tags schedules
+// @Produce json
+// @Param uid path string true "uid"
+// @Success 200 {object} core.ExperimentRun
+// @Failure 400 {object} utils.APIError
+// @Failure 404 {object} utils.APIError
+// @Failure 409 {object} utils.APIError
+// @Failure 500 {object} utils.APIError
+// @Router /api/schedules/{uid}/trigger [post]
+func (s *HttpServer) triggerSchedule(c *gin.Context) {
+	run, err := s.chaos.TriggerSchedule(c.Param("uid"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, run)
+}
@@ -15,6 +15,7 @@ package httpserver
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joomcode/errorx"
@@ -53,6 +54,33 @@ func Register(s *HttpServer, scheduler scheduler.Scheduler) {
 		return
 	}
 
+	// the network rules are reconciled and the experiments are restored before serving,
+	// so that no request sees or changes them halfway
+	s.chaos.StartServing()
+	issues, err := s.chaos.DiagnoseNetworkRules(s.conf.FixNetworkRules)
+	if err != nil {
+		log.Error("failed to diagnose the network rules", zap.Error(err))
+	}
+	for _, issue := range issues {
+		msg := "found an inconsistent network rule, run `chaosd doctor --fix` to fix it"
+		if issue.Fixed {
+			msg = "fixed an inconsistent network rule"
+		}
+		log.Warn(msg, zap.String("type", issue.Type), zap.String("name", issue.Name),
+			zap.String("experiment", issue.Experiment), zap.String("problem", issue.Problem))
+	}
+	if err := s.chaos.RestoreDeadlines(); err != nil {
+		log.Error("failed to restore the deadlines of experiments", zap.Error(err))
+	}
+	if err := s.chaos.RestoreCrashLoops(); err != nil {
+		log.Error("failed to restore the crash-loops of experiments", zap.Error(err))
+	}
+	if err := s.chaos.RestoreScheduledAttacks(); err != nil {
+		log.Error("failed to restore the scheduled experiments", zap.Error(err))
+	}
+	s.chaos.WatchGuardrails()
+	scheduler.Start()
+
 	go func() {
 		if err := s.startHttpServer(); err != nil {
 			log.Fatal("failed to start HTTP server", zap.Error(err))
@@ -63,7 +91,6 @@ func Register(s *HttpServer, scheduler scheduler.Scheduler) {
 			log.Fatal("failed to start HTTPS server", zap.Error(err))
 		}
 	}()
-	scheduler.Start()
 }
 
 func (s *HttpServer) startHttpServer() error {
@@ -86,24 +113,33 @@ func (s *HttpServer) handler(engine *gin.Engine) {
 
 	attack := api.Group("/attack")
 	{
-		attack.POST("/process", s.createProcessAttack)
-		attack.POST("/stress", s.createStressAttack)
-		attack.POST("/network", s.createNetworkAttack)
-		attack.POST("/disk", s.createDiskAttack)
-		attack.POST("/clock", s.createClockAttack)
-		attack.POST("/jvm", s.createJVMAttack)
-		attack.POST("/kafka", s.createKafkaAttack)
-		attack.POST("/vm", s.createVMAttack)
-		attack.POST("/redis", s.createRedisAttack)
-		attack.POST("/user_defined", s.createUserDefinedAttack)
+		for _, kind := range chaosd.GetAttackKinds() {
+			if kind.HTTP != nil {
+				attack.POST("/"+kind.HTTP.Path, s.createAttack(kind))
+			}
+		}
 
+		attack.DELETE("", s.recoverAttacks)
 		attack.DELETE("/:uid", s.recoverAttack)
+		attack.POST("/:uid/pause", s.pauseAttack)
+		attack.POST("/:uid/resume", s.resumeAttack)
 	}
 
 	experiments := api.Group("/experiments")
 	{
 		experiments.GET("/", s.listExperiments)
 		experiments.GET("/:uid/runs", s.listExperimentRuns)
+		experiments.GET("/:uid/history", s.listExperimentHistory)
+		experiments.PATCH("/:uid", s.updateExperiment)
+	}
+
+	schedules := api.Group("/schedules")
+	{
+		schedules.GET("", s.listSchedules)
+		schedules.GET("/:uid", s.describeSchedule)
+		schedules.POST("/:uid/suspend", s.suspendSchedule)
+		schedules.POST("/:uid/unsuspend", s.unsuspendSchedule)
+		schedules.POST("/:uid/trigger", s.triggerSchedule)
 	}
 }
 
@@ -113,379 +149,161 @@ func (s *HttpServer) systemHandler(engine *gin.Engine) {
 	{
 		system.GET("/health", s.healthcheck)
 		system.GET("/version", s.version)
+		system.GET("/halt", s.haltState)
+		system.POST("/halt", s.halt)
+		system.POST("/unhalt", s.unhalt)
 	}
 }
 
-// @Summary Create process attack.
-// @Description Create process attack.
-// @Tags attack
-// @Produce json
-// @Param request body core.ProcessCommand true "Request body"
-// @Success 200 {object} utils.Response
-// @Failure 400 {object} utils.APIError
-// @Failure 500 {object} utils.APIError
-// @Router /api/attack/process [post]
-func (s *HttpServer) createProcessAttack(c *gin.Context) {
-	attack := core.NewProcessCommand()
-	if err := c.ShouldBindJSON(attack); err != nil {
-		_ = c.AbortWithError(http.StatusBadRequest, utils.ErrInternalServer.WrapWithNoMessage(err))
-		return
-	}
-
-	attack.CompleteDefaults()
-	if err := attack.Validate(); err != nil {
-		err = core.ErrAttackConfigValidation.Wrap(err, "attack config validation failed")
-		handleError(c, err)
-		return
-	}
-
-	uid, err := s.chaos.ExecuteAttack(chaosd.ProcessAttack, attack, core.ServerMode)
-	if err != nil {
-		handleError(c, err)
-		return
-	}
-
-	c.JSON(http.StatusOK, utils.AttackSuccessResponse(uid))
-}
-
-// @Summary Create network attack.
-// @Description Create network attack.
+// @Summary Create attack.
+// @Description Create attack, the request body is the config of the attack kind, such as core.ProcessCommand.
+// @Description If dry_run is true in the request body, the attack is not executed and its plan is returned.
 // @Tags attack
 // @Produce json
-// @Param request body core.NetworkCommand true "Request body"
+// @Param kind path string true "the kind of attack, such as process, network and stress"
+// @Param request body object true "Request body"
 // @Success 200 {object} utils.Response
+// @Success 200 {object} chaosd.AttackPlan
 // @Failure 400 {object} utils.APIError
 // @Failure 500 {object} utils.APIError
-// @Router /api/attack/network [post]
-func (s *HttpServer) createNetworkAttack(c *gin.Context) {
-	attack := core.NewNetworkCommand()
-	if err := c.ShouldBindJSON(attack); err != nil {
-		_ = c.AbortWithError(http.StatusBadRequest, utils.ErrInternalServer.WrapWithNoMessage(err))
-		return
-	}
-
-	attack.CompleteDefaults()
-	if err := attack.Validate(); err != nil {
-		err = core.ErrAttackConfigValidation.Wrap(err, "attack config validation failed")
-		handleError(c, err)
-		return
-	}
-
-	uid, err := s.chaos.ExecuteAttack(chaosd.NetworkAttack, attack, core.ServerMode)
-	if err != nil {
-		handleError(c, err)
-		return
-	}
-
-	c.JSON(http.StatusOK, utils.AttackSuccessResponse(uid))
-}
-
-// @Summary Create stress attack.
-// @Description Create stress attack.
-// @Tags attack
-// @Produce json
-// @Param request body core.StressCommand true "Request body"
-// @Success 200 {object} utils.Response
-// @Failure 400 {object} utils.APIError
-// @Failure 500 {object} utils.APIError
-// @Router /api/attack/stress [post]
-func (s *HttpServer) createStressAttack(c *gin.Context) {
-	attack := core.NewStressCommand()
-	if err := c.ShouldBindJSON(attack); err != nil {
-		_ = c.AbortWithError(http.StatusBadRequest, utils.ErrInternalServer.WrapWithNoMessage(err))
-		return
-	}
-
-	attack.CompleteDefaults()
-	if err := attack.Validate(); err != nil {
-		err = core.ErrAttackConfigValidation.Wrap(err, "attack config validation failed")
-		handleError(c, err)
-		return
-	}
-
-	uid, err := s.chaos.ExecuteAttack(chaosd.StressAttack, attack, core.ServerMode)
-	if err != nil {
-		handleError(c, err)
-		return
-	}
-
-	c.JSON(http.StatusOK, utils.AttackSuccessResponse(uid))
-}
-
-// @Summary Create disk attack.
-// @Description Create disk attack.
-// @Tags attack
-// @Produce json
-// @Param request body core.DiskOption true "Request body"
-// @Success 200 {object} utils.Response
-// @Failure 400 {object} utils.APIError
-// @Failure 500 {object} utils.APIError
-// @Router /api/attack/disk [post]
-func (s *HttpServer) createDiskAttack(c *gin.Context) {
-	options := core.NewDiskOptionForServer()
-	if err := c.ShouldBindJSON(options); err != nil {
-		_ = c.AbortWithError(http.StatusBadRequest, utils.ErrInternalServer.WrapWithNoMessage(err))
-		return
-	}
-
-	options.CompleteDefaults()
-	attackConfig, err := options.PreProcess()
-	if err != nil {
-		err = core.ErrAttackConfigValidation.Wrap(err, "attack config validation failed")
-		handleError(c, err)
-		return
-	}
-
-	uid, err := s.chaos.ExecuteAttack(chaosd.DiskServerAttack, attackConfig, core.ServerMode)
-	if err != nil {
-		handleError(c, err)
-		return
-	}
-
-	c.JSON(http.StatusOK, utils.AttackSuccessResponse(uid))
-}
-
-// @Summary Create clock attack.
-// @Description Create clock attack.
-// @Tags attack
-// @Produce json
-// @Param request body core.ClockOption true "Request body"
-// @Success 200 {object} utils.Response
-// @Failure 400 {object} utils.APIError
-// @Failure 500 {object} utils.APIError
-// @Router /api/attack/clock [post]
-func (s *HttpServer) createClockAttack(c *gin.Context) {
-	options := core.NewClockOption()
-	if err := c.ShouldBindJSON(options); err != nil {
-		_ = c.AbortWithError(http.StatusBadRequest, utils.ErrInternalServer.WrapWithNoMessage(err))
-		return
-	}
-
-	options.CompleteDefaults()
-	err := options.PreProcess()
-	if err != nil {
-		err = core.ErrAttackConfigValidation.Wrap(err, "attack config validation failed")
-		handleError(c, err)
-		return
-	}
-	uid, err := s.chaos.ExecuteAttack(chaosd.ClockAttack, options, core.ServerMode)
-	if err != nil {
-		handleError(c, err)
-		return
-	}
-
-	c.JSON(http.StatusOK, utils.AttackSuccessResponse(uid))
-}
-
-// @Summary Create http attack.
-// @Description Create http attack.
-// @Tags attack
-// @Produce json
-// @Param request body core.HTTPAttackOption true "Request body"
-// @Success 200 {object} utils.Response
-// @Failure 400 {object} utils.APIError
-// @Failure 500 {object} utils.APIError
-// @Router /api/attack/http [post]
-func (s *HttpServer) createHTTPAttack(c *gin.Context) {
-	attack := core.NewHTTPAttackOption()
-	if err := c.ShouldBindJSON(attack); err != nil {
-		_ = c.AbortWithError(http.StatusBadRequest, utils.ErrInternalServer.WrapWithNoMessage(err))
-		return
-	}
-
-	attackConfig, err := attack.PreProcess()
-	if err != nil {
-		err = core.ErrAttackConfigValidation.Wrap(err, "attack config validation failed")
-		handleError(c, err)
-		return
-	}
-
-	uid, err := s.chaos.ExecuteAttack(chaosd.HostAttack, attackConfig, core.ServerMode)
-	if err != nil {
-		handleError(c, err)
-		return
-	}
-
-	c.JSON(http.StatusOK, utils.AttackSuccessResponse(uid))
-}
+// @Router /api/attack/{kind} [post]
+func (s *HttpServer) createAttack(kind *chaosd.AttackKind) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var decodeErr error
+		options, err := kind.HTTP.Bind(func(obj interface{}) error {
+			decodeErr = c.ShouldBindJSON(obj)
+			return decodeErr
+		})
+		if decodeErr != nil {
+			_ = c.AbortWithError(http.StatusBadRequest, utils.ErrInternalServer.WrapWithNoMessage(decodeErr))
+			return
+		}
+		if err != nil {
+			err = core.ErrAttackConfigValidation.Wrap(err, "attack config validation failed")
+			handleError(c, err)
+			return
+		}
 
-// @Summary Create JVM attack.
-// @Description Create JVM attack.
-// @Tags attack
-// @Produce json
-// @Param request body core.JVMCommand true "Request body"
-// @Success 200 {object} utils.Response
-// @Failure 400 {object} utils.APIError
-// @Failure 500 {object} utils.APIError
-// @Router /api/attack/jvm [post]
-func (s *HttpServer) createJVMAttack(c *gin.Context) {
-	options := core.NewJVMCommand()
-	if err := c.ShouldBindJSON(options); err != nil {
-		_ = c.AbortWithError(http.StatusBadRequest, utils.ErrInternalServer.WrapWithNoMessage(err))
-		return
-	}
+		if options.IsDryRun() {
+			plan, err := s.chaos.PlanAttack(kind.Attack, options)
+			if err != nil {
+				handleError(c, err)
+				return
+			}
+			c.JSON(http.StatusOK, plan)
+			return
+		}
 
-	options.CompleteDefaults()
-	if err := options.Validate(); err != nil {
-		err = core.ErrAttackConfigValidation.Wrap(err, "attack config validation failed")
-		handleError(c, err)
-		return
-	}
+		uid, err := s.chaos.ExecuteAttack(kind.Attack, options, core.ServerMode)
+		if err != nil {
+			handleError(c, err)
+			return
+		}
 
-	uid, err := s.chaos.ExecuteAttack(chaosd.JVMAttack, options, core.ServerMode)
-	if err != nil {
-		handleError(c, err)
-		return
+		c.JSON(http.StatusOK, utils.AttackSuccessResponse(uid))
 	}
-
-	c.JSON(http.StatusOK, utils.AttackSuccessResponse(uid))
 }
 
-// @Summary Create Kafka attack.
-// @Description Create Kafka attack.
+// @Summary Create recover attack.
+// @Description Create recover attack.
 // @Tags attack
 // @Produce json
-// @Param request body core.KafkaCommand true "Request body"
+// @Param uid path string true "uid"
 // @Success 200 {object} utils.Response
-// @Failure 400 {object} utils.APIError
 // @Failure 500 {object} utils.APIError
-// @Router /api/attack/kafka [post]
-func (s *HttpServer) createKafkaAttack(c *gin.Context) {
-	options := core.NewKafkaCommand()
-	if err := c.ShouldBindJSON(options); err != nil {
-		_ = c.AbortWithError(http.StatusBadRequest, utils.ErrInternalServer.WrapWithNoMessage(err))
-		return
-	}
-
-	options.CompleteDefaults()
-	if err := options.Validate(); err != nil {
-		err = core.ErrAttackConfigValidation.Wrap(err, "attack config validation failed")
-		handleError(c, err)
-		return
-	}
-
-	uid, err := s.chaos.ExecuteAttack(chaosd.KafkaAttack, options, core.ServerMode)
+// @Router /api/attack/{uid} [delete]
+func (s *HttpServer) recoverAttack(c *gin.Context) {
+	uid := c.Param("uid")
+	err := s.chaos.RecoverAttack(uid)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, utils.AttackSuccessResponse(uid))
+	c.JSON(http.StatusOK, utils.RecoverSuccessResponse(uid))
 }
 
-// @Summary Create VM attack.
-// @Description Create VM attack.
+// @Summary Recover attacks in bulk.
+// @Description Recover all the active attacks matching the query, at least one of the conditions or all=true is required.
+// @Description The attacks are recovered concurrently, the result of each attack is returned.
 // @Tags attack
 // @Produce json
-// @Param request body core.VMOption true "Request body"
-// @Success 200 {object} utils.Response
+// @Param all query bool false "recover all the active attacks"
+// @Param kind query string false "the kind of attacks, such as network"
+// @Param launch_mode query string false "the launch mode of attacks, svr or cmd"
+// @Param selector query string false "label selector of attacks, such as team=payments"
+// @Param workers query int false "the number of attacks recovered at the same time"
+// @Success 200 {array} chaosd.RecoverResult
 // @Failure 400 {object} utils.APIError
 // @Failure 500 {object} utils.APIError
-// @Router /api/attack/vm [post]
-func (s *HttpServer) createVMAttack(c *gin.Context) {
-	options := core.NewVMOption()
-	if err := c.ShouldBindJSON(options); err != nil {
-		_ = c.AbortWithError(http.StatusBadRequest, utils.ErrInternalServer.WrapWithNoMessage(err))
-		return
+// @Router /api/attack [delete]
+func (s *HttpServer) recoverAttacks(c *gin.Context) {
+	filter := chaosd.RecoverFilter{
+		Kind:       c.Query("kind"),
+		LaunchMode: c.Query("launch_mode"),
+		Selector:   c.Query("selector"),
 	}
-
-	options.CompleteDefaults()
-	if err := options.Validate(); err != nil {
-		err = core.ErrAttackConfigValidation.Wrap(err, "attack config validation failed")
-		handleError(c, err)
-		return
-	}
-
-	uid, err := s.chaos.ExecuteAttack(chaosd.VMAttack, options, core.ServerMode)
-	if err != nil {
-		handleError(c, err)
+	if c.Query("all") != "true" && len(filter.Kind) == 0 && len(filter.LaunchMode) == 0 && len(filter.Selector) == 0 {
+		_ = c.AbortWithError(http.StatusBadRequest, utils.ErrInvalidRequest.New("all=true or one of kind, launch_mode and selector is required"))
 		return
 	}
 
-	c.JSON(http.StatusOK, utils.AttackSuccessResponse(uid))
-}
-
-// @Summary Create redis attack.
-// @Description Create redis attack.
-// @Tags attack
-// @Produce json
-// @Param request body core.RedisCommand true "Request body"
-// @Success 200 {object} utils.Response
-// @Failure 400 {object} utils.APIError
-// @Failure 500 {object} utils.APIError
-// @Router /api/attack/redis [post]
-func (s *HttpServer) createRedisAttack(c *gin.Context) {
-	attack := core.NewRedisCommand()
-	if err := c.ShouldBindJSON(attack); err != nil {
-		_ = c.AbortWithError(http.StatusBadRequest, utils.ErrInternalServer.WrapWithNoMessage(err))
-		return
-	}
-
-	attack.CompleteDefaults()
-	if err := attack.Validate(); err != nil {
-		err = core.ErrAttackConfigValidation.Wrap(err, "attack config validation failed")
-		handleError(c, err)
-		return
+	workers := chaosd.DefaultRecoverWorkers
+	if value, ok := c.GetQuery("workers"); ok {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			_ = c.AbortWithError(http.StatusBadRequest, utils.ErrInvalidRequest.New("workers should be a positive integer"))
+			return
+		}
+		workers = n
 	}
 
-	uid, err := s.chaos.ExecuteAttack(chaosd.RedisAttack, attack, core.ServerMode)
+	results, err := s.chaos.RecoverExperiments(filter, workers)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
-
-	c.JSON(http.StatusOK, utils.AttackSuccessResponse(uid))
+	c.JSON(http.StatusOK, results)
 }
 
-// @Summary Create user defined attack.
-// @Description Create user defined attack.
+// @Summary Pause attack.
+// @Description Pause attack, the fault is lifted temporarily.
 // @Tags attack
 // @Produce json
-// @Param request body core.RedisCommand true "Request body"
+// @Param uid path string true "uid"
 // @Success 200 {object} utils.Response
-// @Failure 400 {object} utils.APIError
+// @Failure 404 {object} utils.APIError
 // @Failure 500 {object} utils.APIError
-// @Router /api/attack/user_defined [post]
-func (s *HttpServer) createUserDefinedAttack(c *gin.Context) {
-	attack := core.NewUserDefinedOption()
-	if err := c.ShouldBindJSON(attack); err != nil {
-		_ = c.AbortWithError(http.StatusBadRequest, utils.ErrInternalServer.WrapWithNoMessage(err))
-		return
-	}
-
-	attack.CompleteDefaults()
-	if err := attack.Validate(); err != nil {
-		err = core.ErrAttackConfigValidation.Wrap(err, "attack config validation failed")
-		handleError(c, err)
-		return
-	}
-
-	uid, err := s.chaos.ExecuteAttack(chaosd.UserDefinedAttack, attack, core.ServerMode)
+// @Router /api/attack/{uid}/pause [post]
+func (s *HttpServer) pauseAttack(c *gin.Context) {
+	uid := c.Param("uid")
+	err := s.chaos.PauseAttack(uid)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, utils.AttackSuccessResponse(uid))
+	c.JSON(http.StatusOK, utils.PauseSuccessResponse(uid))
 }
 
-// @Summary Create recover attack.
-// @Description Create recover attack.
+// @Summary Resume attack.
+// @Description Resume paused attack, the fault is injected again.
 // @Tags attack
 // @Produce json
 // @Param uid path string true "uid"
 // @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.APIError
 // @Failure 500 {object} utils.APIError
-// @Router /api/attack/{uid} [delete]
-func (s *HttpServer) recoverAttack(c *gin.Context) {
+// @Router /api/attack/{uid}/resume [post]
+func (s *HttpServer) resumeAttack(c *gin.Context) {
 	uid := c.Param("uid")
-	err := s.chaos.RecoverAttack(uid)
+	err := s.chaos.ResumeAttack(uid)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, utils.RecoverSuccessResponse(uid))
+	c.JSON(http.StatusOK, utils.ResumeSuccessResponse(uid))
 }
 
 func handleError(c *gin.Context, err error) {
@@ -495,6 +313,8 @@ func handleError(c *gin.Context, err error) {
 	}
 	if errorx.IsOfType(err, core.ErrAttackConfigValidation) {
 		_ = c.AbortWithError(http.StatusBadRequest, utils.ErrInvalidRequest.WrapWithNoMessage(err))
+	} else if errorx.IsOfType(err, core.ErrHalted) || errorx.IsOfType(err, core.ErrAttackConflict) {
+		_ = c.AbortWithError(http.StatusConflict, utils.ErrConflict.WrapWithNoMessage(err))
 	} else {
 		_ = c.AbortWithError(http.StatusInternalServerError, utils.ErrInternalServer.WrapWithNoMessage(err))
 	}
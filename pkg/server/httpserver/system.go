@@ -18,6 +18,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/chaos-mesh/chaosd/pkg/server/chaosd"
+	"github.com/chaos-mesh/chaosd/pkg/server/utils"
 	"github.com/chaos-mesh/chaosd/pkg/version"
 )
 
@@ -33,3 +35,68 @@ func (s *HttpServer) healthcheck(c *gin.Context) {
 func (s *HttpServer) version(c *gin.Context) {
 	c.JSON(http.StatusOK, version.Get())
 }
+
+type haltRequest struct {
+	Reason string `json:"reason"`
+}
+
+// @Summary Halt chaosd.
+// @Description Recover all the active experiments and reject new attacks until chaosd is unhalted.
+// @Tags system
+// @Produce json
+// @Param request body haltRequest false "Request body"
+// @Success 200 {object} chaosd.HaltResult
+// @Failure 400 {object} utils.APIError
+// @Failure 500 {object} utils.APIError
+// @Router /api/system/halt [post]
+func (s *HttpServer) halt(c *gin.Context) {
+	req := &haltRequest{}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(req); err != nil {
+			_ = c.AbortWithError(http.StatusBadRequest, utils.ErrInvalidRequest.WrapWithNoMessage(err))
+			return
+		}
+	}
+
+	results, err := s.chaos.Halt(req.Reason)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	state, err := s.chaos.GetHaltState()
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, chaosd.HaltResult{HaltState: state, Recovered: results})
+}
+
+// @Summary Unhalt chaosd.
+// @Description Allow new attacks to be executed again after chaosd is halted.
+// @Tags system
+// @Produce json
+// @Success 200 {object} chaosd.HaltResult
+// @Failure 500 {object} utils.APIError
+// @Router /api/system/unhalt [post]
+func (s *HttpServer) unhalt(c *gin.Context) {
+	if err := s.chaos.Unhalt(); err != nil {
+		handleError(c, err)
+		return
+	}
+	s.haltState(c)
+}
+
+// @Summary Get halt state of chaosd.
+// @Tags system
+// @Produce json
+// @Success 200 {object} chaosd.HaltResult
+// @Failure 500 {object} utils.APIError
+// @Router /api/system/halt [get]
+func (s *HttpServer) haltState(c *gin.Context) {
+	state, err := s.chaos.GetHaltState()
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, chaosd.HaltResult{HaltState: state})
+}
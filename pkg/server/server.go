@@ -25,6 +25,8 @@ import (
 
 	"github.com/chaos-mesh/chaos-mesh/pkg/chaosdaemon"
 
+	"github.com/chaos-mesh/chaosd/pkg/config"
+	"github.com/chaos-mesh/chaosd/pkg/core"
 	"github.com/chaos-mesh/chaosd/pkg/crclient"
 	"github.com/chaos-mesh/chaosd/pkg/scheduler"
 	"github.com/chaos-mesh/chaosd/pkg/server/chaosd"
@@ -34,6 +36,7 @@ import (
 var Module = fx.Options(
 	fx.Provide(
 		provideNIl,
+		providePolicy,
 		chaosd.NewServer,
 		httpserver.NewServer,
 		crclient.NewNodeCRClient,
@@ -43,6 +46,12 @@ var Module = fx.Options(
 	),
 )
 
+// providePolicy loads the policy file of chaosd, or the default policy file if no file is provided.
+// The policy is nil if neither exists.
+func providePolicy(conf *config.Config) (*core.Policy, error) {
+	return core.LoadPolicy(conf.PolicyFile)
+}
+
 func provideNIl() (prometheus.Registerer, logr.Logger) {
 	zapLogger, err := zap.NewDevelopment()
 	if err != nil {
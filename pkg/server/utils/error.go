@@ -28,6 +28,7 @@ var (
 	ErrInvalidRequest = ErrNS.NewType("invalid_request")
 	ErrInternalServer = ErrNS.NewType("internal_server_error")
 	ErrNotFound       = ErrNS.NewType("resource_not_found")
+	ErrConflict       = ErrNS.NewType("conflict")
 )
 
 type APIError struct {
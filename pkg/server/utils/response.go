@@ -34,3 +34,19 @@ func RecoverSuccessResponse(uid string) *Response {
 		UID:     uid,
 	}
 }
+
+func PauseSuccessResponse(uid string) *Response {
+	return &Response{
+		Status:  200,
+		Message: "attack pause successfully",
+		UID:     uid,
+	}
+}
+
+func ResumeSuccessResponse(uid string) *Response {
+	return &Response{
+		Status:  200,
+		Message: "attack resume successfully",
+		UID:     uid,
+	}
+}
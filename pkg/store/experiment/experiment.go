@@ -19,6 +19,7 @@ import (
 
 	perr "github.com/pkg/errors"
 	"gorm.io/gorm"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/chaos-mesh/chaosd/pkg/core"
 	"github.com/chaos-mesh/chaosd/pkg/store/dbstore"
@@ -83,12 +84,22 @@ func (e *experimentStore) ListByConditions(_ context.Context, conds *core.Search
 
 	db := e.db.Model(core.Experiment{})
 
-	if conds.Offset > 0 {
-		db = db.Offset(int(conds.Offset))
-	}
+	// the labels are stored as JSON, so the experiments are selected after they are queried,
+	// and the offset and limit are applied to the selected experiments.
+	var selector labels.Selector
+	if len(conds.Selector) > 0 {
+		var err error
+		if selector, err = core.ParseSelector(conds.Selector); err != nil {
+			return nil, err
+		}
+	} else {
+		if conds.Offset > 0 {
+			db = db.Offset(int(conds.Offset))
+		}
 
-	if conds.Limit > 0 {
-		db = db.Limit(int(conds.Limit))
+		if conds.Limit > 0 {
+			db = db.Limit(int(conds.Limit))
+		}
 	}
 
 	if !conds.All {
@@ -113,9 +124,30 @@ func (e *experimentStore) ListByConditions(_ context.Context, conds *core.Search
 		return nil, perr.WithStack(err)
 	}
 
+	if selector != nil {
+		exps = selectExperiments(exps, selector, conds.Offset, conds.Limit)
+	}
 	return exps, nil
 }
 
+func selectExperiments(exps []*core.Experiment, selector labels.Selector, offset, limit uint32) []*core.Experiment {
+	selected := make([]*core.Experiment, 0, len(exps))
+	for _, exp := range exps {
+		if exp.Labels.Matches(selector) {
+			selected = append(selected, exp)
+		}
+	}
+
+	if int(offset) >= len(selected) {
+		return selected[:0]
+	}
+	selected = selected[offset:]
+	if limit > 0 && int(limit) < len(selected) {
+		selected = selected[:limit]
+	}
+	return selected
+}
+
 func (e *experimentStore) FindByUid(_ context.Context, uid string) (*core.Experiment, error) {
 	exps := make([]*core.Experiment, 0)
 	if err := e.db.
@@ -0,0 +1,51 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package experiment
+
+import (
+	"context"
+	"errors"
+
+	perr "github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+	"github.com/chaos-mesh/chaosd/pkg/store/dbstore"
+)
+
+func NewHistoryStore(db *dbstore.DB) core.ExperimentHistoryStore {
+	db.AutoMigrate(&core.ExperimentHistory{})
+	return &experimentHistoryStore{db}
+}
+
+type experimentHistoryStore struct {
+	db *dbstore.DB
+}
+
+func (store *experimentHistoryStore) ListByExperimentUID(ctx context.Context, uid string) ([]*core.ExperimentHistory, error) {
+	histories := make([]*core.ExperimentHistory, 0)
+	if err := store.db.
+		Where("experiment_uid = ?", uid).
+		Order("created_at").
+		Find(&histories).
+		Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, perr.WithStack(err)
+	}
+
+	return histories, nil
+}
+
+func (store *experimentHistoryStore) Add(_ context.Context, history *core.ExperimentHistory) error {
+	return store.db.Model(core.ExperimentHistory{}).Create(history).Error
+}
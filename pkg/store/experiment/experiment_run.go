@@ -77,6 +77,19 @@ func (store *experimentRunStore) LatestRun(ctx context.Context, id uint) (*core.
 	return run, nil
 }
 
+func (store *experimentRunStore) ListByStatus(ctx context.Context, status string) ([]*core.ExperimentRun, error) {
+	runs := make([]*core.ExperimentRun, 0)
+	if err := store.db.
+		Preload("Experiment").
+		Find(&runs, "status = ?", status).
+		Order("start_at DESC").
+		Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, perr.WithStack(err)
+	}
+
+	return runs, nil
+}
+
 func (store *experimentRunStore) NewRun(_ context.Context, expRun *core.ExperimentRun) error {
 	return store.db.Model(core.ExperimentRun{}).Save(expRun).Error
 }
@@ -88,3 +101,11 @@ func (store *experimentRunStore) Update(_ context.Context, runUid string, status
 		Updates(core.Experiment{Status: status, Message: message}).
 		Error
 }
+
+func (store *experimentRunStore) SetRecoverCommand(_ context.Context, runUid string, recoverCommand string) error {
+	return store.db.
+		Model(core.ExperimentRun{}).
+		Where("uid = ?", runUid).
+		Update("recover_command", recoverCommand).
+		Error
+}
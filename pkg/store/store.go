@@ -19,6 +19,7 @@ import (
 	"github.com/chaos-mesh/chaosd/pkg/store/dbstore"
 	"github.com/chaos-mesh/chaosd/pkg/store/experiment"
 	"github.com/chaos-mesh/chaosd/pkg/store/network"
+	"github.com/chaos-mesh/chaosd/pkg/store/system"
 )
 
 var Module = fx.Options(
@@ -26,8 +27,10 @@ var Module = fx.Options(
 		dbstore.NewDBStore,
 		experiment.NewStore,
 		experiment.NewRunStore,
+		experiment.NewHistoryStore,
 		network.NewIPSetRuleStore,
 		network.NewIptablesRuleStore,
 		network.NewTCRuleStore,
+		system.NewHaltStateStore,
 	),
 )
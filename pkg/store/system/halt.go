@@ -0,0 +1,53 @@
+// Copyright 2023 Chaos Mesh Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package system
+
+import (
+	"context"
+	"errors"
+
+	perr "github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/chaos-mesh/chaosd/pkg/core"
+	"github.com/chaos-mesh/chaosd/pkg/store/dbstore"
+)
+
+// haltStateID is the ID of the only row of the halt state
+const haltStateID = 1
+
+func NewHaltStateStore(db *dbstore.DB) core.HaltStateStore {
+	db.AutoMigrate(&core.HaltState{})
+	return &haltStateStore{db}
+}
+
+type haltStateStore struct {
+	db *dbstore.DB
+}
+
+func (store *haltStateStore) Get(_ context.Context) (*core.HaltState, error) {
+	state := &core.HaltState{}
+	if err := store.db.Model(core.HaltState{}).First(state, haltStateID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &core.HaltState{}, nil
+		}
+		return nil, perr.WithStack(err)
+	}
+	return state, nil
+}
+
+func (store *haltStateStore) Set(_ context.Context, state *core.HaltState) error {
+	state.ID = haltStateID
+	return store.db.Model(core.HaltState{}).Save(state).Error
+}
@@ -14,8 +14,11 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
 	"os"
+
+	"github.com/chaos-mesh/chaosd/pkg/server/utils"
 )
 
 // http://tldp.org/LDP/abs/html/exitcodes.html
@@ -45,3 +48,13 @@ func ExitWithMsg(code int, msg string) {
 	fmt.Fprintln(os.Stderr, msg)
 	os.Exit(code)
 }
+
+// ExitOnAPIError exits with the error of calling chaosd server, it does nothing if both the errors are nil.
+func ExitOnAPIError(apiErr *utils.APIError, err error) {
+	if err != nil {
+		ExitWithError(ExitBadConnection, err)
+	}
+	if apiErr != nil {
+		ExitWithError(ExitError, errors.New(apiErr.Message))
+	}
+}
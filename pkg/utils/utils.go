@@ -19,6 +19,10 @@ import (
 
 var PrintFxLog bool
 
+// GlobalFlags are the flags of the root command set by the user, such as "--log-level=debug". They are passed to
+// the helper processes started by chaosd, so that the helpers behave the same way as the command starting them.
+var GlobalFlags []string
+
 // FxNewAppWithoutLog returns fx App without log
 func FxNewAppWithoutLog(opts ...fx.Option) *fx.App {
 	if !PrintFxLog {